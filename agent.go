@@ -187,6 +187,13 @@ func (a *Agent) Run(task string) AgentResult {
 		ctx, cancel = context.WithTimeout(ctx, a.timeout)
 		defer cancel()
 	}
+	ctx = withSettings(ctx, a.builder.currentSettings())
+	ctx, span := startSpan(ctx, "invoke_agent",
+		Attr("gen_ai.operation.name", "invoke_agent"), Attr("gen_ai.request.model", string(a.builder.model)))
+	defer func() {
+		span.SetAttributes(Attr("ai.agent.steps", len(result.Steps)))
+		endSpan(span, result.Error)
+	}()
 
 	// Build the ReAct system prompt
 	systemPrompt := a.buildReActPrompt()
@@ -220,11 +227,14 @@ func (a *Agent) Run(task string) AgentResult {
 
 		stepStart := time.Now()
 		currentStep := AgentStep{Number: step}
+		stepCtx, stepSpan := startSpan(ctx, "ai.agent.step", Attr("ai.agent.step", step))
 
 		// Send request with tools
 		builder.messages = messages
+		builder.ctx = stepCtx
 		resp, err := builder.SendWithTools()
 		if err != nil {
+			endSpan(stepSpan, err)
 			result.Error = err
 			result.Duration = time.Since(start)
 			return result
@@ -239,6 +249,7 @@ func (a *Agent) Run(task string) AgentResult {
 			currentStep.Thought = resp.Content
 			result.Answer = a.extractFinalAnswer(resp.Content)
 			result.Steps = append(result.Steps, currentStep)
+			endSpan(stepSpan, nil)
 			break
 		}
 
@@ -260,6 +271,7 @@ func (a *Agent) Run(task string) AgentResult {
 			if a.humanApproval != nil {
 				if !a.humanApproval(currentStep) {
 					result.Error = fmt.Errorf("action rejected by human: %s", tc.Function.Name)
+					endSpan(stepSpan, result.Error)
 					result.Duration = time.Since(start)
 					return result
 				}
@@ -270,7 +282,9 @@ func (a *Agent) Run(task string) AgentResult {
 			if !ok {
 				currentStep.Observation = fmt.Sprintf("Error: unknown tool %q", tc.Function.Name)
 			} else {
-				observation, err := handler(actionInput)
+				observation, err := traceTool(stepCtx, tc, func() (string, error) {
+					return handler(actionInput)
+				})
 				if err != nil {
 					currentStep.Observation = fmt.Sprintf("Error: %v", err)
 				} else {
@@ -304,8 +318,9 @@ func (a *Agent) Run(task string) AgentResult {
 		}
 
 		result.Steps = append(result.Steps, currentStep)
+		endSpan(stepSpan, nil)
 
-		if Debug {
+		if a.builder.currentSettings().debug {
 			a.printStep(currentStep)
 		}
 	}
@@ -318,8 +333,8 @@ func (a *Agent) Run(task string) AgentResult {
 		a.onComplete(result)
 	}
 
-	if Pretty && result.Answer != "" {
-		printPrettyResponse(a.builder.model, result.Answer)
+	if result.Answer != "" {
+		logAnswer(ctx, a.builder.model, "", result.Answer)
 	}
 
 	return result
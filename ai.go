@@ -15,12 +15,16 @@ var (
 	DefaultModel Model = ModelGPT5
 
 	// Debug enables printing of raw requests and responses to stdout.
+	// Request, response, retry and tool events go to Logger instead when
+	// one is set. It is the default for clients without WithDebug.
 	Debug = false
 
 	// Pretty enables colored output for debug logs.
+	// It is the default for clients without WithPretty.
 	Pretty = true
 
 	// Cache enables caching of identical requests (experimental).
+	// It is the default for clients without WithCache.
 	Cache = false
 )
 
@@ -225,6 +225,7 @@ func (t *TTSBuilder) DoWithMeta() (*TTSResponse, error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
+	ctx = withSettings(ctx, settingsOf(client))
 
 	// Check if provider supports TTS
 	audioProvider, ok := client.provider.(AudioProvider)
@@ -240,18 +241,20 @@ func (t *TTSBuilder) DoWithMeta() (*TTSResponse, error) {
 		Speed:  t.speed,
 	}
 
-	if Debug {
+	if debugOn(ctx) {
 		fmt.Printf("%s TTS: %d chars → %s voice, %s format\n",
 			colorCyan("→"), len(t.text), t.voice, t.format)
 	}
 
-	waitForRateLimit()
+	if err := waitForRateLimit(ctx); err != nil {
+		return nil, err
+	}
 	resp, err := audioProvider.TextToSpeech(ctx, req)
 	if err != nil {
 		return nil, err
 	}
 
-	if Debug {
+	if debugOn(ctx) {
 		fmt.Printf("%s Generated %d bytes of audio\n", colorGreen("✓"), len(resp.Audio))
 	}
 
@@ -269,7 +272,7 @@ func (t *TTSBuilder) Save(path string) error {
 		return fmt.Errorf("failed to save audio: %w", err)
 	}
 
-	if Debug {
+	if settingsOf(t.client).debug {
 		fmt.Printf("%s Saved audio to %s\n", colorGreen("✓"), path)
 	}
 
@@ -392,6 +395,7 @@ func (s *STTBuilder) DoWithMeta() (*STTResponse, error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
+	ctx = withSettings(ctx, settingsOf(client))
 
 	// Check if provider supports STT
 	audioProvider, ok := client.provider.(AudioProvider)
@@ -410,18 +414,20 @@ func (s *STTBuilder) DoWithMeta() (*STTResponse, error) {
 		Timestamps:  s.timestamps,
 	}
 
-	if Debug {
+	if debugOn(ctx) {
 		fmt.Printf("%s STT: %d bytes audio, model=%s\n",
 			colorCyan("→"), len(s.audio), s.model)
 	}
 
-	waitForRateLimit()
+	if err := waitForRateLimit(ctx); err != nil {
+		return nil, err
+	}
 	resp, err := audioProvider.SpeechToText(ctx, req)
 	if err != nil {
 		return nil, err
 	}
 
-	if Debug {
+	if debugOn(ctx) {
 		fmt.Printf("%s Transcribed: %d chars, duration=%.1fs\n",
 			colorGreen("✓"), len(resp.Text), resp.Duration)
 	}
@@ -0,0 +1,303 @@
+package ai
+
+import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// AWS Credentials
+// ═══════════════════════════════════════════════════════════════════════════
+
+// AWSCredentials are the keys used to sign AWS requests.
+type AWSCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string // Set for temporary (STS) credentials
+}
+
+// AWSCredentialsProvider supplies credentials for each signed request.
+type AWSCredentialsProvider interface {
+	Retrieve(ctx context.Context) (AWSCredentials, error)
+}
+
+// AWSCredentialsFunc adapts a function to AWSCredentialsProvider.
+type AWSCredentialsFunc func(ctx context.Context) (AWSCredentials, error)
+
+// Retrieve calls f.
+func (f AWSCredentialsFunc) Retrieve(ctx context.Context) (AWSCredentials, error) {
+	return f(ctx)
+}
+
+// StaticAWSCredentials returns a provider for fixed keys.
+func StaticAWSCredentials(accessKeyID, secretAccessKey, sessionToken string) AWSCredentialsProvider {
+	creds := AWSCredentials{AccessKeyID: accessKeyID, SecretAccessKey: secretAccessKey, SessionToken: sessionToken}
+	return AWSCredentialsFunc(func(context.Context) (AWSCredentials, error) {
+		return creds, nil
+	})
+}
+
+// EnvAWSCredentials reads AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN.
+func EnvAWSCredentials() AWSCredentialsProvider {
+	return AWSCredentialsFunc(func(context.Context) (AWSCredentials, error) {
+		creds := AWSCredentials{
+			AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		}
+		if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+			return AWSCredentials{}, fmt.Errorf("AWS_ACCESS_KEY_ID / AWS_SECRET_ACCESS_KEY not set")
+		}
+		return creds, nil
+	})
+}
+
+// SharedAWSCredentials reads a profile from the shared credentials file
+// (AWS_SHARED_CREDENTIALS_FILE or ~/.aws/credentials). An empty profile
+// uses AWS_PROFILE, then "default".
+func SharedAWSCredentials(profile string) AWSCredentialsProvider {
+	return AWSCredentialsFunc(func(context.Context) (AWSCredentials, error) {
+		profile := awsProfile(profile)
+		section, err := readAWSConfigSection(awsConfigPath("AWS_SHARED_CREDENTIALS_FILE", "credentials"), profile)
+		if err != nil {
+			return AWSCredentials{}, err
+		}
+		creds := AWSCredentials{
+			AccessKeyID:     section["aws_access_key_id"],
+			SecretAccessKey: section["aws_secret_access_key"],
+			SessionToken:    section["aws_session_token"],
+		}
+		if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+			return AWSCredentials{}, fmt.Errorf("no AWS keys for profile %q", profile)
+		}
+		return creds, nil
+	})
+}
+
+// DefaultAWSCredentials tries the environment, then the shared credentials file.
+func DefaultAWSCredentials() AWSCredentialsProvider {
+	env, shared := EnvAWSCredentials(), SharedAWSCredentials("")
+	return AWSCredentialsFunc(func(ctx context.Context) (AWSCredentials, error) {
+		if creds, err := env.Retrieve(ctx); err == nil {
+			return creds, nil
+		}
+		creds, err := shared.Retrieve(ctx)
+		if err != nil {
+			return AWSCredentials{}, fmt.Errorf("no AWS credentials in environment or shared config: %w", err)
+		}
+		return creds, nil
+	})
+}
+
+// WithAWSCredentials sets the credentials used to sign AWS requests.
+func WithAWSCredentials(p AWSCredentialsProvider) ClientOption {
+	return func(c *ProviderConfig) {
+		c.AWSCredentials = p
+	}
+}
+
+// WithRegion sets the cloud region (AWS region for Bedrock).
+func WithRegion(region string) ClientOption {
+	return func(c *ProviderConfig) {
+		c.Region = region
+	}
+}
+
+// awsRegion resolves the region from AWS_REGION, AWS_DEFAULT_REGION or the
+// shared config file (AWS_CONFIG_FILE or ~/.aws/config).
+func awsRegion() string {
+	if r := getEnvWithFallback("AWS_REGION", "AWS_DEFAULT_REGION"); r != "" {
+		return r
+	}
+	profile := awsProfile("")
+	if profile != "default" {
+		profile = "profile " + profile
+	}
+	section, err := readAWSConfigSection(awsConfigPath("AWS_CONFIG_FILE", "config"), profile)
+	if err != nil {
+		return ""
+	}
+	return section["region"]
+}
+
+func awsProfile(profile string) string {
+	if profile == "" {
+		profile = os.Getenv("AWS_PROFILE")
+	}
+	if profile == "" {
+		profile = "default"
+	}
+	return profile
+}
+
+func awsConfigPath(envVar, name string) string {
+	if p := os.Getenv(envVar); p != "" {
+		return p
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".aws", name)
+}
+
+// readAWSConfigSection returns the key/value pairs of one [section] of an AWS INI file.
+func readAWSConfigSection(path, section string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	values := map[string]string{}
+	found, inSection := false, false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			inSection = strings.TrimSpace(line[1:len(line)-1]) == section
+			found = found || inSection
+			continue
+		}
+		if key, value, ok := strings.Cut(line, "="); ok && inSection {
+			values[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("section [%s] not found in %s", section, path)
+	}
+	return values, nil
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Signature Version 4
+// ═══════════════════════════════════════════════════════════════════════════
+
+const awsTimeFormat = "20060102T150405Z"
+
+// signAWSRequest signs req in place with AWS Signature Version 4. body must
+// be the exact request payload. Signed headers are host, x-amz-*, and
+// content-type when present.
+func signAWSRequest(req *http.Request, body []byte, creds AWSCredentials, region, service string, now time.Time) {
+	amzDate := now.UTC().Format(awsTimeFormat)
+	date := amzDate[:8]
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+
+	// Canonical headers, sorted by lowercase name.
+	headers := map[string]string{"host": host}
+	for name, values := range req.Header {
+		lower := strings.ToLower(name)
+		if strings.HasPrefix(lower, "x-amz-") || lower == "content-type" {
+			headers[lower] = strings.Join(values, ",")
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.Join(strings.Fields(headers[name]), " ") + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	payloadHash := sha256.Sum256(body)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		awsCanonicalURI(req.URL),
+		awsCanonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+creds.SecretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		creds.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// awsCanonicalURI encodes each segment of the (already escaped) path again,
+// as SigV4 requires for every service except S3.
+func awsCanonicalURI(u *url.URL) string {
+	path := u.EscapedPath()
+	if path == "" {
+		return "/"
+	}
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		segments[i] = awsEscape(s)
+	}
+	return strings.Join(segments, "/")
+}
+
+func awsCanonicalQuery(q url.Values) string {
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, k := range keys {
+		values := append([]string(nil), q[k]...)
+		sort.Strings(values)
+		for _, v := range values {
+			parts = append(parts, awsEscape(k)+"="+awsEscape(v))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// awsEscape percent-encodes everything except the RFC 3986 unreserved characters.
+func awsEscape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
@@ -27,6 +27,7 @@ type BatchConfig struct {
 	Timeout        time.Duration // per-request timeout (0 = no timeout)
 	StopOnError    bool          // stop all on first error
 	RetryConfig    *RetryConfig  // retry config for failed requests
+	Priority       Priority      // rate-limit priority for builders left at PriorityNormal (default: PriorityBatch)
 }
 
 // DefaultBatchConfig returns sensible defaults.
@@ -36,6 +37,7 @@ func DefaultBatchConfig() *BatchConfig {
 		Timeout:        0,
 		StopOnError:    false,
 		RetryConfig:    nil,
+		Priority:       PriorityBatch,
 	}
 }
 
@@ -92,6 +94,12 @@ func (b *BatchBuilder) WithRetry(config *RetryConfig) *BatchBuilder {
 	return b
 }
 
+// Priority sets the rate-limit priority of the batch's requests.
+func (b *BatchBuilder) Priority(p Priority) *BatchBuilder {
+	b.config.Priority = p
+	return b
+}
+
 // WithContext sets the context for all requests.
 func (b *BatchBuilder) WithContext(ctx context.Context) *BatchBuilder {
 	b.ctx = ctx
@@ -171,12 +179,17 @@ func (b *BatchBuilder) Do() []BatchResult {
 				defer reqCancel()
 			}
 
+			// Send a copy so the caller's builder keeps its own settings
+			bldr = bldr.Clone()
+
 			// Apply retry config if set
 			if b.config.RetryConfig != nil && bldr.retryConfig == nil {
-				bldr = bldr.Clone()
 				bldr.retryConfig = b.config.RetryConfig
 			}
 
+			// Yield to interactive traffic unless the builder chose a priority
+			reqCtx = withDefaultPriority(reqCtx, b.config.Priority)
+
 			// Execute request
 			reqStart := time.Now()
 			bldr.ctx = reqCtx
@@ -3,6 +3,7 @@ package ai
 import (
 	gocontext "context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"maps"
 	"os"
@@ -48,6 +49,9 @@ type Builder struct {
 	// thinking controls the reasoning effort level.
 	thinking ThinkingLevel
 
+	// maxTokens caps the response length (0 = provider default).
+	maxTokens int
+
 	// Tool calling (function tools)
 	tools        []Tool
 	toolHandlers map[string]ToolHandler
@@ -56,7 +60,14 @@ type Builder struct {
 	builtinTools []BuiltinTool
 
 	// Vision
-	images []ImageInput
+	images       []ImageInput
+	imageOptions *ImageOptions // nil = send images unmodified
+
+	// Document text extraction (nil = only when the provider lacks support)
+	extractOptions *ExtractOptions
+
+	// quiet suppresses pretty output (set when a Conversation prints the exchange itself)
+	quiet bool
 
 	// Documents (PDF)
 	documents []DocumentInput
@@ -75,6 +86,12 @@ type Builder struct {
 
 	// Validation / Guardrails
 	validators []Validator
+
+	// Rate-limit priority (see Limits)
+	priority Priority
+
+	// Overrides of the client's debug, pretty, cache, limiter and hooks settings
+	settings settings
 }
 
 // New creates a new Builder instance for the specified model.
@@ -200,6 +217,20 @@ func (b *Builder) Retry(times int) *Builder {
 	return b
 }
 
+// retryable reports whether the simple Retry loop should try again. Errors of
+// a known non-transient kind (auth, invalid request, ...) and open circuits
+// fail the same way every time.
+func retryable(err error) bool {
+	if errors.Is(err, ErrCircuitOpen) {
+		return false
+	}
+	var pe *ProviderError
+	if errors.As(err, &pe) && pe.kind() != nil {
+		return isTransient(pe)
+	}
+	return true
+}
+
 // Fallback sets a list of fallback models to try if the primary model fails.
 // The builder will attempt each model in order until one succeeds or all fail.
 func (b *Builder) Fallback(models ...Model) *Builder {
@@ -230,6 +261,17 @@ func (b *Builder) Temperature(temp float64) *Builder {
 	return b
 }
 
+// ═══════════════════════════════════════════════════════════════════════════
+// Max Output Tokens
+// ═══════════════════════════════════════════════════════════════════════════
+
+// MaxTokens caps the number of tokens the model may generate.
+// It is also reserved from the context window in the preflight check.
+func (b *Builder) MaxTokens(n int) *Builder {
+	b.maxTokens = n
+	return b
+}
+
 // ═══════════════════════════════════════════════════════════════════════════
 // Thinking Level (Reasoning Effort)
 // ═══════════════════════════════════════════════════════════════════════════
@@ -318,29 +360,7 @@ func (b *Builder) buildMessages() []Message {
 					parts = append(parts, ContentPart{Type: "text", Text: text})
 				}
 
-				// Add images
-				for _, img := range b.images {
-					parts = append(parts, ContentPart{
-						Type: "image_url",
-						ImageURL: &ImageURL{
-							URL:    img.URL,
-							Detail: img.Detail,
-						},
-					})
-				}
-
-				// Add documents (PDFs)
-				for _, doc := range b.documents {
-					parts = append(parts, ContentPart{
-						Type: "document",
-						Document: &DocumentRef{
-							Data:     doc.Data,
-							URL:      doc.URL,
-							MimeType: doc.MimeType,
-							Name:     doc.Name,
-						},
-					})
-				}
+				parts = append(parts, b.attachmentParts()...)
 
 				msgs[i].Content = parts
 				break
@@ -351,6 +371,32 @@ func (b *Builder) buildMessages() []Message {
 	return msgs
 }
 
+// attachmentParts returns the attached images and documents as content parts.
+func (b *Builder) attachmentParts() []ContentPart {
+	var parts []ContentPart
+	for _, img := range b.images {
+		parts = append(parts, ContentPart{
+			Type: "image_url",
+			ImageURL: &ImageURL{
+				URL:    img.URL,
+				Detail: img.Detail,
+			},
+		})
+	}
+	for _, doc := range b.documents {
+		parts = append(parts, ContentPart{
+			Type: "document",
+			Document: &DocumentRef{
+				Data:     doc.Data,
+				URL:      doc.URL,
+				MimeType: doc.MimeType,
+				Name:     doc.Name,
+			},
+		})
+	}
+	return parts
+}
+
 // Send executes the request and returns the response content as a string.
 // It handles retries, fallbacks, and error handling as configured.
 func (b *Builder) Send() (string, error) {
@@ -384,88 +430,168 @@ type ResponseMeta struct {
 	// Retries is the number of retry attempts made.
 	Retries int
 
+	// ToolCalls are the tool invocations requested by the model, if any.
+	ToolCalls []ToolCall
+
 	// Responses API output (populated when using built-in tools)
 	// Contains citations, sources, and tool call details
 	ResponsesOutput *ResponsesOutput
+
+	// RateLimit is the provider's remaining request/token quota, when reported.
+	RateLimit *RateLimitInfo
+
+	// Cached is true when the response came from the cache (see Cache).
+	Cached bool
 }
 
 // SendWithMeta executes the request and returns the response with full metadata.
 // This includes token usage, latency, and the specific model used.
-func (b *Builder) SendWithMeta() *ResponseMeta {
+func (b *Builder) SendWithMeta() (meta *ResponseMeta) {
 	msgs := b.buildMessages()
 	start := time.Now()
 
-	// Enable debug for this request if set
-	oldDebug := Debug
-	if b.debug {
-		Debug = true
-	}
-	defer func() { Debug = oldDebug }()
-
 	// Get the client to use
 	client := b.client
 	if client == nil {
 		client = getDefaultClient()
 	}
 
-	// Get context
-	ctx := b.getContext()
+	// Resolve debug, pretty, cache, limiter and hooks for this request
+	set := b.resolveSettings(client)
+	hooks := set.hooks
+	ctx := withSettings(b.getContext(), set)
+	ctx, span := startSpan(ctx, "ai.send",
+		Attr("gen_ai.operation.name", "chat"), Attr("gen_ai.request.model", string(b.model)))
+	defer func() { endSendSpan(span, meta) }()
 
 	// Try primary model with fallbacks
 	models := append([]Model{b.model}, b.fallbacks...)
 	var lastErr error
 	var totalRetries int
+	docs := documentCache{}
+
+	for i, model := range models {
+		provider := client.providerFor(model)
+		if i > 0 {
+			metrics.fallback(client.providerFor(models[i-1]).Name(), string(models[i-1]))
+		}
+
+		// Convert documents this hop's model can't read natively into text
+		msgs, docErr := b.prepareDocuments(ctx, client, provider, model, msgs, docs)
+		if docErr != nil {
+			return &ResponseMeta{Error: docErr, Model: model, Latency: time.Since(start)}
+		}
+
+		// Each fallback hop gets its own span when there are fallbacks
+		hopCtx, hop := ctx, Span(noopSpan{})
+		if len(models) > 1 {
+			hopCtx, hop = startSpan(ctx, "ai.fallback",
+				Attr("gen_ai.request.model", string(model)), Attr("ai.fallback.index", i))
+		}
 
-	for _, model := range models {
 		// Build provider request
 		req := &ProviderRequest{
 			Model:        string(model),
 			Messages:     msgs,
 			Temperature:  b.temperature,
 			Thinking:     b.thinking,
+			MaxTokens:    b.maxTokens,
 			Tools:        b.tools,
 			BuiltinTools: b.builtinTools,
 			JSONMode:     b.jsonMode,
 		}
 
+		// Skip models that can't serve this request before spending an HTTP call
+		if err := b.checkModelSupport(model, msgs); err != nil {
+			hooks.invokeOnError(model, err)
+			lastErr = err
+			endSpan(hop, err)
+			continue
+		}
+		if err := b.checkContextWindow(model, msgs); err != nil {
+			hooks.invokeOnError(model, err)
+			lastErr = err
+			endSpan(hop, err)
+			continue
+		}
+		// Skip straight to the next fallback while this model's circuit is open
+		if set.breaker != nil && set.breaker.State(provider.Name(), model) == CircuitOpen {
+			err := breakerAllow(ctx, provider, model)
+			hooks.invokeOnError(model, err)
+			lastErr = err
+			endSpan(hop, err)
+			continue
+		}
+
 		// Check capability warnings
 		if len(b.tools) > 0 {
-			checkCapability(client.provider, "tools", client.provider.Capabilities().Tools)
+			checkCapability(ctx, provider, "tools", provider.Capabilities().Tools)
 		}
 		if b.thinking != "" {
-			checkCapability(client.provider, "thinking/reasoning", client.provider.Capabilities().Thinking)
+			checkCapability(ctx, provider, "thinking/reasoning", provider.Capabilities().Thinking)
 		}
 		// Check built-in tool capabilities
 		for _, bt := range b.builtinTools {
 			switch bt.Type {
 			case "web_search":
-				checkCapability(client.provider, "web_search", client.provider.Capabilities().WebSearch)
+				checkCapability(ctx, provider, "web_search", provider.Capabilities().WebSearch)
 			case "file_search":
-				checkCapability(client.provider, "file_search", client.provider.Capabilities().FileSearch)
+				checkCapability(ctx, provider, "file_search", provider.Capabilities().FileSearch)
 			case "code_interpreter":
-				checkCapability(client.provider, "code_interpreter", client.provider.Capabilities().CodeInterpreter)
+				checkCapability(ctx, provider, "code_interpreter", provider.Capabilities().CodeInterpreter)
 			case "mcp":
-				checkCapability(client.provider, "mcp", client.provider.Capabilities().MCP)
+				checkCapability(ctx, provider, "mcp", provider.Capabilities().MCP)
 			case "image_generation":
-				checkCapability(client.provider, "image_generation", client.provider.Capabilities().ImageGeneration)
+				checkCapability(ctx, provider, "image_generation", provider.Capabilities().ImageGeneration)
 			case "computer_use_preview":
-				checkCapability(client.provider, "computer_use", client.provider.Capabilities().ComputerUse)
+				checkCapability(ctx, provider, "computer_use", provider.Capabilities().ComputerUse)
 			case "shell":
-				checkCapability(client.provider, "shell", client.provider.Capabilities().Shell)
+				checkCapability(ctx, provider, "shell", provider.Capabilities().Shell)
 			case "apply_patch":
-				checkCapability(client.provider, "apply_patch", client.provider.Capabilities().ApplyPatch)
+				checkCapability(ctx, provider, "apply_patch", provider.Capabilities().ApplyPatch)
 			}
 		}
 
-		if Debug {
-			printDebugRequest(model, msgs)
+		logRequest(ctx, provider.Name(), model, msgs)
+		if len(b.images) > 0 {
+			logImages(ctx, model, b.imageEstimatesFor)
+		}
+
+		// send makes one attempt, after rate limits and the circuit breaker allow it
+		attempt := 0
+		send := func() (*ProviderResponse, error) {
+			attempt++
+			return b.limitedSend(ctx, provider, model, msgs, func() (*ProviderResponse, error) {
+				if err := breakerAllow(ctx, provider, model); err != nil {
+					return nil, err
+				}
+				r, e := instrumentedSend(hopCtx, provider, req, attempt, func(ctx gocontext.Context) (*ProviderResponse, error) {
+					return provider.Send(ctx, req)
+				})
+				breakerRecord(ctx, provider, model, e)
+				return r, e
+			})
+		}
+
+		// Identical plain requests are served from the cache when enabled
+		var key string
+		if set.cache && len(b.tools) == 0 && len(b.builtinTools) == 0 {
+			key = requestCacheKey(req)
 		}
 
 		// Use smart retry if configured
 		var resp *ProviderResponse
 		var err error
+		var cached bool
 
-		if b.retryConfig != nil {
+		content, hit := set.store.lookup(key)
+		if key != "" {
+			metrics.cacheLookup(hit)
+		}
+		if hit {
+			logCacheHit(ctx, model)
+			resp, cached = &ProviderResponse{Content: content}, true
+		} else if b.retryConfig != nil {
 			// Smart retry with exponential backoff + jitter
 			var retries int
 			resp, err = WithRetry(ctx, b.retryConfig, func() (*ProviderResponse, error) {
@@ -473,11 +599,10 @@ func (b *Builder) SendWithMeta() *ResponseMeta {
 				if retries > 1 {
 					totalRetries++
 				}
-				invokeBeforeRequest(model, msgs)
-				waitForRateLimit()
-				r, e := client.provider.Send(ctx, req)
+				hooks.invokeBeforeRequest(model, msgs)
+				r, e := send()
 				if e != nil {
-					invokeOnError(model, e)
+					hooks.invokeOnError(model, e)
 				}
 				return r, e
 			})
@@ -488,31 +613,37 @@ func (b *Builder) SendWithMeta() *ResponseMeta {
 					totalRetries++
 					time.Sleep(time.Duration(attempt*attempt) * 100 * time.Millisecond)
 				}
-				invokeBeforeRequest(model, msgs)
-				waitForRateLimit()
-				resp, err = client.provider.Send(ctx, req)
+				hooks.invokeBeforeRequest(model, msgs)
+				resp, err = send()
 				if err == nil {
 					break
 				}
-				invokeOnError(model, err)
+				hooks.invokeOnError(model, err)
+				if !retryable(err) {
+					break
+				}
 			}
 		} else {
 			// No retry
-			invokeBeforeRequest(model, msgs)
-			waitForRateLimit()
-			resp, err = client.provider.Send(ctx, req)
+			hooks.invokeBeforeRequest(model, msgs)
+			resp, err = send()
 			if err != nil {
-				invokeOnError(model, err)
+				hooks.invokeOnError(model, err)
 			}
 		}
 
 		if err == nil {
+			if key != "" && !cached {
+				set.store.set(key, resp.Content)
+			}
+
 			// Validate response if validators configured (and apply any content filters)
 			content := resp.Content
 			if len(b.validators) > 0 {
 				validated, validationErr := b.runValidators(content)
 				if validationErr != nil {
-					invokeOnError(model, validationErr)
+					hooks.invokeOnError(model, validationErr)
+					endSpan(hop, validationErr)
 					return &ResponseMeta{
 						Error:   validationErr,
 						Model:   model,
@@ -531,21 +662,31 @@ func (b *Builder) SendWithMeta() *ResponseMeta {
 				Tokens:           resp.TotalTokens,
 				PromptTokens:     resp.PromptTokens,
 				CompletionTokens: resp.CompletionTokens,
+				ToolCalls:        resp.ToolCalls,
 				ResponsesOutput:  resp.ResponsesOutput,
+				RateLimit:        resp.RateLimit,
+				Cached:           cached,
 			}
 
-			if Pretty {
-				printPrettyResponse(model, content)
+			if !b.quiet {
+				logAnswer(ctx, model, "", content)
 			}
 
 			// Track stats
 			trackRequest(meta)
-			invokeOnTokens(model, meta.PromptTokens, meta.CompletionTokens)
-			invokeAfterResponse(model, meta.Content, meta.Latency)
+			hooks.invokeOnTokens(model, meta.PromptTokens, meta.CompletionTokens)
+			hooks.invokeAfterResponse(model, meta.Content, meta.Latency)
+			endSpan(hop, nil)
 
 			return meta
 		}
 		lastErr = err
+		endSpan(hop, err)
+
+		// A cancelled or expired context fails every fallback the same way
+		if ctx.Err() != nil {
+			break
+		}
 	}
 
 	return &ResponseMeta{Error: lastErr, Model: b.model, Latency: time.Since(start), Retries: totalRetries}
@@ -647,25 +788,31 @@ func (b *Builder) Clone() *Builder {
 		tempCopy = &v
 	}
 	newB := &Builder{
-		model:        b.model,
-		system:       b.system,
-		messages:     make([]Message, len(b.messages)),
-		vars:         make(Vars),
-		fileContext:  make([]string, len(b.fileContext)),
-		debug:        b.debug,
-		maxRetries:   b.maxRetries,
-		fallbacks:    make([]Model, len(b.fallbacks)),
-		jsonMode:     b.jsonMode,
-		temperature:  tempCopy,
-		thinking:     b.thinking,
-		tools:        make([]Tool, len(b.tools)),
-		builtinTools: make([]BuiltinTool, len(b.builtinTools)),
-		images:       make([]ImageInput, len(b.images)),
-		documents:    make([]DocumentInput, len(b.documents)),
-		client:       b.client,
-		ctx:          b.ctx,
-		retryConfig:  b.retryConfig,
-		validators:   make([]Validator, len(b.validators)),
+		model:          b.model,
+		system:         b.system,
+		messages:       make([]Message, len(b.messages)),
+		vars:           make(Vars),
+		fileContext:    make([]string, len(b.fileContext)),
+		debug:          b.debug,
+		maxRetries:     b.maxRetries,
+		fallbacks:      make([]Model, len(b.fallbacks)),
+		jsonMode:       b.jsonMode,
+		temperature:    tempCopy,
+		thinking:       b.thinking,
+		maxTokens:      b.maxTokens,
+		tools:          make([]Tool, len(b.tools)),
+		builtinTools:   make([]BuiltinTool, len(b.builtinTools)),
+		images:         make([]ImageInput, len(b.images)),
+		imageOptions:   b.imageOptions,
+		extractOptions: b.extractOptions,
+		quiet:          b.quiet,
+		documents:      make([]DocumentInput, len(b.documents)),
+		client:         b.client,
+		ctx:            b.ctx,
+		retryConfig:    b.retryConfig,
+		validators:     make([]Validator, len(b.validators)),
+		priority:       b.priority,
+		settings:       b.settings,
 	}
 	copy(newB.messages, b.messages)
 	copy(newB.fileContext, b.fileContext)
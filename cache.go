@@ -11,66 +11,108 @@ import (
 // Response Caching
 // ═══════════════════════════════════════════════════════════════════════════
 
-var (
-	cache     = make(map[string]string)
-	cacheLock sync.RWMutex
-)
+// responseCache stores responses by request hash. Clients with their own
+// Cache setting get their own store; the rest share defaultCache.
+type responseCache struct {
+	mu      sync.RWMutex
+	entries map[string]string
+}
+
+func newResponseCache() *responseCache {
+	return &responseCache{entries: make(map[string]string)}
+}
+
+var defaultCache = newResponseCache()
 
 // cacheKey generates a unique key for a request.
 func cacheKey(model Model, messages []Message, opts SendOptions) string {
+	return requestCacheKey(&ProviderRequest{
+		Model:       string(model),
+		Messages:    messages,
+		Temperature: opts.Temperature,
+		Thinking:    opts.Thinking,
+	})
+}
+
+// requestCacheKey hashes the parts of a request that shape its response.
+func requestCacheKey(req *ProviderRequest) string {
 	data, _ := json.Marshal(struct {
-		Model    string    `json:"m"`
-		Messages []Message `json:"msgs"`
-		Temp     *float64  `json:"t,omitempty"`
-		Thinking string    `json:"r,omitempty"`
+		Model     string    `json:"m"`
+		Messages  []Message `json:"msgs"`
+		Temp      *float64  `json:"t,omitempty"`
+		Thinking  string    `json:"r,omitempty"`
+		MaxTokens int       `json:"n,omitempty"`
+		JSONMode  bool      `json:"j,omitempty"`
 	}{
-		Model:    string(model),
-		Messages: messages,
-		Temp:     opts.Temperature,
-		Thinking: string(opts.Thinking),
+		Model:     req.Model,
+		Messages:  req.Messages,
+		Temp:      req.Temperature,
+		Thinking:  string(req.Thinking),
+		MaxTokens: req.MaxTokens,
+		JSONMode:  req.JSONMode,
 	})
 
 	hash := sha256.Sum256(data)
 	return hex.EncodeToString(hash[:])
 }
 
-// getCached returns a cached response if available.
-func getCached(model Model, messages []Message, opts SendOptions) (string, bool) {
-	if !Cache {
+func (c *responseCache) get(key string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	resp, ok := c.entries[key]
+	return resp, ok
+}
+
+// lookup is get for an optional key ("" never hits).
+func (c *responseCache) lookup(key string) (string, bool) {
+	if key == "" {
 		return "", false
 	}
+	return c.get(key)
+}
+
+func (c *responseCache) set(key, response string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = response
+}
 
-	cacheLock.RLock()
-	defer cacheLock.RUnlock()
+func (c *responseCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]string)
+}
+
+func (c *responseCache) size() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.entries)
+}
 
-	key := cacheKey(model, messages, opts)
-	resp, ok := cache[key]
+// getCached returns a cached response if available.
+func getCached(s *resolvedSettings, model Model, messages []Message, opts SendOptions) (string, bool) {
+	if !s.cache {
+		return "", false
+	}
+	resp, ok := s.store.get(cacheKey(model, messages, opts))
+	metrics.cacheLookup(ok)
 	return resp, ok
 }
 
 // setCached stores a response in cache.
-func setCached(model Model, messages []Message, opts SendOptions, response string) {
-	if !Cache {
+func setCached(s *resolvedSettings, model Model, messages []Message, opts SendOptions, response string) {
+	if !s.cache {
 		return
 	}
-
-	cacheLock.Lock()
-	defer cacheLock.Unlock()
-
-	key := cacheKey(model, messages, opts)
-	cache[key] = response
+	s.store.set(cacheKey(model, messages, opts), response)
 }
 
-// ClearCache empties the in-memory cache.
+// ClearCache empties the shared in-memory cache.
 func ClearCache() {
-	cacheLock.Lock()
-	defer cacheLock.Unlock()
-	cache = make(map[string]string)
+	defaultCache.clear()
 }
 
-// CacheSize returns the number of cached responses.
+// CacheSize returns the number of responses in the shared cache.
 func CacheSize() int {
-	cacheLock.RLock()
-	defer cacheLock.RUnlock()
-	return len(cache)
+	return defaultCache.size()
 }
@@ -49,16 +49,16 @@ func TestGetSetCached(t *testing.T) {
 	msgs := []Message{{Role: "user", Content: "test"}}
 
 	// Initially not cached
-	_, ok := getCached(ModelGPT5, msgs, SendOptions{})
+	_, ok := getCached(defaultSettings(), ModelGPT5, msgs, SendOptions{})
 	if ok {
 		t.Error("should not be cached initially")
 	}
 
 	// Set cache
-	setCached(ModelGPT5, msgs, SendOptions{}, "cached response")
+	setCached(defaultSettings(), ModelGPT5, msgs, SendOptions{}, "cached response")
 
 	// Now should be cached
-	response, ok := getCached(ModelGPT5, msgs, SendOptions{})
+	response, ok := getCached(defaultSettings(), ModelGPT5, msgs, SendOptions{})
 	if !ok {
 		t.Error("should be cached after setCached")
 	}
@@ -76,10 +76,10 @@ func TestCacheDisabled(t *testing.T) {
 	msgs := []Message{{Role: "user", Content: "test"}}
 
 	// Set should be no-op when disabled
-	setCached(ModelGPT5, msgs, SendOptions{}, "response")
+	setCached(defaultSettings(), ModelGPT5, msgs, SendOptions{}, "response")
 
 	// Get should return false when disabled
-	_, ok := getCached(ModelGPT5, msgs, SendOptions{})
+	_, ok := getCached(defaultSettings(), ModelGPT5, msgs, SendOptions{})
 	if ok {
 		t.Error("cache should not work when disabled")
 	}
@@ -95,7 +95,7 @@ func TestClearCache(t *testing.T) {
 	}()
 
 	msgs := []Message{{Role: "user", Content: "test"}}
-	setCached(ModelGPT5, msgs, SendOptions{}, "response")
+	setCached(defaultSettings(), ModelGPT5, msgs, SendOptions{}, "response")
 
 	if CacheSize() == 0 {
 		t.Error("cache should have entries")
@@ -107,7 +107,7 @@ func TestClearCache(t *testing.T) {
 		t.Error("cache should be empty after clear")
 	}
 
-	_, ok := getCached(ModelGPT5, msgs, SendOptions{})
+	_, ok := getCached(defaultSettings(), ModelGPT5, msgs, SendOptions{})
 	if ok {
 		t.Error("cache should be empty after clear")
 	}
@@ -131,18 +131,18 @@ func TestCacheSize(t *testing.T) {
 	msgs1 := []Message{{Role: "user", Content: "test1"}}
 	msgs2 := []Message{{Role: "user", Content: "test2"}}
 
-	setCached(ModelGPT5, msgs1, SendOptions{}, "response1")
+	setCached(defaultSettings(), ModelGPT5, msgs1, SendOptions{}, "response1")
 	if CacheSize() != 1 {
 		t.Errorf("expected cache size 1, got %d", CacheSize())
 	}
 
-	setCached(ModelGPT5, msgs2, SendOptions{}, "response2")
+	setCached(defaultSettings(), ModelGPT5, msgs2, SendOptions{}, "response2")
 	if CacheSize() != 2 {
 		t.Errorf("expected cache size 2, got %d", CacheSize())
 	}
 
 	// Same key shouldn't increase size
-	setCached(ModelGPT5, msgs1, SendOptions{}, "updated response")
+	setCached(defaultSettings(), ModelGPT5, msgs1, SendOptions{}, "updated response")
 	if CacheSize() != 2 {
 		t.Errorf("expected cache size 2, got %d", CacheSize())
 	}
@@ -1,42 +1,202 @@
 package ai
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
 	"strings"
 )
 
 // Conversation maintains chat history for multi-turn conversations.
 // It wraps a Builder and automatically appends user and assistant messages to the history.
+// Each turn is sent through the builder's SendWithMeta path, so the builder's client,
+// tools, fallbacks, retry config and validators all apply.
 type Conversation struct {
 	builder *Builder
 	history []Message
+
+	// pending holds attachments for the next turn (nil = none)
+	pending *Builder
+
+	// memory compacts history before each turn (nil = keep everything)
+	memory  MemoryStrategy
+	summary string // rolling summary of compacted turns
+
+	// MaxToolIterations limits tool-call round trips per turn (0 = 10).
+	MaxToolIterations int
 }
 
 // Say sends a message to the AI and returns the response.
 // It appends the user's message and the AI's response to the conversation history.
 func (c *Conversation) Say(message string) (string, error) {
-	// Add user message to history
-	c.history = append(c.history, Message{Role: "user", Content: message})
+	meta := c.SayWithMeta(message)
+	return meta.Content, meta.Error
+}
+
+// SayWithMeta sends a message and returns the full response metadata.
+// Any attachments added since the last turn are included with the message, and
+// tool calls are executed with the builder's handlers until the model answers.
+// Tokens, Latency and Retries are summed across tool round trips.
+// A failed turn leaves the history (and pending attachments) as they were.
+func (c *Conversation) SayWithMeta(message string) *ResponseMeta {
+	pending := c.pending
+	c.history = append(c.history, c.userMessage(message))
+	if err := c.Compact(); err != nil {
+		c.history, c.pending = c.history[:len(c.history)-1], pending
+		return &ResponseMeta{Error: err, Model: c.builder.model}
+	}
+
+	// Compaction keeps the newest message, so the turn starts at the end
+	mark := len(c.history) - 1
+	if mark < 0 {
+		mark = 0
+	}
+	rollback := func() {
+		c.history, c.pending = c.history[:mark], pending
+	}
+
+	maxIterations := c.MaxToolIterations
+	if maxIterations <= 0 {
+		maxIterations = 10
+	}
+
+	total := &ResponseMeta{Model: c.builder.model}
+	for i := 0; i < maxIterations; i++ {
+		turn := c.turnBuilder()
+		meta := turn.SendWithMeta()
+
+		total.Model = meta.Model
+		total.Tokens += meta.Tokens
+		total.PromptTokens += meta.PromptTokens
+		total.CompletionTokens += meta.CompletionTokens
+		total.Latency += meta.Latency
+		total.Retries += meta.Retries
+		total.ResponsesOutput = meta.ResponsesOutput
+		if meta.Error != nil {
+			total.Error = meta.Error
+			rollback()
+			return total
+		}
+
+		if len(meta.ToolCalls) == 0 || len(turn.toolHandlers) == 0 {
+			total.Content = meta.Content
+			total.ToolCalls = meta.ToolCalls
+			c.history = append(c.history, Message{Role: "assistant", Content: meta.Content})
+			logAnswer(withSettings(c.builder.getContext(), c.builder.currentSettings()), meta.Model, message, meta.Content)
+			return total
+		}
+
+		// Run every tool first, then record the tool-call turn and its results
+		// together: providers reject tool calls without results.
+		results := make([]Message, 0, len(meta.ToolCalls))
+		for _, tc := range meta.ToolCalls {
+			result, err := turn.executeToolCall(tc)
+			if err != nil {
+				total.Error = err
+				rollback()
+				return total
+			}
+			results = append(results, Message{Role: "tool", Content: result, ToolCallID: tc.ID})
+		}
+		c.history = append(c.history, Message{Role: "assistant", Content: meta.Content, ToolCalls: meta.ToolCalls})
+		c.history = append(c.history, results...)
+	}
 
-	// Build full message list
-	msgs := c.buildMessages()
+	total.Error = fmt.Errorf("max tool iterations (%d) reached", maxIterations)
+	rollback()
+	return total
+}
 
-	content, _, err := Send(c.builder.model, msgs, SendOptions{
-		Temperature: c.builder.temperature,
-		Thinking:    c.builder.thinking,
-	})
-	if err != nil {
-		return "", err
+// userMessage builds the next user message, attaching pending images and documents.
+// Attachments set on the builder itself go with the first turn.
+func (c *Conversation) userMessage(text string) Message {
+	var attachments []ContentPart
+	if len(c.history) == 0 {
+		attachments = append(attachments, c.builder.attachmentParts()...)
+	}
+	if c.pending != nil {
+		attachments = append(attachments, c.pending.attachmentParts()...)
+		c.pending = nil
 	}
+	if len(attachments) == 0 {
+		return Message{Role: "user", Content: text}
+	}
+
+	parts := []ContentPart{{Type: "text", Text: text}}
+	return Message{Role: "user", Content: append(parts, attachments...)}
+}
 
-	// Add assistant response to history
-	c.history = append(c.history, Message{Role: "assistant", Content: content})
+// turnBuilder clones the builder with the conversation history as its messages.
+func (c *Conversation) turnBuilder() *Builder {
+	b := c.builder.Clone()
+	if c.summary != "" {
+		b.system = withSummaryNote(b.system, c.summary)
+	}
+	b.messages = append([]Message(nil), c.history...)
+	b.images = nil
+	b.documents = nil
+	b.quiet = true
+	return b
+}
 
-	if Pretty {
-		printPrettyConversation(c.builder.model, message, content)
+// withSummaryNote appends the rolling conversation summary to a system prompt.
+func withSummaryNote(system, summary string) string {
+	note := "# Summary of earlier conversation\n" + summary
+	if system == "" {
+		return note
 	}
+	return system + "\n\n" + note
+}
+
+// attach returns the builder collecting attachments for the next turn.
+func (c *Conversation) attach() *Builder {
+	if c.pending == nil {
+		c.pending = New(c.builder.model)
+		c.pending.client = c.builder.client
+		c.pending.imageOptions = c.builder.imageOptions
+	}
+	return c.pending
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Per-turn Attachments
+// ═══════════════════════════════════════════════════════════════════════════
+
+// Image attaches a local image to the next message.
+func (c *Conversation) Image(path string) *Conversation {
+	c.attach().Image(path)
+	return c
+}
+
+// ImageURL attaches a remote image to the next message.
+func (c *Conversation) ImageURL(url string) *Conversation {
+	c.attach().ImageURL(url)
+	return c
+}
+
+// ImageBase64 attaches a base64-encoded image to the next message.
+func (c *Conversation) ImageBase64(data, mimeType string) *Conversation {
+	c.attach().ImageBase64(data, mimeType)
+	return c
+}
 
-	return content, nil
+// PDF attaches a local PDF to the next message.
+func (c *Conversation) PDF(path string) *Conversation {
+	c.attach().PDF(path)
+	return c
+}
+
+// PDFURL attaches a remote PDF to the next message.
+func (c *Conversation) PDFURL(url string) *Conversation {
+	c.attach().PDFURL(url)
+	return c
+}
+
+// Document attaches a local document (PDF, DOCX, HTML, CSV, text) to the next message.
+func (c *Conversation) Document(path string) *Conversation {
+	c.attach().Document(path)
+	return c
 }
 
 // buildMessages combines the system prompt with the conversation history.
@@ -45,11 +205,14 @@ func (c *Conversation) buildMessages() []Message {
 	var msgs []Message
 
 	// Add system message if present
-	if c.builder.system != "" {
+	if c.builder.system != "" || c.summary != "" {
 		system := c.builder.system
 		if len(c.builder.vars) > 0 {
 			system = applyTemplate(system, c.builder.vars)
 		}
+		if c.summary != "" {
+			system = withSummaryNote(system, c.summary)
+		}
 
 		// Add JSON instruction if enabled
 		if c.builder.jsonMode && system != "" {
@@ -82,7 +245,9 @@ func (c *Conversation) History() []Message {
 // The system prompt and other builder settings are preserved.
 func (c *Conversation) Clear() {
 	c.history = []Message{}
-	if Pretty {
+	c.pending = nil
+	c.summary = ""
+	if c.builder.currentSettings().pretty {
 		fmt.Println(colorYellow("↻ Conversation cleared"))
 	}
 }
@@ -102,18 +267,46 @@ func (c *Conversation) Dump() {
 
 	for _, m := range c.history {
 		fmt.Println()
-		if m.Role == "user" {
+		switch m.Role {
+		case "user":
 			fmt.Printf("%s\n", colorGreen("[YOU]"))
-		} else {
+		case "tool":
+			fmt.Printf("%s\n", colorYellow("[TOOL]"))
+		default:
 			fmt.Printf("%s\n", colorBlue("[ASSISTANT]"))
 		}
-		fmt.Println(m.Content)
+		fmt.Println(messageText(m))
+		for _, tc := range m.ToolCalls {
+			fmt.Printf("%s %s(%s)\n", colorYellow("🔧"), tc.Function.Name, tc.Function.Arguments)
+		}
 	}
 
 	fmt.Println(colorCyan("═══════════════════════════════════════════════════════════════"))
 	fmt.Printf("Total messages: %d\n\n", len(c.history))
 }
 
+// messageText returns the text of a message, summarizing attachments.
+func messageText(m Message) string {
+	switch content := m.Content.(type) {
+	case string:
+		return content
+	case []ContentPart:
+		var lines []string
+		for _, p := range content {
+			switch p.Type {
+			case "text":
+				lines = append(lines, p.Text)
+			case "image_url":
+				lines = append(lines, "[image]")
+			case "document":
+				lines = append(lines, "[document]")
+			}
+		}
+		return strings.Join(lines, "\n")
+	}
+	return fmt.Sprint(m.Content)
+}
+
 // LastResponse returns the content of the last assistant response.
 // Returns an empty string if there are no assistant messages.
 func (c *Conversation) LastResponse() string {
@@ -147,3 +340,102 @@ func (c *Conversation) Summarize() string {
 
 	return sb.String()
 }
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Persistence
+// ═══════════════════════════════════════════════════════════════════════════
+
+// conversationVersion is the current Save format version.
+const conversationVersion = 1
+
+// savedConversation is the JSON form written by Save.
+type savedConversation struct {
+	Version int            `json:"version"`
+	Model   Model          `json:"model"`
+	Summary string         `json:"summary,omitempty"`
+	History []savedMessage `json:"history"`
+}
+
+// savedMessage mirrors Message with content kept raw so it can be decoded
+// back into a string or []ContentPart.
+type savedMessage struct {
+	Role       string          `json:"role"`
+	Content    json.RawMessage `json:"content"`
+	ToolCalls  []ToolCall      `json:"tool_calls,omitempty"`
+	ToolCallID string          `json:"tool_call_id,omitempty"`
+}
+
+// Save writes the conversation history as JSON.
+// Builder settings (system prompt, tools, client) are not saved; they come from
+// the builder the conversation is loaded into.
+func (c *Conversation) Save(w io.Writer) error {
+	state := savedConversation{
+		Version: conversationVersion,
+		Model:   c.builder.model,
+		Summary: c.summary,
+		History: make([]savedMessage, 0, len(c.history)),
+	}
+	for _, m := range c.history {
+		content, err := json.Marshal(m.Content)
+		if err != nil {
+			return fmt.Errorf("encode message content: %w", err)
+		}
+		state.History = append(state.History, savedMessage{
+			Role:       m.Role,
+			Content:    content,
+			ToolCalls:  m.ToolCalls,
+			ToolCallID: m.ToolCallID,
+		})
+	}
+	return json.NewEncoder(w).Encode(state)
+}
+
+// Load replaces the conversation history with one written by Save.
+func (c *Conversation) Load(r io.Reader) error {
+	var state savedConversation
+	if err := json.NewDecoder(r).Decode(&state); err != nil {
+		return fmt.Errorf("decode conversation: %w", err)
+	}
+	if state.Version > conversationVersion {
+		return fmt.Errorf("unsupported conversation version %d", state.Version)
+	}
+
+	history := make([]Message, 0, len(state.History))
+	for _, sm := range state.History {
+		content, err := decodeMessageContent(sm.Content)
+		if err != nil {
+			return err
+		}
+		history = append(history, Message{
+			Role:       sm.Role,
+			Content:    content,
+			ToolCalls:  sm.ToolCalls,
+			ToolCallID: sm.ToolCallID,
+		})
+	}
+	c.history = history
+	c.summary = state.Summary
+	c.pending = nil
+	return nil
+}
+
+// decodeMessageContent restores content saved as a string or a list of parts.
+func decodeMessageContent(raw json.RawMessage) (any, error) {
+	raw = bytes.TrimSpace(raw)
+	switch {
+	case len(raw) == 0 || bytes.Equal(raw, []byte("null")):
+		return "", nil
+	case raw[0] == '[':
+		var parts []ContentPart
+		if err := json.Unmarshal(raw, &parts); err != nil {
+			return nil, fmt.Errorf("decode message parts: %w", err)
+		}
+		return parts, nil
+	default:
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil, fmt.Errorf("decode message content: %w", err)
+		}
+		return s, nil
+	}
+}
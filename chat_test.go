@@ -1,6 +1,9 @@
 package ai
 
 import (
+	"bytes"
+	"context"
+	"encoding/base64"
 	"testing"
 )
 
@@ -175,3 +178,176 @@ func TestConversationBuildMessagesNoSystem(t *testing.T) {
 		t.Error("should only have user message")
 	}
 }
+
+func TestConversationSay_UsesBuilderClientAndAttachments(t *testing.T) {
+	defer withTestGlobals(t)()
+
+	sp := &stubProvider{
+		name: "stub",
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			return &ProviderResponse{Content: "seen", TotalTokens: 3}, nil
+		},
+	}
+	client := &Client{provider: sp, providerType: ProviderOpenAI}
+
+	chat := client.New(ModelGPT5).System("sys").Chat()
+	img := base64.StdEncoding.EncodeToString([]byte("img"))
+	if _, err := chat.ImageBase64(img, "image/png").Say("what is this?"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := chat.Say("thanks"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reqs := sp.Requests()
+	if len(reqs) != 2 {
+		t.Fatalf("expected 2 requests on the builder's client, got %d", len(reqs))
+	}
+	first := reqs[0].Messages
+	if len(first) != 2 || first[0].Role != "system" {
+		t.Fatalf("unexpected first request messages: %+v", first)
+	}
+	parts, ok := first[1].Content.([]ContentPart)
+	if !ok || len(parts) != 2 || parts[1].Type != "image_url" {
+		t.Fatalf("expected text + image parts, got %#v", first[1].Content)
+	}
+	// Attachments belong to their turn only.
+	second := reqs[1].Messages
+	if second[len(second)-1].Content != "thanks" {
+		t.Fatalf("expected plain text follow-up, got %#v", second[len(second)-1].Content)
+	}
+}
+
+func TestConversationSay_ExecutesTools(t *testing.T) {
+	defer withTestGlobals(t)()
+
+	call := 0
+	sp := &stubProvider{
+		name: "stub",
+		caps: ProviderCapabilities{Tools: true},
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			call++
+			if call == 1 {
+				tc := ToolCall{ID: "tc_1", Type: "function"}
+				tc.Function.Name = "lookup"
+				tc.Function.Arguments = `{"id":"42"}`
+				return &ProviderResponse{ToolCalls: []ToolCall{tc}}, nil
+			}
+			last := req.Messages[len(req.Messages)-1]
+			if last.Role != "tool" || last.ToolCallID != "tc_1" {
+				t.Fatalf("expected tool result as last message, got %+v", last)
+			}
+			return &ProviderResponse{Content: "order 42 shipped"}, nil
+		},
+	}
+	client := &Client{provider: sp, providerType: ProviderOpenAI}
+
+	chat := client.New(ModelGPT5).
+		Tool("lookup", "Look up an order", Params().String("id", "Order ID", true).Build()).
+		OnToolCall("lookup", func(args map[string]any) (string, error) {
+			return "shipped", nil
+		}).
+		Chat()
+
+	out, err := chat.Say("where is order 42?")
+	if err != nil || out != "order 42 shipped" {
+		t.Fatalf("unexpected result: %q err=%v", out, err)
+	}
+
+	roles := ""
+	for _, m := range chat.History() {
+		roles += m.Role + " "
+	}
+	if roles != "user assistant tool assistant " {
+		t.Fatalf("unexpected history roles: %q", roles)
+	}
+}
+
+func TestConversationSay_FailedTurnLeavesHistory(t *testing.T) {
+	defer withTestGlobals(t)()
+
+	fail := false
+	sp := &stubProvider{
+		name: "stub",
+		caps: ProviderCapabilities{Tools: true},
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			if fail {
+				return nil, &ProviderError{Provider: "stub", StatusCode: 400, Message: "bad request"}
+			}
+			a, b := ToolCall{ID: "tc_1", Type: "function"}, ToolCall{ID: "tc_2", Type: "function"}
+			a.Function.Name, a.Function.Arguments = "lookup", `{"id":"1"}`
+			b.Function.Name, b.Function.Arguments = "missing", `{}`
+			return &ProviderResponse{ToolCalls: []ToolCall{a, b}}, nil
+		},
+	}
+	client := &Client{provider: sp, providerType: ProviderOpenAI}
+
+	chat := client.New(ModelGPT5).
+		Tool("lookup", "Look up an order", Params().String("id", "Order ID", true).Build()).
+		OnToolCall("lookup", func(args map[string]any) (string, error) { return "ok", nil }).
+		Chat()
+
+	// A tool call without a handler fails the turn without leaving
+	// unanswered tool calls behind.
+	if _, err := chat.Say("first"); err == nil {
+		t.Fatal("expected error for tool without handler")
+	}
+	if h := chat.History(); len(h) != 0 {
+		t.Fatalf("expected history rolled back, got %+v", h)
+	}
+
+	// So does a failed send, and pending attachments survive for the retry.
+	fail = true
+	img := base64.StdEncoding.EncodeToString([]byte("img"))
+	if _, err := chat.ImageBase64(img, "image/png").Say("second"); err == nil {
+		t.Fatal("expected send error")
+	}
+	if h := chat.History(); len(h) != 0 || chat.pending == nil {
+		t.Fatalf("expected history and attachments restored, got %+v", h)
+	}
+}
+
+func TestConversationSaveLoad(t *testing.T) {
+	tc := ToolCall{ID: "tc_1", Type: "function"}
+	tc.Function.Name = "lookup"
+	tc.Function.Arguments = `{}`
+
+	chat := &Conversation{builder: New(ModelClaudeSonnet), history: []Message{
+		{Role: "user", Content: []ContentPart{
+			{Type: "text", Text: "look"},
+			{Type: "image_url", ImageURL: &ImageURL{URL: "https://example.com/a.png"}},
+		}},
+		{Role: "assistant", Content: "", ToolCalls: []ToolCall{tc}},
+		{Role: "tool", Content: "ok", ToolCallID: "tc_1"},
+		{Role: "assistant", Content: "done"},
+	}}
+
+	var buf bytes.Buffer
+	if err := chat.Save(&buf); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	restored := New(ModelClaudeSonnet).Chat()
+	if err := restored.Load(&buf); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	h := restored.History()
+	if len(h) != 4 {
+		t.Fatalf("expected 4 messages, got %d", len(h))
+	}
+	parts, ok := h[0].Content.([]ContentPart)
+	if !ok || parts[1].ImageURL.URL != "https://example.com/a.png" {
+		t.Fatalf("expected multimodal content restored, got %#v", h[0].Content)
+	}
+	if h[1].ToolCalls[0].Function.Name != "lookup" || h[2].ToolCallID != "tc_1" {
+		t.Fatalf("expected tool call data restored, got %+v %+v", h[1], h[2])
+	}
+	if restored.LastResponse() != "done" {
+		t.Fatalf("unexpected last response: %q", restored.LastResponse())
+	}
+
+	if err := restored.Load(bytes.NewBufferString(`{"version":99,"history":[]}`)); err == nil {
+		t.Fatal("expected error for future version")
+	}
+}
@@ -0,0 +1,286 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Circuit Breaker
+// ═══════════════════════════════════════════════════════════════════════════
+
+// Breaker optionally trips a circuit per provider and model after repeated
+// failures. While a circuit is open, SendWithMeta skips that model and moves
+// on to the next Fallback instead of retrying it.
+// Set it to a CircuitBreaker (for example, NewCircuitBreaker(BreakerConfig{})),
+// or give a single client its own with WithBreaker.
+var Breaker *CircuitBreaker
+
+// CircuitState is the state of one circuit.
+type CircuitState int
+
+const (
+	CircuitClosed   CircuitState = iota // requests flow normally
+	CircuitOpen                         // requests are rejected until the cool-down ends
+	CircuitHalfOpen                     // a few trial requests decide whether to close
+)
+
+// String returns the state name.
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// BreakerConfig tunes when circuits trip and recover.
+type BreakerConfig struct {
+	FailureRate    float64       // Failure ratio in the window that trips the circuit (default: 0.5)
+	MinRequests    int           // Requests needed in the window before tripping (default: 5)
+	Window         time.Duration // Rolling window for counting outcomes (default: 1m)
+	Cooldown       time.Duration // Time open before allowing trial requests (default: 30s)
+	HalfOpenProbes int           // Successful trials needed to close again (default: 1)
+}
+
+// ErrCircuitOpen is returned (wrapped in *CircuitOpenError) when a request
+// is skipped because its circuit is open.
+var ErrCircuitOpen = errors.New("circuit open")
+
+// CircuitOpenError reports a request rejected by an open circuit.
+type CircuitOpenError struct {
+	Provider string
+	Model    Model
+	RetryAt  time.Time // when the circuit becomes half-open
+}
+
+// Error implements the error interface.
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("%s: circuit open for %s until %s", e.Provider, e.Model, e.RetryAt.Format(time.RFC3339))
+}
+
+// Unwrap returns ErrCircuitOpen so errors.Is works.
+func (e *CircuitOpenError) Unwrap() error {
+	return ErrCircuitOpen
+}
+
+type circuitKey struct {
+	provider string
+	model    Model
+}
+
+// circuit tracks one provider+model pair.
+type circuit struct {
+	state       CircuitState
+	windowStart time.Time
+	requests    int
+	failures    int
+	openedAt    time.Time
+	probes      int // trial requests in flight while half-open
+	successes   int // successful trials while half-open
+}
+
+// CircuitBreaker holds the circuits for every provider+model pair.
+type CircuitBreaker struct {
+	config BreakerConfig
+	now    func() time.Time
+
+	mu       sync.Mutex
+	circuits map[circuitKey]*circuit
+}
+
+// NewCircuitBreaker creates a CircuitBreaker; zero config fields use defaults.
+func NewCircuitBreaker(config BreakerConfig) *CircuitBreaker {
+	if config.FailureRate <= 0 {
+		config.FailureRate = 0.5
+	}
+	if config.MinRequests <= 0 {
+		config.MinRequests = 5
+	}
+	if config.Window <= 0 {
+		config.Window = time.Minute
+	}
+	if config.Cooldown <= 0 {
+		config.Cooldown = 30 * time.Second
+	}
+	if config.HalfOpenProbes <= 0 {
+		config.HalfOpenProbes = 1
+	}
+	return &CircuitBreaker{config: config, now: time.Now, circuits: map[circuitKey]*circuit{}}
+}
+
+func (cb *CircuitBreaker) circuitLocked(provider string, model Model) *circuit {
+	key := circuitKey{provider, model}
+	c, ok := cb.circuits[key]
+	if !ok {
+		c = &circuit{windowStart: cb.now()}
+		cb.circuits[key] = c
+	}
+	return c
+}
+
+// Allow reports whether a request may be sent. It returns a
+// *CircuitOpenError when the circuit is open or its trial slots are taken.
+// Every allowed request must be followed by Record.
+func (cb *CircuitBreaker) Allow(provider string, model Model) error {
+	return cb.allow(provider, model, Debug)
+}
+
+// allow is Allow with state changes printed when debug is set.
+func (cb *CircuitBreaker) allow(provider string, model Model, debug bool) error {
+	cb.mu.Lock()
+	var change func()
+	defer func() {
+		cb.mu.Unlock()
+		if change != nil {
+			change()
+		}
+	}()
+
+	c := cb.circuitLocked(provider, model)
+	now := cb.now()
+	if c.state == CircuitOpen {
+		if now.Sub(c.openedAt) < cb.config.Cooldown {
+			return &CircuitOpenError{Provider: provider, Model: model, RetryAt: c.openedAt.Add(cb.config.Cooldown)}
+		}
+		change = cb.setLocked(c, provider, model, CircuitHalfOpen, debug)
+	}
+	if c.state == CircuitHalfOpen {
+		if c.probes >= cb.config.HalfOpenProbes {
+			return &CircuitOpenError{Provider: provider, Model: model, RetryAt: now}
+		}
+		c.probes++
+	}
+	return nil
+}
+
+// Record reports the outcome of an allowed request. Errors that say nothing
+// about provider health (bad requests, cancellations) count as successes.
+func (cb *CircuitBreaker) Record(provider string, model Model, err error) {
+	cb.record(provider, model, err, Debug)
+}
+
+// record is Record with state changes printed when debug is set.
+func (cb *CircuitBreaker) record(provider string, model Model, err error, debug bool) {
+	failed := err != nil && isBreakerFailure(err)
+
+	cb.mu.Lock()
+	var change func()
+	defer func() {
+		cb.mu.Unlock()
+		if change != nil {
+			change()
+		}
+	}()
+
+	c := cb.circuitLocked(provider, model)
+	now := cb.now()
+
+	switch c.state {
+	case CircuitHalfOpen:
+		if c.probes > 0 {
+			c.probes--
+		}
+		if failed {
+			change = cb.setLocked(c, provider, model, CircuitOpen, debug)
+			return
+		}
+		c.successes++
+		if c.successes >= cb.config.HalfOpenProbes {
+			change = cb.setLocked(c, provider, model, CircuitClosed, debug)
+		}
+	case CircuitClosed:
+		if now.Sub(c.windowStart) >= cb.config.Window {
+			c.windowStart, c.requests, c.failures = now, 0, 0
+		}
+		c.requests++
+		if failed {
+			c.failures++
+		}
+		if c.requests >= cb.config.MinRequests && float64(c.failures)/float64(c.requests) >= cb.config.FailureRate {
+			change = cb.setLocked(c, provider, model, CircuitOpen, debug)
+		}
+	}
+}
+
+// setLocked moves c to state and returns the hook call to run after unlocking.
+func (cb *CircuitBreaker) setLocked(c *circuit, provider string, model Model, state CircuitState, debug bool) func() {
+	from := c.state
+	c.state = state
+	c.probes, c.successes = 0, 0
+	switch state {
+	case CircuitOpen:
+		c.openedAt = cb.now()
+	case CircuitClosed:
+		c.windowStart, c.requests, c.failures = cb.now(), 0, 0
+	}
+	if debug {
+		fmt.Printf("%s [%s] circuit for %s: %s → %s\n", colorYellow("⚡"), provider, model, from, state)
+	}
+	return func() { invokeOnCircuitChange(provider, model, from, state) }
+}
+
+// State returns the current state of a circuit. An open circuit whose
+// cool-down has passed reports half-open.
+func (cb *CircuitBreaker) State(provider string, model Model) CircuitState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	c, ok := cb.circuits[circuitKey{provider, model}]
+	if !ok {
+		return CircuitClosed
+	}
+	if c.state == CircuitOpen && cb.now().Sub(c.openedAt) >= cb.config.Cooldown {
+		return CircuitHalfOpen
+	}
+	return c.state
+}
+
+// Reset closes every circuit.
+func (cb *CircuitBreaker) Reset() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.circuits = map[circuitKey]*circuit{}
+}
+
+// isBreakerFailure reports whether err reflects provider health: server
+// errors, timeouts, rate limits and transport failures. Client errors such
+// as invalid requests or bad keys do not trip the circuit.
+func isBreakerFailure(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, ErrCircuitOpen) {
+		return false
+	}
+	var pe *ProviderError
+	if errors.As(err, &pe) && pe.kind() != nil {
+		return isTransient(pe)
+	}
+	return true
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Builder Integration
+// ═══════════════════════════════════════════════════════════════════════════
+
+// breakerAllow checks the request's breaker (Breaker unless the client sets
+// one) before a request.
+func breakerAllow(ctx context.Context, provider Provider, model Model) error {
+	set := settingsFrom(ctx)
+	if set.breaker == nil {
+		return nil
+	}
+	return set.breaker.allow(provider.Name(), model, set.debug)
+}
+
+// breakerRecord reports a request outcome to the request's breaker (if any).
+func breakerRecord(ctx context.Context, provider Provider, model Model, err error) {
+	set := settingsFrom(ctx)
+	if set.breaker != nil {
+		set.breaker.record(provider.Name(), model, err, set.debug)
+	}
+}
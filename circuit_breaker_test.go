@@ -0,0 +1,166 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker_TripsAndRecovers(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+	defer ClearHooks()
+
+	var changes []string
+	OnCircuitChange(func(provider string, model Model, from, to CircuitState) {
+		changes = append(changes, provider+" "+string(model)+" "+from.String()+"→"+to.String())
+	})
+
+	now := time.Unix(0, 0)
+	cb := NewCircuitBreaker(BreakerConfig{FailureRate: 0.5, MinRequests: 4, Cooldown: time.Minute})
+	cb.now = func() time.Time { return now }
+
+	serverErr := &ProviderError{Provider: "openai", Code: "500", Message: "boom"}
+	badRequest := &ProviderError{Provider: "openai", Code: "400", Message: "bad"}
+
+	// Client errors say nothing about provider health.
+	for i := 0; i < 4; i++ {
+		cb.Record("openai", ModelGPT4o, badRequest)
+	}
+	if cb.State("openai", ModelGPT4o) != CircuitClosed {
+		t.Fatal("client errors should not trip the circuit")
+	}
+
+	cb.Reset()
+	cb.Record("openai", ModelGPT4o, nil)
+	cb.Record("openai", ModelGPT4o, serverErr)
+	cb.Record("openai", ModelGPT4o, nil)
+	if cb.State("openai", ModelGPT4o) != CircuitClosed {
+		t.Fatal("circuit should stay closed below MinRequests")
+	}
+	cb.Record("openai", ModelGPT4o, serverErr)
+	if cb.State("openai", ModelGPT4o) != CircuitOpen {
+		t.Fatal("expected circuit to open at 50% failures")
+	}
+	if cb.State("openai", ModelGPT4oMini) != CircuitClosed {
+		t.Fatal("circuits are per model")
+	}
+
+	err := cb.Allow("openai", ModelGPT4o)
+	var openErr *CircuitOpenError
+	if !errors.Is(err, ErrCircuitOpen) || !errors.As(err, &openErr) || !openErr.RetryAt.Equal(now.Add(time.Minute)) {
+		t.Fatalf("expected circuit open error, got %v", err)
+	}
+
+	// After the cool-down a single probe is let through; a failure reopens.
+	now = now.Add(time.Minute)
+	if err := cb.Allow("openai", ModelGPT4o); err != nil {
+		t.Fatalf("expected half-open probe, got %v", err)
+	}
+	if err := cb.Allow("openai", ModelGPT4o); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected second probe to be rejected, got %v", err)
+	}
+	cb.Record("openai", ModelGPT4o, serverErr)
+	if cb.State("openai", ModelGPT4o) != CircuitOpen {
+		t.Fatal("failed probe should reopen the circuit")
+	}
+
+	// A successful probe closes it.
+	now = now.Add(time.Minute)
+	if err := cb.Allow("openai", ModelGPT4o); err != nil {
+		t.Fatal(err)
+	}
+	cb.Record("openai", ModelGPT4o, nil)
+	if cb.State("openai", ModelGPT4o) != CircuitClosed {
+		t.Fatal("successful probe should close the circuit")
+	}
+
+	prefix := "openai " + string(ModelGPT4o) + " "
+	want := []string{
+		prefix + "closed→open",
+		prefix + "open→half-open",
+		prefix + "half-open→open",
+		prefix + "open→half-open",
+		prefix + "half-open→closed",
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("unexpected state changes %v", changes)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Fatalf("change %d = %q, want %q", i, changes[i], want[i])
+		}
+	}
+}
+
+func TestCircuitBreaker_OpenCircuitSkipsToFallback(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+	oldBreaker := Breaker
+	defer func() { Breaker = oldBreaker }()
+	Breaker = NewCircuitBreaker(BreakerConfig{MinRequests: 2, Cooldown: time.Hour})
+
+	primary := resolveModel(ProviderOpenAI, ModelGPT4o)
+	p := &stubProvider{name: "openai", sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+		if req.Model == primary || req.Model == string(ModelGPT4o) {
+			return nil, &ProviderError{Provider: "openai", Code: "503", Message: "overloaded"}
+		}
+		return &ProviderResponse{Content: "fallback"}, nil
+	}}
+	setDefaultClientForTest(t, p, ProviderOpenAI)
+
+	// Retries trip the circuit, then stop instead of hammering the model.
+	meta := New(ModelGPT4o).Fallback(ModelGPT4oMini).
+		RetryConfig(&RetryConfig{MaxRetries: 5, InitialDelay: time.Millisecond, MaxDelay: time.Millisecond, Multiplier: 1, RetryOnStatus: []int{503}}).
+		User("hi").SendWithMeta()
+	if meta.Error != nil || meta.Content != "fallback" {
+		t.Fatalf("expected fallback response, got %+v", meta)
+	}
+	if p.sendCalls != 3 {
+		t.Fatalf("expected 2 failed attempts and 1 fallback, got %d calls", p.sendCalls)
+	}
+
+	// With the circuit open, the primary is skipped without a request.
+	meta = New(ModelGPT4o).Fallback(ModelGPT4oMini).User("hi").SendWithMeta()
+	if meta.Error != nil || meta.Content != "fallback" {
+		t.Fatalf("expected fallback response, got %+v", meta)
+	}
+	if p.sendCalls != 4 {
+		t.Fatalf("expected only the fallback to be called, got %d calls", p.sendCalls)
+	}
+
+	meta = New(ModelGPT4o).User("hi").SendWithMeta()
+	if !errors.Is(meta.Error, ErrCircuitOpen) {
+		t.Fatalf("expected circuit open error without fallbacks, got %v", meta.Error)
+	}
+}
+
+func TestCircuitBreaker_ClientOption(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+	oldBreaker := Breaker
+	defer func() { Breaker = oldBreaker }()
+	Breaker = nil
+
+	p := &stubProvider{name: "acme", sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+		return nil, &ProviderError{Provider: "acme", Code: "503", Message: "overloaded"}
+	}}
+	cb := NewCircuitBreaker(BreakerConfig{MinRequests: 1, Cooldown: time.Hour})
+	client := NewClientWithProvider(p, WithBreaker(cb))
+
+	_ = client.New("acme/model").RetryConfig(noSleepRetryConfig(0)).User("hi").SendWithMeta()
+	if cb.State("acme", "acme/model") != CircuitOpen {
+		t.Fatal("expected the client's breaker to trip")
+	}
+	meta := client.New("acme/model").RetryConfig(noSleepRetryConfig(0)).User("hi").SendWithMeta()
+	if !errors.Is(meta.Error, ErrCircuitOpen) || p.sendCalls != 1 {
+		t.Fatalf("expected the open circuit to skip the request, got %v after %d calls", meta.Error, p.sendCalls)
+	}
+
+	// Other clients keep using the (unset) global Breaker.
+	other := NewClientWithProvider(p)
+	if meta := other.New("acme/model").RetryConfig(noSleepRetryConfig(0)).User("hi").SendWithMeta(); errors.Is(meta.Error, ErrCircuitOpen) {
+		t.Fatal("expected other clients to ignore the client's breaker")
+	}
+}
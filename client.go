@@ -2,8 +2,10 @@ package ai
 
 import (
 	"context"
-	"fmt"
 	"os"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -16,46 +18,89 @@ import (
 type Client struct {
 	provider     Provider
 	providerType ProviderType
+
+	settings   settings       // overrides of the package-level defaults
+	cache      *responseCache // own cache when the client sets Cache
+	middleware []Middleware   // wraps every provider call (see WithMiddleware)
+	fetcher    *MediaFetcher  // fetcher for documents extracted to text
+
+	chainsMu sync.Mutex
+	chains   map[Provider]Handler // middleware chain per provider
 }
 
 // NewClient creates a new Client for the specified provider type.
 // It accepts optional configuration options like API key, base URL, etc.
+// Built-in providers read their own environment variables (see the package
+// docs). For providers added with RegisterProvider, unset API keys and base
+// URLs are read from <NAME>_API_KEY and <NAME>_BASE_URL (e.g. MY_GATEWAY_API_KEY
+// for "my-gateway"). Unknown provider types fall back to OpenRouter.
 func NewClient(providerType ProviderType, opts ...ClientOption) *Client {
 	config := ProviderConfig{}
 	for _, opt := range opts {
 		opt(&config)
 	}
+	if !isBuiltinProvider(providerType) {
+		if config.APIKey == "" {
+			config.APIKey = os.Getenv(providerEnvPrefix(providerType) + "_API_KEY")
+		}
+		if config.BaseURL == "" {
+			config.BaseURL = os.Getenv(providerEnvPrefix(providerType) + "_BASE_URL")
+		}
+	}
+
+	factory, ok := lookupProviderFactory(providerType)
+	if !ok {
+		factory, _ = lookupProviderFactory(ProviderOpenRouter)
+	}
+
+	clientSettings, middleware := config.settings, config.middleware
+	config.settings, config.middleware = nil, nil // these belong to the client, not the provider
 
 	var provider Provider
-	switch providerType {
-	case ProviderOpenRouter:
-		provider = NewOpenRouterProvider(config)
-	case ProviderOpenAI:
-		provider = NewOpenAIProvider(config)
-	case ProviderAnthropic:
-		provider = NewAnthropicProvider(config)
-	case ProviderGoogle:
-		provider = NewGoogleProvider(config)
-	case ProviderOllama:
-		provider = NewOllamaProvider(config)
-	case ProviderAzure:
-		provider = NewAzureProvider(config)
-	default:
-		provider = NewOpenRouterProvider(config)
+	if config.pool != nil && len(config.pool.keys)+len(config.pool.configs) > 0 {
+		provider = newPoolProvider(providerType, factory, config)
+	} else {
+		config.pool = nil
+		provider = factory(config)
 	}
 
-	return &Client{
+	client := &Client{
 		provider:     provider,
 		providerType: providerType,
+		fetcher:      mediaFetcher(config),
 	}
+	client.applySettings(clientSettings)
+	client.middleware = middleware
+	return client
 }
 
 // NewClientWithProvider creates a client using a custom provider implementation.
 // Useful for testing or adding new providers without modifying the package.
-func NewClientWithProvider(provider Provider) *Client {
-	return &Client{
+// Only the settings options (WithDebug, WithHooks, ...), WithMiddleware and
+// WithMediaFetcher apply to custom providers.
+func NewClientWithProvider(provider Provider, opts ...ClientOption) *Client {
+	config := ProviderConfig{}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	client := &Client{
 		provider:     provider,
 		providerType: ProviderType(provider.Name()),
+		fetcher:      mediaFetcher(config),
+	}
+	client.applySettings(config.settings)
+	client.middleware = config.middleware
+	return client
+}
+
+// applySettings stores the settings collected from ClientOptions.
+func (c *Client) applySettings(s *settings) {
+	if s == nil {
+		return
+	}
+	c.settings = *s
+	if s.cache != nil {
+		c.cache = newResponseCache()
 	}
 }
 
@@ -64,6 +109,92 @@ func (c *Client) Provider() Provider {
 	return c.provider
 }
 
+// ═══════════════════════════════════════════════════════════════════════════
+// Provider Registry
+// ═══════════════════════════════════════════════════════════════════════════
+
+// ProviderFactory builds a provider from client configuration.
+type ProviderFactory func(config ProviderConfig) Provider
+
+var (
+	providersMu       sync.RWMutex
+	providerFactories = map[ProviderType]ProviderFactory{}
+	builtinProviders  = map[ProviderType]bool{} // registered by this package
+)
+
+func init() {
+	registerBuiltin(ProviderOpenRouter, func(c ProviderConfig) Provider { return NewOpenRouterProvider(c) })
+	registerBuiltin(ProviderOpenAI, func(c ProviderConfig) Provider { return NewOpenAIProvider(c) })
+	registerBuiltin(ProviderAnthropic, func(c ProviderConfig) Provider { return NewAnthropicProvider(c) })
+	registerBuiltin(ProviderGoogle, func(c ProviderConfig) Provider { return NewGoogleProvider(c) })
+	registerBuiltin(ProviderOllama, func(c ProviderConfig) Provider { return NewOllamaProvider(c) })
+	registerBuiltin(ProviderAzure, func(c ProviderConfig) Provider { return NewAzureProvider(c) })
+	registerBuiltin(ProviderBedrock, func(c ProviderConfig) Provider { return NewBedrockProvider(c) })
+	registerBuiltin(ProviderRouter, func(c ProviderConfig) Provider { return NewRouterProvider(c) })
+}
+
+// registerBuiltin registers one of the package's own providers. Built-ins
+// handle their environment themselves, so NewClient leaves it to them.
+func registerBuiltin(name ProviderType, factory ProviderFactory) {
+	RegisterProvider(name, factory)
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	builtinProviders[name] = true
+}
+
+func isBuiltinProvider(name ProviderType) bool {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	return builtinProviders[name]
+}
+
+// RegisterProvider makes a provider available to NewClient, SetDefaultProvider
+// and env-driven configuration under the given name. Registering an existing
+// name (including a built-in) replaces it.
+//
+//	ai.RegisterProvider("gateway", func(c ai.ProviderConfig) ai.Provider {
+//		return NewGatewayProvider(c)
+//	})
+//	ai.SetDefaultProvider("gateway") // key from GATEWAY_API_KEY
+func RegisterProvider(name ProviderType, factory ProviderFactory) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	providerFactories[name] = factory
+}
+
+// RegisteredProviders returns the names of all registered providers, sorted.
+func RegisteredProviders() []ProviderType {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+
+	names := make([]ProviderType, 0, len(providerFactories))
+	for name := range providerFactories {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
+
+func lookupProviderFactory(name ProviderType) (ProviderFactory, bool) {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	factory, ok := providerFactories[name]
+	return factory, ok
+}
+
+// providerEnvPrefix turns a provider name into an env var prefix ("my-gateway" → "MY_GATEWAY").
+func providerEnvPrefix(name ProviderType) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, string(name))
+}
+
 // ═══════════════════════════════════════════════════════════════════════════
 // Client Options (functional options pattern)
 // ═══════════════════════════════════════════════════════════════════════════
@@ -436,42 +567,6 @@ func (c *Client) Mistral() *Builder { return c.New(ModelMistralLarge) }
 // MistralLarge returns a Builder configured for ModelMistralLarge using this client's provider.
 func (c *Client) MistralLarge() *Builder { return c.New(ModelMistralLarge) }
 
-// ═══════════════════════════════════════════════════════════════════════════
-// Azure Provider (special constructor)
-// ═══════════════════════════════════════════════════════════════════════════
-
-// AzureProvider is a specialized OpenAI provider for Azure deployments.
-// It handles Azure-specific authentication and endpoint formatting.
-type AzureProvider struct {
-	*OpenAIProvider
-	deploymentURL string
-}
-
-// NewAzureProvider creates a new Azure OpenAI provider.
-// It requires a deployment URL and API key.
-// If the BaseURL is empty, it uses a placeholder that must be replaced.
-// If the APIKey is empty, it attempts to read from AZURE_OPENAI_API_KEY or OPENAI_API_KEY env vars.
-func NewAzureProvider(config ProviderConfig) *AzureProvider {
-	// Azure requires a custom endpoint
-	if config.BaseURL == "" {
-		// User must provide endpoint
-		config.BaseURL = "https://YOUR-RESOURCE.openai.azure.com/openai/deployments/YOUR-DEPLOYMENT"
-	}
-	if config.APIKey == "" {
-		config.APIKey = getEnvWithFallback("AZURE_OPENAI_API_KEY", "OPENAI_API_KEY")
-	}
-
-	return &AzureProvider{
-		OpenAIProvider: NewOpenAIProvider(config),
-		deploymentURL:  config.BaseURL,
-	}
-}
-
-// Name returns the provider identifier ("azure").
-func (p *AzureProvider) Name() string {
-	return "azure"
-}
-
 // Helper to get env with fallback
 func getEnvWithFallback(primary, fallback string) string {
 	if v := os.Getenv(primary); v != "" {
@@ -493,16 +588,16 @@ func Send(model Model, messages []Message, opts ...SendOptions) (string, *Respon
 		opt = opts[0]
 	}
 
+	client := getDefaultClient()
+	set := client.resolve(settings{})
+	ctx := withSettings(context.Background(), set)
+
 	// Check cache first
-	if cached, ok := getCached(model, messages, opt); ok {
-		if Debug {
-			printDebugCacheHit()
-		}
+	if cached, ok := getCached(set, model, messages, opt); ok {
+		logCacheHit(ctx, model)
 		return cached, &Response{}, nil
 	}
 
-	client := getDefaultClient()
-
 	req := &ProviderRequest{
 		Model:       string(model),
 		Messages:    messages,
@@ -510,22 +605,21 @@ func Send(model Model, messages []Message, opts ...SendOptions) (string, *Respon
 		Thinking:    opt.Thinking,
 	}
 
-	if Debug {
-		printDebugRequest(model, messages)
-	}
+	provider := client.providerFor(model)
+	logRequest(ctx, provider.Name(), model, messages)
 
-	waitForRateLimit()
-	resp, err := client.provider.Send(context.Background(), req)
+	if err := waitForRateLimit(ctx); err != nil {
+		return "", nil, err
+	}
+	start := time.Now()
+	resp, err := provider.Send(ctx, req)
+	logResult(ctx, provider.Name(), string(model), resp, err, time.Since(start))
 	if err != nil {
 		return "", nil, err
 	}
 
 	// Cache the response
-	setCached(model, messages, opt, resp.Content)
-
-	if Debug {
-		printDebugResponse(resp.Content, toResponse(resp))
-	}
+	setCached(set, model, messages, opt, resp.Content)
 
 	return resp.Content, toResponse(resp), nil
 }
@@ -539,6 +633,8 @@ func SendWithTools(model Model, messages []Message, tools []Tool, opts ...SendOp
 	}
 
 	client := getDefaultClient()
+	set := client.resolve(settings{})
+	ctx := withSettings(context.Background(), set)
 
 	req := &ProviderRequest{
 		Model:       string(model),
@@ -548,12 +644,15 @@ func SendWithTools(model Model, messages []Message, tools []Tool, opts ...SendOp
 		Tools:       tools,
 	}
 
-	if Debug {
-		printDebugRequest(model, messages)
-	}
+	provider := client.providerFor(model)
+	logRequest(ctx, provider.Name(), model, messages)
 
-	waitForRateLimit()
-	resp, err := client.provider.Send(context.Background(), req)
+	if err := waitForRateLimit(ctx); err != nil {
+		return "", nil, nil, err
+	}
+	start := time.Now()
+	resp, err := provider.Send(ctx, req)
+	logResult(ctx, provider.Name(), string(model), resp, err, time.Since(start))
 	if err != nil {
 		return "", nil, nil, err
 	}
@@ -588,8 +687,3 @@ func toResponse(pr *ProviderResponse) *Response {
 		},
 	}
 }
-
-// printDebugCacheHit prints cache hit message
-func printDebugCacheHit() {
-	fmt.Println(colorYellow("⚡ Cache hit"))
-}
@@ -73,15 +73,9 @@ func (c *CompareBuilder) On(models ...Model) []CompareResult {
 				b = b.With(c.vars)
 			}
 
-			// Temporarily disable pretty for parallel calls
-			oldPretty := Pretty
-			Pretty = false
-
 			msgs := b.User(c.prompt).buildMessages()
 			content, resp, err := Send(m, msgs)
 
-			Pretty = oldPretty
-
 			results[idx] = CompareResult{
 				Model:    m,
 				Response: content,
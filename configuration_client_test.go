@@ -84,3 +84,68 @@ func TestNewClient_AzureKeyFallsBackToOpenAI(t *testing.T) {
 		t.Fatalf("expected deployment URL to be set")
 	}
 }
+
+func TestRegisterProvider_CustomProviderViaEnv(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	var got ProviderConfig
+	RegisterProvider("my-gateway", func(c ProviderConfig) Provider {
+		got = c
+		return &stubProvider{name: "my-gateway"}
+	})
+	defer func() {
+		providersMu.Lock()
+		delete(providerFactories, "my-gateway")
+		providersMu.Unlock()
+	}()
+
+	t.Setenv("MY_GATEWAY_API_KEY", "gw-key")
+	t.Setenv("MY_GATEWAY_BASE_URL", "https://gateway.internal/v1")
+
+	SetDefaultProvider("my-gateway")
+	c := getDefaultClient()
+	if c.providerType != "my-gateway" || c.provider.Name() != "my-gateway" {
+		t.Fatalf("expected custom provider as default, got %s (%T)", c.providerType, c.provider)
+	}
+	if got.APIKey != "gw-key" || got.BaseURL != "https://gateway.internal/v1" {
+		t.Fatalf("expected env config, got %+v", got)
+	}
+
+	NewClient("my-gateway", WithAPIKey("explicit"))
+	if got.APIKey != "explicit" {
+		t.Fatalf("explicit options should win over env, got %q", got.APIKey)
+	}
+
+	found := false
+	for _, name := range RegisteredProviders() {
+		found = found || name == "my-gateway"
+	}
+	if !found {
+		t.Fatal("expected custom provider in RegisteredProviders")
+	}
+}
+
+func TestNewClient_BuiltinsIgnoreGenericBaseURL(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	t.Setenv("OPENAI_BASE_URL", "https://stray.example/v1")
+	t.Setenv("ANTHROPIC_BASE_URL", "https://stray.example")
+
+	if p := NewClient(ProviderOpenAI).provider.(*OpenAIProvider); p.config.BaseURL != openAIBaseURL {
+		t.Fatalf("built-in OpenAI should keep its base URL, got %q", p.config.BaseURL)
+	}
+	if p := NewClient(ProviderAnthropic).provider.(*AnthropicProvider); p.config.BaseURL != anthropicBaseURL {
+		t.Fatalf("built-in Anthropic should keep its base URL, got %q", p.config.BaseURL)
+	}
+}
+
+func TestNewClient_UnknownProviderFallsBackToOpenRouter(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	if _, ok := NewClient("nope").provider.(*OpenRouterProvider); !ok {
+		t.Fatal("expected OpenRouter fallback for unknown provider")
+	}
+}
@@ -11,8 +11,8 @@ import (
 
 // ModelPricing contains pricing per 1M tokens for a model.
 type ModelPricing struct {
-	InputPerMillion  float64 // USD per 1M input tokens
-	OutputPerMillion float64 // USD per 1M output tokens
+	InputPerMillion  float64 `json:"input_per_million"`  // USD per 1M input tokens
+	OutputPerMillion float64 `json:"output_per_million"` // USD per 1M output tokens
 }
 
 // ModelPricingMap maps models to their pricing.
@@ -154,7 +154,7 @@ var AudioPricingMap = map[string]float64{
 
 // CalculateCost calculates the estimated cost for a request in USD.
 func CalculateCost(model Model, promptTokens, completionTokens int) float64 {
-	pricing, ok := ModelPricingMap[model]
+	pricing, ok := lookupPricing(model)
 	if !ok {
 		// Use default pricing if model not found
 		pricing = ModelPricing{2.50, 10.00}
@@ -332,14 +332,23 @@ func PrintCostSummary() {
 // ═══════════════════════════════════════════════════════════════════════════
 
 // EstimatePromptCost estimates prompt cost in USD before sending, using a rough token heuristic.
+// Prefer Builder.EstimateCost, which counts tokens with the model's tokenizer.
 func EstimatePromptCost(model Model, promptChars int) float64 {
 	// Rough token estimate: 1 token ≈ 4 characters
-	estimatedTokens := promptChars / 4
+	return promptTokensCost(model, promptChars/4)
+}
+
+// EstimateCost estimates the prompt cost in USD of the request as built, using CountTokens.
+func (b *Builder) EstimateCost() float64 {
+	return promptTokensCost(b.model, b.CountTokens())
+}
+
+func promptTokensCost(model Model, tokens int) float64 {
 	inputPerMillion := 2.50
-	if pricing, ok := ModelPricingMap[model]; ok {
+	if pricing, ok := lookupPricing(model); ok {
 		inputPerMillion = pricing.InputPerMillion
 	}
-	return float64(estimatedTokens) / 1_000_000 * inputPerMillion
+	return float64(tokens) / 1_000_000 * inputPerMillion
 }
 
 // CheapestModel returns the cheapest model (by average in/out pricing) from a list.
@@ -352,7 +361,7 @@ func CheapestModel(models ...Model) Model {
 	cheapestCost := float64(999999)
 
 	for _, m := range models {
-		pricing, ok := ModelPricingMap[m]
+		pricing, ok := lookupPricing(m)
 		if !ok {
 			continue
 		}
@@ -376,7 +385,7 @@ func MostExpensiveModel(models ...Model) Model {
 	highestCost := float64(0)
 
 	for _, m := range models {
-		pricing, ok := ModelPricingMap[m]
+		pricing, ok := lookupPricing(m)
 		if !ok {
 			continue
 		}
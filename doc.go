@@ -32,7 +32,7 @@
 //	ai.Anthropic().Claude().Ask("Hello")
 //	ai.Google().Gemini().Ask("Hello")
 //	ai.Ollama().Use("llama3:8b").Ask("Hello")
-//	ai.Azure("https://YOUR-RESOURCE.openai.azure.com/openai/deployments/YOUR-DEPLOYMENT").GPT4o().Ask("Hello")
+//	ai.Azure("https://YOUR-RESOURCE.openai.azure.com").GPT4o().Ask("Hello")
 //
 // Prompt variables:
 //
@@ -0,0 +1,453 @@
+package ai
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Document Text Extraction
+// ═══════════════════════════════════════════════════════════════════════════
+
+// DocumentSection is one page or section of extracted document text.
+type DocumentSection struct {
+	Label string // e.g. "Page 3", "Section: Pricing", "Rows 1-100"
+	Text  string
+}
+
+// ExtractOptions controls how documents are turned into text parts.
+type ExtractOptions struct {
+	Always   bool  // Extract even when the provider reads the format natively
+	Pages    []int // Only include these 1-based pages/sections (nil = all)
+	MaxChars int   // Truncate each document's text to this many characters (0 = no limit)
+}
+
+// csvRowsPerSection is how many CSV rows go into one section.
+const csvRowsPerSection = 100
+
+// ExtractDocument extracts text sections from raw document bytes.
+// Supported types: PDF, DOCX, HTML, CSV, and plain text (including Markdown and JSON).
+func ExtractDocument(data []byte, mimeType string) ([]DocumentSection, error) {
+	switch mimeType {
+	case "application/pdf":
+		pages, err := extractPDFPages(data)
+		if err != nil {
+			return nil, err
+		}
+		sections := make([]DocumentSection, len(pages))
+		for i, text := range pages {
+			sections[i] = DocumentSection{Label: fmt.Sprintf("Page %d", i+1), Text: text}
+		}
+		return sections, nil
+	case mimeDOCX:
+		return extractDOCX(data)
+	case "text/html", "application/xhtml+xml":
+		return extractHTML(string(data)), nil
+	case "text/csv":
+		return extractCSV(data)
+	}
+	if isTextMime(mimeType) {
+		return []DocumentSection{{Text: strings.TrimSpace(string(data))}}, nil
+	}
+	if mimeType == "" {
+		return nil, fmt.Errorf("unknown document type")
+	}
+	return nil, fmt.Errorf("text extraction not supported for %s", mimeType)
+}
+
+// ExtractDocumentFile extracts text sections from a local file.
+// Useful for splitting a large document into per-page requests.
+func ExtractDocumentFile(path string) ([]DocumentSection, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ExtractDocument(data, detectDocMimeType(path))
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Builder Methods
+// ═══════════════════════════════════════════════════════════════════════════
+
+// ExtractDocuments sends attached documents as extracted text instead of files.
+// Without this, text extraction only happens automatically when the provider
+// lacks native support for a document's format.
+func (b *Builder) ExtractDocuments(opts ...ExtractOptions) *Builder {
+	o := ExtractOptions{Always: true}
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	b.extractOptions = &o
+	return b
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Request Preparation
+// ═══════════════════════════════════════════════════════════════════════════
+
+// documentCache holds the text extracted from each document of one request,
+// so fallback hops don't fetch and extract it again.
+type documentCache map[*DocumentRef][]ContentPart
+
+// prepareDocuments replaces document parts model can't read natively on
+// provider with text parts carrying page/section markers. Remote documents
+// are fetched with the client's MediaFetcher. msgs is not modified; a copy
+// is returned when anything changes. cache may be nil.
+func (b *Builder) prepareDocuments(ctx context.Context, client *Client, provider Provider, model Model, msgs []Message, cache documentCache) ([]Message, error) {
+	opts := ExtractOptions{}
+	if b.extractOptions != nil {
+		opts = *b.extractOptions
+	}
+	native := nativePDF(provider, model)
+	fetcher := client.fetcher
+	if fetcher == nil {
+		fetcher = DefaultMediaFetcher
+	}
+
+	prepared, copied := msgs, false
+	for i, msg := range msgs {
+		parts, ok := msg.Content.([]ContentPart)
+		if !ok {
+			continue
+		}
+		var out []ContentPart
+		changed := false
+		for _, part := range parts {
+			if part.Type != "document" || part.Document == nil {
+				out = append(out, part)
+				continue
+			}
+			if native && !opts.Always && part.Document.MimeType == "application/pdf" {
+				out = append(out, part)
+				continue
+			}
+			textParts, ok := cache[part.Document]
+			if !ok {
+				var err error
+				if textParts, err = extractDocumentParts(ctx, fetcher, part.Document, opts); err != nil {
+					return nil, err
+				}
+				if cache != nil {
+					cache[part.Document] = textParts
+				}
+			}
+			out = append(out, textParts...)
+			changed = true
+		}
+		if changed {
+			if !copied {
+				prepared, copied = append([]Message(nil), msgs...), true
+			}
+			prepared[i].Content = out
+		}
+	}
+	return prepared, nil
+}
+
+// nativePDF reports whether PDFs can be sent to model as files: the provider
+// must accept them and the catalog must list documents as an input modality.
+// Models the catalog has no modalities for go by the provider alone.
+func nativePDF(provider Provider, model Model) bool {
+	if !provider.Capabilities().PDF {
+		return false
+	}
+	if info, ok := LookupModel(model); ok && len(info.InputModalities) > 0 {
+		return info.Accepts(ModalityDocument)
+	}
+	return true
+}
+
+// extractDocumentParts turns one document into text parts, one per page/section.
+func extractDocumentParts(ctx context.Context, fetcher *MediaFetcher, doc *DocumentRef, opts ExtractOptions) ([]ContentPart, error) {
+	name := doc.Name
+	var data []byte
+	var err error
+	mimeType := doc.MimeType
+
+	if doc.Data != "" {
+		data, err = base64.StdEncoding.DecodeString(doc.Data)
+		if err != nil {
+			return nil, fmt.Errorf("document %s: invalid base64: %w", name, err)
+		}
+	} else {
+		var fetchedType string
+		data, fetchedType, err = fetcher.Fetch(ctx, doc.URL)
+		if err != nil {
+			return nil, err
+		}
+		if mimeType == "" {
+			mimeType = fetchedType
+		}
+		if name == "" {
+			name = filepath.Base(strings.SplitN(doc.URL, "?", 2)[0])
+		}
+	}
+	if name == "" {
+		name = "document"
+	}
+
+	sections, err := ExtractDocument(data, mimeType)
+	if err != nil {
+		return nil, fmt.Errorf("document %s: %w", name, err)
+	}
+
+	var parts []ContentPart
+	remaining := opts.MaxChars
+	for i, s := range sections {
+		if len(opts.Pages) > 0 && !containsInt(opts.Pages, i+1) {
+			continue
+		}
+		text := s.Text
+		if opts.MaxChars > 0 {
+			if remaining <= 0 {
+				break
+			}
+			if len(text) > remaining {
+				text = text[:runeCut(text, remaining)] + "\n[truncated]"
+			}
+			remaining -= len(s.Text)
+		}
+
+		header := "[Document: " + name
+		if s.Label != "" {
+			header += " — " + s.Label
+		}
+		header += "]"
+		parts = append(parts, ContentPart{Type: "text", Text: header + "\n" + text})
+	}
+	if len(parts) == 0 {
+		parts = append(parts, ContentPart{Type: "text", Text: "[Document: " + name + "]\n(no extractable text)"})
+	}
+	return parts, nil
+}
+
+func containsInt(list []int, v int) bool {
+	for _, x := range list {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Format Extractors
+// ═══════════════════════════════════════════════════════════════════════════
+
+const mimeDOCX = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+
+func isTextMime(mimeType string) bool {
+	return strings.HasPrefix(mimeType, "text/") ||
+		mimeType == "application/json" ||
+		mimeType == "application/xml"
+}
+
+// extractDOCX reads word/document.xml and starts a new section at each heading.
+func extractDOCX(data []byte) ([]DocumentSection, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DOCX: %w", err)
+	}
+	var body io.ReadCloser
+	for _, f := range zr.File {
+		if f.Name == "word/document.xml" {
+			body, err = f.Open()
+			if err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+	if body == nil {
+		return nil, fmt.Errorf("invalid DOCX: word/document.xml not found")
+	}
+	defer body.Close()
+
+	var sections []DocumentSection
+	current := DocumentSection{}
+	var para strings.Builder
+	heading := false
+
+	flush := func() {
+		text := strings.TrimSpace(para.String())
+		para.Reset()
+		if text == "" {
+			heading = false
+			return
+		}
+		if heading {
+			if strings.TrimSpace(current.Text) != "" || current.Label != "" {
+				current.Text = strings.TrimSpace(current.Text)
+				sections = append(sections, current)
+			}
+			current = DocumentSection{Label: "Section: " + text}
+			heading = false
+			return
+		}
+		current.Text += text + "\n"
+	}
+
+	dec := xml.NewDecoder(body)
+	inText := false
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid DOCX: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				para.WriteByte('\t')
+			case "br", "cr":
+				para.WriteByte('\n')
+			case "pStyle", "outlineLvl":
+				for _, a := range t.Attr {
+					if a.Name.Local == "val" && (t.Name.Local == "outlineLvl" || strings.HasPrefix(strings.ToLower(a.Value), "heading") || a.Value == "Title") {
+						heading = true
+					}
+				}
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				flush()
+			case "tc":
+				para.WriteString(" | ")
+			}
+		case xml.CharData:
+			if inText {
+				para.Write(t)
+			}
+		}
+	}
+	flush()
+	current.Text = strings.TrimSpace(current.Text)
+	if current.Text != "" || current.Label != "" {
+		sections = append(sections, current)
+	}
+	return sections, nil
+}
+
+var (
+	htmlSkipBlock = regexp.MustCompile(`(?is)<(script|style|noscript|template|svg)\b.*?</(script|style|noscript|template|svg)>|<!--.*?-->`)
+	htmlTag       = regexp.MustCompile(`(?s)<(/?)([a-zA-Z][a-zA-Z0-9]*)[^>]*>`)
+	htmlSpaces    = regexp.MustCompile(`[ \t\r\f\v]+`)
+	htmlNewlines  = regexp.MustCompile(`\n\s*\n+`)
+)
+
+// extractHTML strips markup and starts a new section at each h1-h3 heading.
+func extractHTML(src string) []DocumentSection {
+	src = htmlSkipBlock.ReplaceAllString(src, "")
+
+	var sections []DocumentSection
+	current := DocumentSection{}
+	var buf strings.Builder
+	var headingBuf *strings.Builder
+
+	emit := func() {
+		text := cleanHTMLText(buf.String())
+		buf.Reset()
+		if text != "" || current.Label != "" {
+			current.Text = text
+			sections = append(sections, current)
+		}
+	}
+
+	last := 0
+	for _, m := range htmlTag.FindAllStringSubmatchIndex(src, -1) {
+		text := src[last:m[0]]
+		if headingBuf != nil {
+			headingBuf.WriteString(text)
+		} else {
+			buf.WriteString(text)
+		}
+		last = m[1]
+
+		closing := src[m[2]:m[3]] == "/"
+		tag := strings.ToLower(src[m[4]:m[5]])
+		switch tag {
+		case "h1", "h2", "h3":
+			if !closing {
+				emit()
+				headingBuf = &strings.Builder{}
+			} else if headingBuf != nil {
+				current = DocumentSection{Label: "Section: " + cleanHTMLText(headingBuf.String())}
+				headingBuf = nil
+			}
+		case "br", "p", "div", "li", "tr", "h4", "h5", "h6", "section", "article", "header", "footer", "blockquote", "pre", "table", "ul", "ol":
+			buf.WriteByte('\n')
+		case "td", "th":
+			if closing {
+				buf.WriteString(" | ")
+			}
+		}
+	}
+	buf.WriteString(src[last:])
+	emit()
+	return sections
+}
+
+func cleanHTMLText(s string) string {
+	s = html.UnescapeString(s)
+	s = htmlSpaces.ReplaceAllString(s, " ")
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	return strings.TrimSpace(htmlNewlines.ReplaceAllString(strings.Join(lines, "\n"), "\n"))
+}
+
+// extractCSV renders rows as pipe-separated lines, repeating the header in each section.
+func extractCSV(data []byte) ([]DocumentSection, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	header := strings.Join(records[0], " | ")
+	rows := records[1:]
+	if len(rows) == 0 {
+		return []DocumentSection{{Text: header}}, nil
+	}
+
+	var sections []DocumentSection
+	for start := 0; start < len(rows); start += csvRowsPerSection {
+		end := start + csvRowsPerSection
+		if end > len(rows) {
+			end = len(rows)
+		}
+		lines := []string{header}
+		for _, row := range rows[start:end] {
+			lines = append(lines, strings.Join(row, " | "))
+		}
+		sections = append(sections, DocumentSection{
+			Label: fmt.Sprintf("Rows %d-%d", start+1, end),
+			Text:  strings.Join(lines, "\n"),
+		})
+	}
+	return sections, nil
+}
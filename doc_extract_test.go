@@ -0,0 +1,337 @@
+package ai
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// buildTestPDF assembles a minimal PDF with one Flate-compressed content stream per page.
+// The second font uses a ToUnicode CMap with 2-byte codes.
+func buildTestPDF(t *testing.T, pages ...string) []byte {
+	t.Helper()
+	var objs []string
+	add := func(s string) int {
+		objs = append(objs, s)
+		return len(objs)
+	}
+
+	cmap := "/CIDInit /ProcSet findresource begin\nbegincmap\n1 begincodespacerange <0000> <FFFF> endcodespacerange\n" +
+		"2 beginbfchar\n<0001> <0048>\n<0002> <0069>\nendbfchar\n1 beginbfrange\n<0010> <0012> <0061>\nendbfrange\nendcmap\n"
+	cmapObj := add(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(cmap), cmap))
+	font1 := add("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>")
+	font2 := add(fmt.Sprintf("<< /Type /Font /Subtype /Type0 /ToUnicode %d 0 R >>", cmapObj))
+
+	pagesObj := len(objs) + 1 + 2*len(pages) // reserve: content+page per page, then Pages
+	var kids []string
+	for _, content := range pages {
+		var z bytes.Buffer
+		w := zlib.NewWriter(&z)
+		_, _ = w.Write([]byte(content))
+		_ = w.Close()
+		c := add(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", z.Len(), z.String()))
+		p := add(fmt.Sprintf("<< /Type /Page /Parent %d 0 R /Contents %d 0 R >>", pagesObj, c))
+		kids = append(kids, fmt.Sprintf("%d 0 R", p))
+	}
+	add(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d /Resources << /Font << /F1 %d 0 R /F2 %d 0 R >> >> >>",
+		strings.Join(kids, " "), len(pages), font1, font2))
+	catalog := add(fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesObj))
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	for i, o := range objs {
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, o)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Root %d 0 R >>\n%%%%EOF\n", catalog)
+	return buf.Bytes()
+}
+
+func TestExtractPDFPages(t *testing.T) {
+	pdf := buildTestPDF(t,
+		"BT /F1 12 Tf 72 720 Td (Hello \\(PDF\\)) Tj 0 -14 Td [(Second) -300 (line)] TJ ET",
+		"BT /F2 12 Tf 72 720 Td <00010002> Tj ( ) Tj <001000110012> Tj ET",
+	)
+
+	sections, err := ExtractDocument(pdf, "application/pdf")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sections) != 2 {
+		t.Fatalf("expected 2 pages, got %d", len(sections))
+	}
+	if sections[0].Label != "Page 1" || sections[0].Text != "Hello (PDF)\nSecond line" {
+		t.Fatalf("unexpected page 1: %+v", sections[0])
+	}
+	if !strings.Contains(sections[1].Text, "Hi") || !strings.Contains(sections[1].Text, "abc") {
+		t.Fatalf("expected ToUnicode-mapped text on page 2, got %q", sections[1].Text)
+	}
+}
+
+func TestExtractPDFRejectsEncrypted(t *testing.T) {
+	if _, err := ExtractDocument([]byte("%PDF-1.7\ntrailer << /Encrypt 5 0 R >>"), "application/pdf"); err == nil {
+		t.Fatal("expected error for encrypted PDF")
+	}
+}
+
+// hostilePDFs have object stream and stream headers that must be skipped, not sliced with.
+var hostilePDFs = []string{
+	"%PDF-1.5\n1 0 obj\n<< /Type /ObjStm /N 1 /First -3 /Length 3 >>\nstream\nabc\nendstream\nendobj\n",
+	"%PDF-1.5\n1 0 obj\n<< /Type /ObjStm /N -5 /First 2 /Length 3 >>\nstream\nabc\nendstream\nendobj\n",
+	"%PDF-1.5\n1 0 obj\n<< /Type /ObjStm /N 99999999999 /First 3 /Length 3 >>\nstream\nabc\nendstream\nendobj\n",
+	"%PDF-1.5\n1 0 obj\n<< /Type /ObjStm /N 1 /First 1e300 /Length 3 >>\nstream\nabc\nendstream\nendobj\n",
+	"%PDF-1.5\n1 0 obj\n<< /Type /ObjStm /N 1 /First 4 /Length 8 >>\nstream\n2 -9 (x)\nendstream\nendobj\n",
+	"%PDF-1.5\n1 0 obj\n<< /Type /ObjStm /N 1 /First 5 /Length 8 >>\nstream\n2 999 (x)\nendstream\nendobj\n",
+	"%PDF-1.5\n1 0 obj\n<< /Length -10 >>\nstream\nabc\nendstream\nendobj\n",
+	"%PDF-1.5\n1 0 obj\n<< /Length 1e300 >>\nstream\nabc\nendstream\nendobj\n",
+	"%PDF-1.5\n1 0 obj\n<< /Length 99 >>\nstream\nabc",
+}
+
+func TestExtractPDFHostileHeaders(t *testing.T) {
+	for _, pdf := range hostilePDFs {
+		if _, err := extractPDFPages([]byte(pdf)); err != nil {
+			t.Errorf("unexpected error for %q: %v", pdf, err)
+		}
+	}
+}
+
+func TestExtractPDFLimits(t *testing.T) {
+	deep := "%PDF-1.4\n1 0 obj\n" + strings.Repeat("[", 1<<20) + "\nendobj\n"
+	if _, err := extractPDFPages([]byte(deep)); err != errPDFTooDeep {
+		t.Fatalf("expected nesting error, got %v", err)
+	}
+
+	var z bytes.Buffer
+	zw, _ := zlib.NewWriterLevel(&z, zlib.BestSpeed)
+	zeros := make([]byte, 1<<20)
+	for i := 0; i <= pdfMaxInflated>>20; i++ {
+		_, _ = zw.Write(zeros)
+	}
+	_ = zw.Close()
+	bomb := fmt.Sprintf("%%PDF-1.5\n1 0 obj\n<< /Type /ObjStm /N 1 /First 4 /Filter /FlateDecode /Length %d >>\nstream\n", z.Len()) +
+		z.String() + "\nendstream\nendobj\n"
+	if _, err := extractPDFPages([]byte(bomb)); err != errPDFTooLarge {
+		t.Fatalf("expected size limit error, got %v", err)
+	}
+}
+
+func FuzzExtractPDFPages(f *testing.F) {
+	for _, pdf := range hostilePDFs {
+		f.Add([]byte(pdf))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_, _ = extractPDFPages(data)
+	})
+}
+
+func TestExtractDOCXSections(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, _ := zw.Create("word/document.xml")
+	_, _ = w.Write([]byte(`<?xml version="1.0"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>
+<w:p><w:r><w:t>Intro text</w:t></w:r></w:p>
+<w:p><w:pPr><w:pStyle w:val="Heading1"/></w:pPr><w:r><w:t>Pricing</w:t></w:r></w:p>
+<w:p><w:r><w:t>Plan A</w:t></w:r><w:r><w:tab/><w:t>$10</w:t></w:r></w:p>
+</w:body></w:document>`))
+	_ = zw.Close()
+
+	sections, err := ExtractDocument(buf.Bytes(), mimeDOCX)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sections) != 2 {
+		t.Fatalf("expected 2 sections, got %+v", sections)
+	}
+	if sections[0].Text != "Intro text" {
+		t.Fatalf("unexpected first section: %+v", sections[0])
+	}
+	if sections[1].Label != "Section: Pricing" || sections[1].Text != "Plan A\t$10" {
+		t.Fatalf("unexpected second section: %+v", sections[1])
+	}
+}
+
+func TestExtractHTML(t *testing.T) {
+	src := `<html><head><style>p{color:red}</style><script>alert(1)</script></head>
+<body><p>Welcome &amp; hello</p><h2>Details</h2><ul><li>One</li><li>Two</li></ul></body></html>`
+
+	sections, err := ExtractDocument([]byte(src), "text/html")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sections) != 2 {
+		t.Fatalf("expected 2 sections, got %+v", sections)
+	}
+	if sections[0].Text != "Welcome & hello" {
+		t.Fatalf("unexpected intro: %q", sections[0].Text)
+	}
+	if sections[1].Label != "Section: Details" || sections[1].Text != "One\nTwo" {
+		t.Fatalf("unexpected details section: %+v", sections[1])
+	}
+}
+
+func TestExtractCSVSplitsRows(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString("id,name\n")
+	for i := 1; i <= 150; i++ {
+		fmt.Fprintf(&sb, "%d,item%d\n", i, i)
+	}
+
+	sections, err := ExtractDocument([]byte(sb.String()), "text/csv")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sections) != 2 || sections[1].Label != "Rows 101-150" {
+		t.Fatalf("unexpected sections: %d %q", len(sections), sections[len(sections)-1].Label)
+	}
+	if !strings.HasPrefix(sections[1].Text, "id | name\n101 | item101") {
+		t.Fatalf("expected header repeated in each section, got %q", sections[1].Text[:30])
+	}
+}
+
+func TestSendWithMeta_ExtractsDocumentsWhenProviderLacksPDF(t *testing.T) {
+	defer withTestGlobals(t)()
+
+	sp := &stubProvider{name: "stub", caps: ProviderCapabilities{PDF: false}}
+	client := &Client{provider: sp, providerType: ProviderOllama}
+
+	pdf := buildTestPDF(t, "BT /F1 12 Tf (First) Tj ET", "BT /F1 12 Tf (Second) Tj ET")
+	meta := client.New(ModelGPT5).PDFBase64(base64.StdEncoding.EncodeToString(pdf)).User("summarize").SendWithMeta()
+	if meta.Error != nil {
+		t.Fatalf("unexpected error: %v", meta.Error)
+	}
+
+	parts := sp.Requests()[0].Messages[0].Content.([]ContentPart)
+	if len(parts) != 3 {
+		t.Fatalf("expected prompt + 2 page parts, got %+v", parts)
+	}
+	for _, p := range parts {
+		if p.Type != "text" {
+			t.Fatalf("expected only text parts, got %q", p.Type)
+		}
+	}
+	if parts[2].Text != "[Document: document — Page 2]\nSecond" {
+		t.Fatalf("unexpected page part: %q", parts[2].Text)
+	}
+}
+
+func TestSendWithMeta_KeepsNativePDFUnlessForced(t *testing.T) {
+	defer withTestGlobals(t)()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "report.pdf")
+	if err := os.WriteFile(path, buildTestPDF(t, "BT (A) Tj ET", "BT (B) Tj ET", "BT (C) Tj ET"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	sp := &stubProvider{name: "stub", caps: ProviderCapabilities{PDF: true}}
+	client := &Client{provider: sp, providerType: ProviderAnthropic}
+
+	client.New(ModelClaudeSonnet).PDF(path).User("read").SendWithMeta()
+	parts := sp.Requests()[0].Messages[0].Content.([]ContentPart)
+	if parts[1].Type != "document" {
+		t.Fatalf("expected native document part, got %q", parts[1].Type)
+	}
+
+	client.New(ModelClaudeSonnet).PDF(path).ExtractDocuments(ExtractOptions{Always: true, Pages: []int{2}}).User("read").SendWithMeta()
+	parts = sp.Requests()[1].Messages[0].Content.([]ContentPart)
+	if len(parts) != 2 || parts[1].Text != "[Document: report.pdf — Page 2]\nB" {
+		t.Fatalf("expected only page 2 as text, got %+v", parts)
+	}
+}
+
+func TestSendWithMeta_ExtractsPDFForModelWithoutDocumentInput(t *testing.T) {
+	defer withTestGlobals(t)()
+
+	sp := &stubProvider{name: "stub", caps: ProviderCapabilities{PDF: true}}
+	client := &Client{provider: sp, providerType: ProviderOpenRouter}
+
+	pdf := base64.StdEncoding.EncodeToString(buildTestPDF(t, "BT (Only) Tj ET"))
+	client.New(ModelLlama4).PDFBase64(pdf).User("read").SendWithMeta()
+	parts := sp.Requests()[0].Messages[0].Content.([]ContentPart)
+	if parts[1].Type != "text" {
+		t.Fatalf("expected extracted text for a model without document input, got %q", parts[1].Type)
+	}
+}
+
+func TestSendWithMeta_FetchesDocumentsWithClientFetcher(t *testing.T) {
+	defer withTestGlobals(t)()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte(strings.Repeat("x", 100)))
+	}))
+	defer srv.Close()
+
+	sp := &stubProvider{name: "stub"}
+	client := NewClientWithProvider(sp, WithMediaFetcher(&MediaFetcher{MaxBytes: 10}))
+
+	meta := client.New(ModelGPT5).DocumentURL(srv.URL + "/notes.txt").User("read").SendWithMeta()
+	if meta.Error == nil || !strings.Contains(meta.Error.Error(), "exceeds limit of 10") {
+		t.Fatalf("expected the client's fetcher limit to apply, got %v", meta.Error)
+	}
+}
+
+func TestExtractDocumentParts_TruncatesOnRuneBoundary(t *testing.T) {
+	doc := &DocumentRef{Name: "notes.txt", MimeType: "text/plain", Data: base64.StdEncoding.EncodeToString([]byte("héllo"))}
+	parts, err := extractDocumentParts(context.Background(), DefaultMediaFetcher, doc, ExtractOptions{MaxChars: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parts[0].Text != "[Document: notes.txt]\nh\n[truncated]" {
+		t.Fatalf("unexpected truncation: %q", parts[0].Text)
+	}
+}
+
+func TestSendWithMeta_PreparesDocumentsPerFallback(t *testing.T) {
+	defer withTestGlobals(t)()
+
+	pdf := buildTestPDF(t, "BT (Hello) Tj ET")
+	var fetches int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		w.Header().Set("Content-Type", "application/pdf")
+		_, _ = w.Write(pdf)
+	}))
+	defer srv.Close()
+
+	fail := func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+		return nil, &ProviderError{Provider: "stub", Code: "503", Message: "overloaded"}
+	}
+	native := &stubProvider{name: "native", caps: ProviderCapabilities{PDF: true}, sendFn: fail}
+	local := &stubProvider{name: "local", sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+		if req.Model == "local/a" {
+			return fail(ctx, req)
+		}
+		return &ProviderResponse{Content: "ok"}, nil
+	}}
+	client := NewClientWithProvider(NewRouterProvider(ProviderConfig{},
+		RouteRule{Prefix: "anthropic/", Provider: native},
+		RouteRule{Prefix: "local/", Provider: local},
+	))
+
+	meta := client.New(ModelClaudeSonnet).Fallback("local/a", "local/b").
+		RetryConfig(noSleepRetryConfig(0)).PDFURL(srv.URL + "/report.pdf").User("read").SendWithMeta()
+	if meta.Error != nil || meta.Content != "ok" {
+		t.Fatalf("expected the last fallback to answer, got %+v", meta)
+	}
+	if parts := native.Requests()[0].Messages[0].Content.([]ContentPart); parts[1].Type != "document" {
+		t.Fatalf("expected the native hop to get the PDF, got %q", parts[1].Type)
+	}
+	for _, req := range local.Requests() {
+		if parts := req.Messages[0].Content.([]ContentPart); parts[1].Text != "[Document: report.pdf — Page 1]\nHello" {
+			t.Fatalf("expected extracted text for %s, got %+v", req.Model, parts[1])
+		}
+	}
+	if fetches != 1 {
+		t.Fatalf("expected the document to be extracted once, got %d fetches", fetches)
+	}
+}
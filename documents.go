@@ -12,11 +12,12 @@ import (
 // Document / PDF Input
 // ═══════════════════════════════════════════════════════════════════════════
 
-// DocumentInput represents a document (PDF) included in a request.
+// DocumentInput represents a document included in a request.
+// PDFs are sent natively where supported; other formats are sent as extracted text.
 type DocumentInput struct {
 	Data     string // base64-encoded data
 	URL      string // or URL (mutually exclusive with Data)
-	MimeType string // "application/pdf"
+	MimeType string // "application/pdf", "text/html", "text/csv", ...
 	Name     string // optional filename for context
 }
 
@@ -35,13 +36,14 @@ func (b *Builder) PDF(path string) *Builder {
 	encoded := base64.StdEncoding.EncodeToString(data)
 	b.documents = append(b.documents, DocumentInput{
 		Data:     encoded,
-		MimeType: detectDocMimeType(path),
+		MimeType: "application/pdf",
 		Name:     filepath.Base(path),
 	})
 	return b
 }
 
 // PDFURL adds a PDF document from a URL.
+// Providers without native URL support fetch and inline it (see MediaFetcher).
 func (b *Builder) PDFURL(url string) *Builder {
 	b.documents = append(b.documents, DocumentInput{
 		URL:      url,
@@ -67,41 +69,73 @@ func (b *Builder) PDFs(paths ...string) *Builder {
 	return b
 }
 
-// Document adds a document with auto-detected type (PDF, etc.).
+// Document adds a document with auto-detected type.
+// Supported: PDF, DOCX, HTML, CSV, and plain text (.txt, .md, .json, ...).
 func (b *Builder) Document(path string) *Builder {
 	ext := strings.ToLower(filepath.Ext(path))
-	switch ext {
-	case ".pdf":
+	if detectDocMimeType(path) == "" {
+		fmt.Printf("%s Unsupported document type: %s\n", colorYellow("⚠"), ext)
+		return b
+	}
+	if ext == ".pdf" {
 		return b.PDF(path)
-	default:
-		fmt.Printf("%s Unsupported document type: %s (only PDF supported)\n", colorYellow("⚠"), ext)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Printf("%s Error loading document %s: %v\n", colorRed("✗"), path, err)
 		return b
 	}
+	b.documents = append(b.documents, DocumentInput{
+		Data:     base64.StdEncoding.EncodeToString(data),
+		MimeType: detectDocMimeType(path),
+		Name:     filepath.Base(path),
+	})
+	return b
 }
 
 // DocumentURL adds a document from URL with auto-detected type.
+// Unknown extensions default to PDF.
 func (b *Builder) DocumentURL(url string) *Builder {
-	// Check URL extension
-	lower := strings.ToLower(url)
-	if strings.HasSuffix(lower, ".pdf") || strings.Contains(lower, ".pdf?") {
+	path := strings.SplitN(url, "?", 2)[0]
+	mimeType := detectDocMimeType(path)
+	if mimeType == "" || mimeType == "application/pdf" {
 		return b.PDFURL(url)
 	}
-	// Default to PDF
-	return b.PDFURL(url)
+	b.documents = append(b.documents, DocumentInput{
+		URL:      url,
+		MimeType: mimeType,
+	})
+	return b
 }
 
 // ═══════════════════════════════════════════════════════════════════════════
 // Internal Helpers
 // ═══════════════════════════════════════════════════════════════════════════
 
-// detectDocMimeType returns the MIME type based on file extension.
+// detectDocMimeType returns the MIME type based on file extension,
+// or "" for unsupported types.
 func detectDocMimeType(path string) string {
 	ext := strings.ToLower(filepath.Ext(path))
 	switch ext {
 	case ".pdf":
 		return "application/pdf"
+	case ".docx":
+		return mimeDOCX
+	case ".html", ".htm":
+		return "text/html"
+	case ".csv":
+		return "text/csv"
+	case ".md", ".markdown":
+		return "text/markdown"
+	case ".json":
+		return "application/json"
+	case ".xml":
+		return "application/xml"
+	case ".txt", ".text", ".log":
+		return "text/plain"
 	default:
-		return "application/pdf" // fallback
+		return ""
 	}
 }
 
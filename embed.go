@@ -135,6 +135,7 @@ func (e *EmbedBuilder) DoWithMeta() (*EmbeddingResponse, error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
+	ctx = withSettings(ctx, settingsOf(client))
 
 	// Check if provider supports embeddings
 	embedder, ok := client.provider.(Embedder)
@@ -148,17 +149,19 @@ func (e *EmbedBuilder) DoWithMeta() (*EmbeddingResponse, error) {
 		Dimensions: e.dimensions,
 	}
 
-	if Debug {
+	if debugOn(ctx) {
 		fmt.Printf("%s Embedding %d text(s) with %s\n", colorCyan("→"), len(e.texts), e.model)
 	}
 
-	waitForRateLimit()
+	if err := waitForRateLimit(ctx); err != nil {
+		return nil, err
+	}
 	resp, err := embedder.Embed(ctx, req)
 	if err != nil {
 		return nil, err
 	}
 
-	if Debug {
+	if debugOn(ctx) {
 		fmt.Printf("%s Got %d embedding(s), dim=%d, tokens=%d\n",
 			colorGreen("✓"), len(resp.Embeddings), resp.Dimensions, resp.TotalTokens)
 	}
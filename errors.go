@@ -0,0 +1,227 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Error Kinds
+// ═══════════════════════════════════════════════════════════════════════════
+
+// Provider failures are classified into these kinds. Match them with
+// errors.Is; use errors.As with *ProviderError for the status code, raw
+// provider code and RetryAfter. ErrContextTooLong is also reported for
+// context-length errors returned by the provider.
+var (
+	ErrRateLimited     = errors.New("rate limited")
+	ErrAuth            = errors.New("authentication failed")
+	ErrQuotaExhausted  = errors.New("quota exhausted")
+	ErrContentFiltered = errors.New("content filtered")
+	ErrInvalidRequest  = errors.New("invalid request")
+	ErrServerError     = errors.New("server error")
+	ErrTimeout         = errors.New("timeout")
+)
+
+// isTransient reports whether err is worth retrying: rate limits, server
+// errors and timeouts.
+func isTransient(err error) bool {
+	return errors.Is(err, ErrRateLimited) || errors.Is(err, ErrServerError) || errors.Is(err, ErrTimeout)
+}
+
+// classifyError maps an HTTP status, provider error code/type and message
+// to an error kind. Specific codes and messages win over the status, since
+// e.g. quota, context-length and content-filter errors share 400/429.
+func classifyError(status int, code, message string) error {
+	code = strings.ToLower(code)
+	msg := strings.ToLower(message)
+
+	switch code {
+	case "context_length_exceeded", "string_above_max_length":
+		return ErrContextTooLong
+	case "content_filter", "content_policy_violation", "responsible_ai_policy_violation":
+		return ErrContentFiltered
+	case "insufficient_quota", "billing_error", "billing_hard_limit_reached":
+		return ErrQuotaExhausted
+	case "rate_limit_exceeded", "rate_limit_error", "resource_exhausted", "throttlingexception",
+		"too_many_requests", "servicequotaexceededexception":
+		return ErrRateLimited
+	case "invalid_api_key", "authentication_error", "permission_error", "unauthenticated", "permission_denied",
+		"accessdeniedexception", "unrecognizedclientexception", "expiredtokenexception":
+		return ErrAuth
+	case "timeout", "deadline_exceeded", "modeltimeoutexception":
+		return ErrTimeout
+	case "api_error", "overloaded_error", "server_error", "internal", "unavailable",
+		"internalserverexception", "serviceunavailableexception", "modelnotreadyexception":
+		return ErrServerError
+	}
+
+	switch {
+	case strings.Contains(msg, "context length") || strings.Contains(msg, "context window") ||
+		strings.Contains(msg, "prompt is too long") || strings.Contains(msg, "maximum context") ||
+		strings.Contains(msg, "input is too long"):
+		return ErrContextTooLong
+	case strings.Contains(msg, "content management policy") || strings.Contains(msg, "content filter"):
+		return ErrContentFiltered
+	case strings.Contains(msg, "exceeded your current quota") || strings.Contains(msg, "credit balance"):
+		return ErrQuotaExhausted
+	}
+
+	switch {
+	case status == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return ErrAuth
+	case status == http.StatusPaymentRequired:
+		return ErrQuotaExhausted
+	case status == http.StatusRequestTimeout || status == http.StatusGatewayTimeout:
+		return ErrTimeout
+	case status >= 500:
+		return ErrServerError
+	case status >= 400:
+		return ErrInvalidRequest
+	}
+
+	switch code {
+	case "invalid_request_error", "invalid_argument", "not_found_error", "not_found", "failed_precondition",
+		"validationexception", "resourcenotfoundexception":
+		return ErrInvalidRequest
+	}
+	return nil
+}
+
+// kind returns the classified kind of e, or nil if it is unknown.
+func (e *ProviderError) kind() error {
+	if e.Kind != nil {
+		return e.Kind
+	}
+	status := e.StatusCode
+	code := e.Code
+	if n, err := strconv.Atoi(code); err == nil {
+		status, code = n, ""
+	}
+	if kind := classifyError(status, code, e.Message); kind != nil {
+		return kind
+	}
+	var netErr net.Error
+	if errors.Is(e.Err, context.DeadlineExceeded) || errors.As(e.Err, &netErr) && netErr.Timeout() {
+		return ErrTimeout
+	}
+	return nil
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// HTTP Error Parsing
+// ═══════════════════════════════════════════════════════════════════════════
+
+// newHTTPError builds a classified ProviderError from a non-200 response.
+// Code keeps the HTTP status; Message is the message from the error body
+// when one can be found.
+func newHTTPError(provider string, resp *http.Response, body []byte) *ProviderError {
+	code, message := parseErrorBody(body)
+	if message == "" {
+		message = string(body)
+	}
+	e := &ProviderError{
+		Provider:   provider,
+		Code:       strconv.Itoa(resp.StatusCode),
+		Message:    message,
+		StatusCode: resp.StatusCode,
+		Kind:       classifyError(resp.StatusCode, code, message),
+		RateLimit:  parseRateLimitHeaders(resp.Header),
+	}
+	if e.RateLimit != nil {
+		// Without Retry-After, a 429 can wait for the exhausted quota to reset
+		e.RetryAfter = e.RateLimit.wait()
+	}
+	return e
+}
+
+// parseErrorBody extracts the error code/type and message from the error
+// bodies used by OpenAI, Anthropic, Google and most compatible APIs.
+func parseErrorBody(body []byte) (code, message string) {
+	var shape struct {
+		Error   json.RawMessage `json:"error"`
+		Message string          `json:"message"`
+		Type    string          `json:"type"`
+		Code    json.RawMessage `json:"code"`
+		Detail  json.RawMessage `json:"detail"`
+	}
+	if json.Unmarshal(body, &shape) != nil {
+		return "", ""
+	}
+	code, message = rawCode(shape.Code), shape.Message
+	if code == "" {
+		code = shape.Type
+	}
+	if len(shape.Error) > 0 && json.Unmarshal(shape.Error, &message) != nil {
+		var obj struct {
+			Message string          `json:"message"`
+			Type    string          `json:"type"`
+			Code    json.RawMessage `json:"code"`
+			Status  json.RawMessage `json:"status"` // Google's status name; a number on Azure
+		}
+		if json.Unmarshal(shape.Error, &obj) == nil {
+			message = obj.Message
+			// Prefer the most specific identifier: OpenAI's code, Google's
+			// status, then the error type.
+			switch status := rawCode(obj.Status); {
+			case status != "" && !isNumber(status):
+				code = status
+			case rawCode(obj.Code) != "" && !isNumber(rawCode(obj.Code)):
+				code = rawCode(obj.Code)
+			default:
+				code = obj.Type
+			}
+		}
+	}
+	if message == "" && len(shape.Detail) > 0 {
+		if json.Unmarshal(shape.Detail, &message) != nil {
+			message = string(shape.Detail)
+		}
+	}
+	return code, message
+}
+
+// rawCode returns a JSON string or number as text; null yields "".
+func rawCode(raw json.RawMessage) string {
+	s := strings.Trim(string(raw), `"`)
+	if s == "null" {
+		return ""
+	}
+	return s
+}
+
+func isNumber(s string) bool {
+	_, err := strconv.Atoi(s)
+	return err == nil
+}
+
+// parseRetryAfter reads Retry-After (seconds or an HTTP date) and the
+// retry-after-ms header some providers send.
+func parseRetryAfter(h http.Header) time.Duration {
+	if ms := h.Get("Retry-After-Ms"); ms != "" {
+		if n, err := strconv.ParseFloat(ms, 64); err == nil && n > 0 {
+			return time.Duration(n * float64(time.Millisecond))
+		}
+	}
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	if n, err := strconv.ParseFloat(v, 64); err == nil && n > 0 {
+		return time.Duration(n * float64(time.Second))
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
@@ -0,0 +1,172 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestProviderErrors_ClassifiedFromResponses(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	cases := []struct {
+		name   string
+		status int
+		header map[string]string
+		body   string
+		send   func(url string) error
+		kind   error
+	}{
+		{
+			name:   "openai rate limit",
+			status: http.StatusTooManyRequests,
+			header: map[string]string{"Retry-After": "7"},
+			body:   `{"error":{"message":"Rate limit reached","type":"requests","code":"rate_limit_exceeded"}}`,
+			send:   sendOpenAI,
+			kind:   ErrRateLimited,
+		},
+		{
+			name:   "openai quota",
+			status: http.StatusTooManyRequests,
+			body:   `{"error":{"message":"You exceeded your current quota","type":"insufficient_quota","code":"insufficient_quota"}}`,
+			send:   sendOpenAI,
+			kind:   ErrQuotaExhausted,
+		},
+		{
+			name:   "openai context length",
+			status: http.StatusBadRequest,
+			body:   `{"error":{"message":"This model's maximum context length is 128000 tokens","type":"invalid_request_error","code":"context_length_exceeded"}}`,
+			send:   sendOpenAI,
+			kind:   ErrContextTooLong,
+		},
+		{
+			name:   "azure content filter",
+			status: http.StatusBadRequest,
+			body:   `{"error":{"message":"The response was filtered","code":"content_filter","status":400}}`,
+			send:   sendOpenAI,
+			kind:   ErrContentFiltered,
+		},
+		{
+			name:   "anthropic auth",
+			status: http.StatusUnauthorized,
+			body:   `{"type":"error","error":{"type":"authentication_error","message":"invalid x-api-key"}}`,
+			send: func(url string) error {
+				_, err := NewAnthropicProvider(ProviderConfig{APIKey: "k", BaseURL: url}).Send(context.Background(), testRequest())
+				return err
+			},
+			kind: ErrAuth,
+		},
+		{
+			name:   "anthropic overloaded",
+			status: 529,
+			body:   `{"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}`,
+			send: func(url string) error {
+				_, err := NewAnthropicProvider(ProviderConfig{APIKey: "k", BaseURL: url}).Send(context.Background(), testRequest())
+				return err
+			},
+			kind: ErrServerError,
+		},
+		{
+			name:   "google invalid argument",
+			status: http.StatusBadRequest,
+			body:   `{"error":{"code":400,"message":"Invalid JSON payload","status":"INVALID_ARGUMENT"}}`,
+			send: func(url string) error {
+				_, err := NewGoogleProvider(ProviderConfig{APIKey: "k", BaseURL: url}).Send(context.Background(), testRequest())
+				return err
+			},
+			kind: ErrInvalidRequest,
+		},
+		{
+			name:   "bedrock throttling",
+			status: http.StatusBadRequest,
+			header: map[string]string{"X-Amzn-ErrorType": "ThrottlingException:http://internal.amazon.com/coral/"},
+			body:   `{"message":"Too many requests"}`,
+			send: func(url string) error {
+				p := NewBedrockProvider(ProviderConfig{APIKey: "k", BaseURL: url, Region: "us-east-1"})
+				_, err := p.Send(context.Background(), testRequest())
+				return err
+			},
+			kind: ErrRateLimited,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for k, v := range tc.header {
+					w.Header().Set(k, v)
+				}
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			defer srv.Close()
+
+			err := tc.send(srv.URL)
+			if !errors.Is(err, tc.kind) {
+				t.Fatalf("expected %v, got %v", tc.kind, err)
+			}
+			var pe *ProviderError
+			if !errors.As(err, &pe) || pe.StatusCode != tc.status {
+				t.Fatalf("expected status %d, got %#v", tc.status, err)
+			}
+			if tc.header["Retry-After"] == "7" && pe.RetryAfter != 7*time.Second {
+				t.Fatalf("expected RetryAfter 7s, got %v", pe.RetryAfter)
+			}
+		})
+	}
+}
+
+func sendOpenAI(url string) error {
+	_, err := NewOpenAIProvider(ProviderConfig{APIKey: "k", BaseURL: url}).Send(context.Background(), testRequest())
+	return err
+}
+
+func testRequest() *ProviderRequest {
+	return &ProviderRequest{Model: string(ModelGPT4o), Messages: []Message{{Role: "user", Content: "hi"}}}
+}
+
+func TestRetry_UsesErrorKinds(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	config := noSleepRetryConfig(3)
+
+	// Body codes without a status still classify.
+	if !shouldRetry(config, &ProviderError{Provider: "x", Code: "overloaded_error", Message: "busy"}) {
+		t.Fatal("expected server errors to be retried")
+	}
+	// A 429 that is really an exhausted quota will not recover by waiting.
+	quota := &ProviderError{Provider: "x", Code: "429", Message: "x", Kind: ErrQuotaExhausted}
+	if shouldRetry(config, quota) {
+		t.Fatal("quota errors should not be retried")
+	}
+	// The message no longer matters once the kind is known.
+	if shouldRetry(config, &ProviderError{Provider: "x", Code: "401", Message: "timeout talking to auth server"}) {
+		t.Fatal("auth errors should not be retried")
+	}
+	if !shouldRetry(config, errors.New("read: connection reset by peer")) {
+		t.Fatal("unclassified transport errors should fall back to substrings")
+	}
+
+	// Retry-After overrides a shorter backoff, capped by MaxDelay.
+	config.MaxDelay = 20 * time.Millisecond
+	attempts := 0
+	start := time.Now()
+	_, err := WithRetry(context.Background(), config, func() (string, error) {
+		attempts++
+		if attempts == 1 {
+			return "", &ProviderError{Provider: "x", StatusCode: 429, Code: "429", RetryAfter: time.Hour}
+		}
+		return "ok", nil
+	})
+	if err != nil || attempts != 2 {
+		t.Fatalf("unexpected result: attempts=%d err=%v", attempts, err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond || elapsed > time.Second {
+		t.Fatalf("expected to wait MaxDelay, waited %v", elapsed)
+	}
+}
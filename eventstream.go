@@ -0,0 +1,104 @@
+package ai
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// AWS Event Stream Decoding (application/vnd.amazon.eventstream)
+// ═══════════════════════════════════════════════════════════════════════════
+
+// eventStreamMessage is one binary frame of an AWS event stream.
+// Only string headers (":event-type", ":message-type", ...) are kept.
+type eventStreamMessage struct {
+	Headers map[string]string
+	Payload []byte
+}
+
+const (
+	eventStreamPreludeLen = 12
+	eventStreamMaxLen     = 16 << 20
+)
+
+// readEventStreamMessage reads and CRC-checks the next frame. It returns
+// io.EOF at a clean end of stream.
+//
+// Frame layout: total length (4) | headers length (4) | prelude CRC (4) |
+// headers | payload | message CRC (4), all big-endian.
+func readEventStreamMessage(r io.Reader) (*eventStreamMessage, error) {
+	prelude := make([]byte, eventStreamPreludeLen)
+	if _, err := io.ReadFull(r, prelude); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return nil, fmt.Errorf("event stream: truncated prelude")
+		}
+		return nil, err
+	}
+
+	totalLen := binary.BigEndian.Uint32(prelude[0:4])
+	headersLen := binary.BigEndian.Uint32(prelude[4:8])
+	if crc32.ChecksumIEEE(prelude[0:8]) != binary.BigEndian.Uint32(prelude[8:12]) {
+		return nil, errors.New("event stream: prelude checksum mismatch")
+	}
+	// Compare in int64 so a huge headersLen cannot wrap around.
+	if totalLen > eventStreamMaxLen || int64(totalLen) < int64(eventStreamPreludeLen)+4+int64(headersLen) {
+		return nil, fmt.Errorf("event stream: invalid frame length %d", totalLen)
+	}
+
+	frame := make([]byte, totalLen)
+	copy(frame, prelude)
+	if _, err := io.ReadFull(r, frame[eventStreamPreludeLen:]); err != nil {
+		return nil, fmt.Errorf("event stream: truncated frame: %w", err)
+	}
+	crcAt := totalLen - 4
+	if crc32.ChecksumIEEE(frame[:crcAt]) != binary.BigEndian.Uint32(frame[crcAt:]) {
+		return nil, errors.New("event stream: message checksum mismatch")
+	}
+
+	headersEnd := eventStreamPreludeLen + headersLen
+	headers, err := parseEventStreamHeaders(frame[eventStreamPreludeLen:headersEnd])
+	if err != nil {
+		return nil, err
+	}
+	return &eventStreamMessage{Headers: headers, Payload: frame[headersEnd:crcAt]}, nil
+}
+
+// eventStreamValueLen gives the fixed size of each header value type;
+// -1 marks the length-prefixed types (bytes, string).
+var eventStreamValueLen = [...]int{0, 0, 1, 2, 4, 8, -1, -1, 8, 16}
+
+func parseEventStreamHeaders(b []byte) (map[string]string, error) {
+	headers := map[string]string{}
+	for len(b) > 0 {
+		nameLen := int(b[0])
+		if len(b) < 1+nameLen+1 {
+			return nil, errors.New("event stream: truncated header")
+		}
+		name := string(b[1 : 1+nameLen])
+		typ := int(b[1+nameLen])
+		b = b[2+nameLen:]
+
+		if typ >= len(eventStreamValueLen) {
+			return nil, fmt.Errorf("event stream: unknown header type %d", typ)
+		}
+		size := eventStreamValueLen[typ]
+		if size < 0 {
+			if len(b) < 2 {
+				return nil, errors.New("event stream: truncated header")
+			}
+			size = int(binary.BigEndian.Uint16(b))
+			b = b[2:]
+		}
+		if len(b) < size {
+			return nil, errors.New("event stream: truncated header value")
+		}
+		if typ == 7 {
+			headers[name] = string(b[:size])
+		}
+		b = b[size:]
+	}
+	return headers, nil
+}
@@ -16,16 +16,31 @@ type (
 	AfterResponseHook func(model Model, content string, duration time.Duration)
 	OnErrorHook       func(model Model, err error)
 	OnTokensHook      func(model Model, prompt, completion int)
+	CircuitHook       func(provider string, model Model, from, to CircuitState)
 )
 
+// Hooks is a set of lifecycle hooks. The package-level On* functions register
+// global hooks, used by clients without their own (see WithHooks).
+type Hooks struct {
+	mu sync.RWMutex
+
+	beforeRequest []BeforeRequestHook
+	afterResponse []AfterResponseHook
+	onError       []OnErrorHook
+	onTokens      []OnTokensHook
+}
+
+// NewHooks creates an empty set of hooks.
+func NewHooks() *Hooks {
+	return &Hooks{}
+}
+
 // Global hooks (can be set by users for observability).
 var (
-	hooksLock sync.RWMutex
+	globalHooks = NewHooks()
 
-	beforeRequestHooks []BeforeRequestHook
-	afterResponseHooks []AfterResponseHook
-	onErrorHooks       []OnErrorHook
-	onTokensHooks      []OnTokensHook
+	circuitLock  sync.RWMutex
+	circuitHooks []CircuitHook
 )
 
 // ═══════════════════════════════════════════════════════════════════════════
@@ -33,87 +48,129 @@ var (
 // ═══════════════════════════════════════════════════════════════════════════
 
 // OnBeforeRequest registers a hook called before each request.
-func OnBeforeRequest(hook BeforeRequestHook) {
-	hooksLock.Lock()
-	defer hooksLock.Unlock()
-	beforeRequestHooks = append(beforeRequestHooks, hook)
+func (h *Hooks) OnBeforeRequest(hook BeforeRequestHook) *Hooks {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.beforeRequest = append(h.beforeRequest, hook)
+	return h
 }
 
 // OnAfterResponse registers a hook called after each successful response.
-func OnAfterResponse(hook AfterResponseHook) {
-	hooksLock.Lock()
-	defer hooksLock.Unlock()
-	afterResponseHooks = append(afterResponseHooks, hook)
+func (h *Hooks) OnAfterResponse(hook AfterResponseHook) *Hooks {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.afterResponse = append(h.afterResponse, hook)
+	return h
 }
 
 // OnError registers a hook called on errors.
-func OnError(hook OnErrorHook) {
-	hooksLock.Lock()
-	defer hooksLock.Unlock()
-	onErrorHooks = append(onErrorHooks, hook)
+func (h *Hooks) OnError(hook OnErrorHook) *Hooks {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.onError = append(h.onError, hook)
+	return h
 }
 
 // OnTokens registers a hook called with token counts.
-func OnTokens(hook OnTokensHook) {
-	hooksLock.Lock()
-	defer hooksLock.Unlock()
-	onTokensHooks = append(onTokensHooks, hook)
+func (h *Hooks) OnTokens(hook OnTokensHook) *Hooks {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.onTokens = append(h.onTokens, hook)
+	return h
+}
+
+// Clear removes all hooks from the set.
+func (h *Hooks) Clear() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.beforeRequest = nil
+	h.afterResponse = nil
+	h.onError = nil
+	h.onTokens = nil
+}
+
+// OnBeforeRequest registers a global hook called before each request.
+func OnBeforeRequest(hook BeforeRequestHook) { globalHooks.OnBeforeRequest(hook) }
+
+// OnAfterResponse registers a global hook called after each successful response.
+func OnAfterResponse(hook AfterResponseHook) { globalHooks.OnAfterResponse(hook) }
+
+// OnError registers a global hook called on errors.
+func OnError(hook OnErrorHook) { globalHooks.OnError(hook) }
+
+// OnTokens registers a global hook called with token counts.
+func OnTokens(hook OnTokensHook) { globalHooks.OnTokens(hook) }
+
+// OnCircuitChange registers a hook called when a circuit breaker changes state.
+// Breakers can be shared by many clients, so circuit hooks are always global.
+func OnCircuitChange(hook CircuitHook) {
+	circuitLock.Lock()
+	defer circuitLock.Unlock()
+	circuitHooks = append(circuitHooks, hook)
 }
 
-// ClearHooks removes all registered hooks.
+// ClearHooks removes all registered global hooks.
 func ClearHooks() {
-	hooksLock.Lock()
-	defer hooksLock.Unlock()
-	beforeRequestHooks = nil
-	afterResponseHooks = nil
-	onErrorHooks = nil
-	onTokensHooks = nil
+	globalHooks.Clear()
+	circuitLock.Lock()
+	defer circuitLock.Unlock()
+	circuitHooks = nil
 }
 
 // ═══════════════════════════════════════════════════════════════════════════
 // Hook Invocation (called internally)
 // ═══════════════════════════════════════════════════════════════════════════
 
-func invokeBeforeRequest(model Model, messages []Message) {
-	hooksLock.RLock()
-	hooks := beforeRequestHooks
-	hooksLock.RUnlock()
+func (h *Hooks) invokeBeforeRequest(model Model, messages []Message) {
+	h.mu.RLock()
+	hooks := h.beforeRequest
+	h.mu.RUnlock()
 
 	for _, hook := range hooks {
 		hook(model, messages)
 	}
 }
 
-func invokeAfterResponse(model Model, content string, duration time.Duration) {
-	hooksLock.RLock()
-	hooks := afterResponseHooks
-	hooksLock.RUnlock()
+func (h *Hooks) invokeAfterResponse(model Model, content string, duration time.Duration) {
+	h.mu.RLock()
+	hooks := h.afterResponse
+	h.mu.RUnlock()
 
 	for _, hook := range hooks {
 		hook(model, content, duration)
 	}
 }
 
-func invokeOnError(model Model, err error) {
-	hooksLock.RLock()
-	hooks := onErrorHooks
-	hooksLock.RUnlock()
+func (h *Hooks) invokeOnError(model Model, err error) {
+	h.mu.RLock()
+	hooks := h.onError
+	h.mu.RUnlock()
 
 	for _, hook := range hooks {
 		hook(model, err)
 	}
 }
 
-func invokeOnTokens(model Model, prompt, completion int) {
-	hooksLock.RLock()
-	hooks := onTokensHooks
-	hooksLock.RUnlock()
+func (h *Hooks) invokeOnTokens(model Model, prompt, completion int) {
+	h.mu.RLock()
+	hooks := h.onTokens
+	h.mu.RUnlock()
 
 	for _, hook := range hooks {
 		hook(model, prompt, completion)
 	}
 }
 
+func invokeOnCircuitChange(provider string, model Model, from, to CircuitState) {
+	circuitLock.RLock()
+	hooks := circuitHooks
+	circuitLock.RUnlock()
+
+	for _, hook := range hooks {
+		hook(provider, model, from, to)
+	}
+}
+
 // ═══════════════════════════════════════════════════════════════════════════
 // Common Hook Examples (optional utilities)
 // ═══════════════════════════════════════════════════════════════════════════
@@ -181,9 +238,14 @@ func (m *MetricsCollector) ErrorHook() OnErrorHook {
 	}
 }
 
-// Register registers all hooks for this collector.
+// Register registers all hooks for this collector globally.
 func (m *MetricsCollector) Register() {
-	OnAfterResponse(m.Hook())
-	OnTokens(m.TokenHook())
-	OnError(m.ErrorHook())
+	m.RegisterWith(globalHooks)
+}
+
+// RegisterWith registers all hooks for this collector on h.
+func (m *MetricsCollector) RegisterWith(h *Hooks) {
+	h.OnAfterResponse(m.Hook())
+	h.OnTokens(m.TokenHook())
+	h.OnError(m.ErrorHook())
 }
@@ -0,0 +1,529 @@
+package ai
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif" // register GIF decoder
+	"image/jpeg"
+	"image/png"
+	"math"
+	"net/url"
+	"strings"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Image Preprocessing Pipeline
+// ═══════════════════════════════════════════════════════════════════════════
+
+// ImageFormat is the output encoding used by the image preprocessing pipeline.
+type ImageFormat string
+
+const (
+	ImageFormatKeep ImageFormat = ""     // Keep JPEG/PNG as-is (GIF is re-encoded as PNG)
+	ImageFormatJPEG ImageFormat = "jpeg" // Smallest payloads, no transparency
+	ImageFormatPNG  ImageFormat = "png"  // Lossless, keeps transparency
+)
+
+// ImageOptions configures the optional image preprocessing pipeline.
+// Re-encoding always drops embedded metadata such as EXIF (including GPS tags);
+// the EXIF orientation is applied to the pixels first.
+type ImageOptions struct {
+	MaxWidth   int         // Maximum width in pixels (0 = provider default)
+	MaxHeight  int         // Maximum height in pixels (0 = provider default)
+	Format     ImageFormat // Output format (default: keep source format)
+	Quality    int         // JPEG quality 1-100 (default: 85)
+	AutoDetail bool        // Pick ImageDetailLow/High from the processed size when detail is "auto"
+}
+
+// DefaultImageOptions returns the options used when PreprocessImages is called without arguments.
+func DefaultImageOptions() ImageOptions {
+	return ImageOptions{
+		Quality:    85,
+		AutoDetail: true,
+	}
+}
+
+// imageLimit is the recommended maximum image size for a provider.
+type imageLimit struct {
+	maxWidth  int
+	maxHeight int
+}
+
+// imageLimits holds the per-provider maximum dimensions. Larger images are
+// downscaled server-side anyway, so sending them only costs bandwidth.
+var imageLimits = map[ProviderType]imageLimit{
+	ProviderOpenAI:    {2048, 2048},
+	ProviderAzure:     {2048, 2048},
+	ProviderAnthropic: {1568, 1568},
+	ProviderGoogle:    {3072, 3072},
+	ProviderOllama:    {1024, 1024},
+}
+
+const defaultImageLimit = 2048
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Builder Methods
+// ═══════════════════════════════════════════════════════════════════════════
+
+// PreprocessImages enables the image preprocessing pipeline for this builder.
+// Local and base64 images are downscaled to the provider's maximum dimensions,
+// re-encoded (stripping EXIF), and optionally assigned a detail level.
+// Images attached before this call are processed as well.
+func (b *Builder) PreprocessImages(opts ...ImageOptions) *Builder {
+	o := DefaultImageOptions()
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	b.imageOptions = &o
+
+	for i, img := range b.images {
+		processed, err := b.preprocessImage(img)
+		if err != nil {
+			fmt.Printf("%s Error preprocessing image %d: %v\n", colorRed("✗"), i+1, err)
+			continue
+		}
+		b.images[i] = processed
+	}
+	return b
+}
+
+// ImageTokens returns the estimated prompt tokens consumed by the attached images.
+// Remote image URLs are not fetched and count as zero.
+func (b *Builder) ImageTokens() int {
+	total := 0
+	for _, est := range b.ImageEstimates() {
+		total += est.Tokens
+	}
+	return total
+}
+
+// ImageEstimate describes an attached image and its estimated token cost.
+type ImageEstimate struct {
+	Index  int    // position in the builder's image list
+	Width  int    // pixel width (0 if unknown)
+	Height int    // pixel height (0 if unknown)
+	Detail string // detail level sent to the provider
+	Tokens int    // estimated prompt tokens (0 if unknown)
+}
+
+// ImageEstimates returns a per-image token estimate for the target provider.
+func (b *Builder) ImageEstimates() []ImageEstimate {
+	return b.imageEstimatesFor(b.model)
+}
+
+// imageEstimatesFor estimates image tokens as if the request targeted model.
+func (b *Builder) imageEstimatesFor(model Model) []ImageEstimate {
+	vendor := imageVendor(b.providerType(), model)
+	out := make([]ImageEstimate, 0, len(b.images))
+	for i, img := range b.images {
+		w, h := img.Width, img.Height
+		if w == 0 || h == 0 {
+			w, h = dataURIDimensions(img.URL)
+		}
+		out = append(out, ImageEstimate{
+			Index:  i,
+			Width:  w,
+			Height: h,
+			Detail: img.Detail,
+			Tokens: EstimateImageTokens(vendor, w, h, ImageDetail(img.Detail)),
+		})
+	}
+	return out
+}
+
+// providerType returns the provider type this builder will send to.
+func (b *Builder) providerType() ProviderType {
+	client := b.client
+	if client == nil {
+		if DefaultProvider != ProviderRouter {
+			return DefaultProvider
+		}
+		client = getDefaultClient()
+	}
+	if r, ok := client.provider.(*RouterProvider); ok {
+		return ProviderType(r.ProviderFor(b.model).Name())
+	}
+	return client.providerType
+}
+
+// preprocessImage runs the pipeline on a single image if it is an inline data URI.
+func (b *Builder) preprocessImage(img ImageInput) (ImageInput, error) {
+	if b.imageOptions == nil || !strings.HasPrefix(img.URL, "data:") {
+		return img, nil
+	}
+
+	mimeType, data, err := decodeDataURI(img.URL)
+	if err != nil {
+		return img, err
+	}
+
+	opts := *b.imageOptions
+	vendor := imageVendor(b.providerType(), b.model)
+	limit, ok := imageLimits[vendor]
+	if !ok {
+		limit = imageLimit{defaultImageLimit, defaultImageLimit}
+	}
+	if opts.MaxWidth <= 0 {
+		opts.MaxWidth = limit.maxWidth
+	}
+	if opts.MaxHeight <= 0 {
+		opts.MaxHeight = limit.maxHeight
+	}
+
+	out, outMime, w, h, err := processImage(data, mimeType, opts)
+	if errors.Is(err, image.ErrFormat) {
+		return img, nil // no decoder for this format (e.g. WebP): send as-is
+	}
+	if err != nil {
+		return img, err
+	}
+
+	img.URL = fmt.Sprintf("data:%s;base64,%s", outMime, base64.StdEncoding.EncodeToString(out))
+	img.Width = w
+	img.Height = h
+	if opts.AutoDetail && (img.Detail == "" || img.Detail == string(ImageDetailAuto)) {
+		img.Detail = string(pickImageDetail(w, h))
+	}
+	return img, nil
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Token Estimates
+// ═══════════════════════════════════════════════════════════════════════════
+
+// EstimateImageTokens estimates the prompt tokens an image of the given size costs
+// on a provider. It follows each vendor's published sizing rules and returns 0
+// when the dimensions are unknown.
+func EstimateImageTokens(provider ProviderType, width, height int, detail ImageDetail) int {
+	if width <= 0 || height <= 0 {
+		return 0
+	}
+
+	switch provider {
+	case ProviderAnthropic:
+		// Claude: images are scaled to fit 1568px, then cost ≈ (w*h)/750 tokens.
+		w, h := fitWithin(width, height, 1568, 1568)
+		return int(math.Ceil(float64(w*h) / 750))
+
+	case ProviderGoogle:
+		// Gemini: 258 tokens for images up to 384x384, otherwise 258 per 768x768 tile.
+		if width <= 384 && height <= 384 {
+			return 258
+		}
+		tiles := ceilDiv(width, 768) * ceilDiv(height, 768)
+		return 258 * tiles
+
+	default:
+		// OpenAI tile model (also a reasonable default for OpenAI-compatible hosts).
+		if detail == ImageDetailLow {
+			return 85
+		}
+		w, h := fitWithin(width, height, 2048, 2048)
+		if short := minInt(w, h); short > 768 {
+			scale := 768 / float64(short)
+			w = int(float64(w) * scale)
+			h = int(float64(h) * scale)
+		}
+		tiles := ceilDiv(w, 512) * ceilDiv(h, 512)
+		return 170*tiles + 85
+	}
+}
+
+// imageVendor maps a provider/model pair to the vendor whose image rules apply.
+// OpenRouter forwards images to the underlying vendor, so the model namespace decides.
+func imageVendor(provider ProviderType, model Model) ProviderType {
+	if provider != ProviderOpenRouter {
+		return provider
+	}
+	switch {
+	case strings.HasPrefix(string(model), "anthropic/"):
+		return ProviderAnthropic
+	case strings.HasPrefix(string(model), "google/"):
+		return ProviderGoogle
+	default:
+		return ProviderOpenAI
+	}
+}
+
+// pickImageDetail chooses a detail level from the processed image size.
+// Small images gain nothing from high detail, so they are sent as low.
+func pickImageDetail(width, height int) ImageDetail {
+	if width <= 512 && height <= 512 {
+		return ImageDetailLow
+	}
+	return ImageDetailHigh
+}
+
+// dataURIDimensions returns the pixel size of an inline image, or zeros if unknown.
+func dataURIDimensions(uri string) (int, int) {
+	if !strings.HasPrefix(uri, "data:") {
+		return 0, 0
+	}
+	_, data, err := decodeDataURI(uri)
+	if err != nil {
+		return 0, 0
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return 0, 0
+	}
+	return cfg.Width, cfg.Height
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Internal Helpers
+// ═══════════════════════════════════════════════════════════════════════════
+
+// decodeDataURI splits a data URI into its MIME type and raw bytes.
+func decodeDataURI(uri string) (string, []byte, error) {
+	mimeType, payload, isBase64, ok := parseDataURI(uri)
+	if !ok {
+		return "", nil, fmt.Errorf("invalid data URI")
+	}
+	if !isBase64 {
+		raw, err := url.PathUnescape(payload)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid percent-encoded payload: %w", err)
+		}
+		return mimeType, []byte(raw), nil
+	}
+	data, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid base64 payload: %w", err)
+	}
+	return mimeType, data, nil
+}
+
+// processImage decodes, downscales, and re-encodes an image.
+// It returns the encoded bytes, their MIME type, and the final dimensions.
+func processImage(data []byte, mimeType string, opts ImageOptions) ([]byte, string, int, int, error) {
+	src, srcFormat, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", 0, 0, fmt.Errorf("decode %s: %w", mimeType, err)
+	}
+	if srcFormat == "jpeg" {
+		src = orientImage(src, jpegOrientation(data))
+	}
+
+	bounds := src.Bounds()
+	w, h := fitWithin(bounds.Dx(), bounds.Dy(), opts.MaxWidth, opts.MaxHeight)
+	if w != bounds.Dx() || h != bounds.Dy() {
+		src = resizeImage(src, w, h)
+	}
+
+	format := opts.Format
+	if format == ImageFormatKeep {
+		format = ImageFormatPNG
+		if srcFormat == "jpeg" {
+			format = ImageFormatJPEG
+		}
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case ImageFormatJPEG:
+		quality := opts.Quality
+		if quality <= 0 || quality > 100 {
+			quality = 85
+		}
+		if err := jpeg.Encode(&buf, flattenImage(src), &jpeg.Options{Quality: quality}); err != nil {
+			return nil, "", 0, 0, err
+		}
+		return buf.Bytes(), "image/jpeg", w, h, nil
+	default:
+		if err := png.Encode(&buf, src); err != nil {
+			return nil, "", 0, 0, err
+		}
+		return buf.Bytes(), "image/png", w, h, nil
+	}
+}
+
+// jpegOrientation returns the EXIF Orientation (1-8) of a JPEG, or 1 when absent.
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 1
+		}
+		switch marker := data[i+1]; {
+		case marker == 0xFF: // fill byte
+			i++
+			continue
+		case marker == 0x01 || marker >= 0xD0 && marker <= 0xD7: // no length
+			i += 2
+			continue
+		case marker == 0xDA || marker == 0xD9: // metadata comes before the scan
+			return 1
+		}
+		size := int(binary.BigEndian.Uint16(data[i+2:]))
+		if size < 2 || i+2+size > len(data) {
+			return 1
+		}
+		if data[i+1] == 0xE1 {
+			if o := exifOrientation(data[i+4 : i+2+size]); o != 0 {
+				return o
+			}
+		}
+		i += 2 + size
+	}
+	return 1
+}
+
+// exifOrientation reads the Orientation tag from IFD0 of an APP1 Exif payload,
+// returning 0 when the payload has none.
+func exifOrientation(seg []byte) int {
+	if len(seg) < 14 || string(seg[:6]) != "Exif\x00\x00" {
+		return 0
+	}
+	tiff := seg[6:]
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+	ifd := int64(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > int64(len(tiff)) {
+		return 0
+	}
+	n := int(order.Uint16(tiff[ifd:]))
+	for j := 0; j < n; j++ {
+		e := int(ifd) + 2 + j*12
+		if e+12 > len(tiff) {
+			return 0
+		}
+		if order.Uint16(tiff[e:]) == 0x0112 {
+			if o := int(order.Uint16(tiff[e+8:])); o >= 1 && o <= 8 {
+				return o
+			}
+			return 0
+		}
+	}
+	return 0
+}
+
+// orientImage rotates/flips src so it displays upright for an EXIF orientation.
+func orientImage(src image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return src
+	}
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // mirrored
+				dx, dy = w-1-x, y
+			case 3: // rotated 180°
+				dx, dy = w-1-x, h-1-y
+			case 4: // flipped
+				dx, dy = x, h-1-y
+			case 5: // transposed
+				dx, dy = y, x
+			case 6: // needs 90° clockwise
+				dx, dy = h-1-y, x
+			case 7: // transversed
+				dx, dy = h-1-y, w-1-x
+			case 8: // needs 90° counter-clockwise
+				dx, dy = y, w-1-x
+			}
+			dst.Set(dx, dy, src.At(b.Min.X+x, b.Min.Y+y))
+		}
+	}
+	return dst
+}
+
+// fitWithin scales (w, h) down to fit inside (maxW, maxH), preserving aspect ratio.
+func fitWithin(w, h, maxW, maxH int) (int, int) {
+	if maxW <= 0 || maxH <= 0 || (w <= maxW && h <= maxH) {
+		return w, h
+	}
+	scale := math.Min(float64(maxW)/float64(w), float64(maxH)/float64(h))
+	nw := int(math.Round(float64(w) * scale))
+	nh := int(math.Round(float64(h) * scale))
+	if nw < 1 {
+		nw = 1
+	}
+	if nh < 1 {
+		nh = 1
+	}
+	return nw, nh
+}
+
+// resizeImage downscales src to w x h using an area-averaging (box) filter.
+func resizeImage(src image.Image, w, h int) *image.NRGBA {
+	sb := src.Bounds()
+	sw, sh := sb.Dx(), sb.Dy()
+	dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+
+	for y := 0; y < h; y++ {
+		y0 := sb.Min.Y + y*sh/h
+		y1 := sb.Min.Y + (y+1)*sh/h
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+		for x := 0; x < w; x++ {
+			x0 := sb.Min.X + x*sw/w
+			x1 := sb.Min.X + (x+1)*sw/w
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r += uint64(cr)
+					g += uint64(cg)
+					bl += uint64(cb)
+					a += uint64(ca)
+					n++
+				}
+			}
+			dst.Set(x, y, color.RGBA64{
+				R: uint16(r / n),
+				G: uint16(g / n),
+				B: uint16(bl / n),
+				A: uint16(a / n),
+			})
+		}
+	}
+	return dst
+}
+
+// flattenImage composites an image onto a white background (JPEG has no alpha).
+func flattenImage(src image.Image) image.Image {
+	b := src.Bounds()
+	dst := image.NewRGBA(b)
+	draw.Draw(dst, b, image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(dst, b, src, b.Min, draw.Over)
+	return dst
+}
+
+func ceilDiv(a, b int) int {
+	return (a + b - 1) / b
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
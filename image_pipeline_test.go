@@ -0,0 +1,175 @@
+package ai
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testPNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.NRGBA{uint8(x), uint8(y), 128, 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestPreprocessImagesDownscalesToProviderLimit(t *testing.T) {
+	defer withTestGlobals(t)()
+	SetDefaultProvider(ProviderAnthropic)
+
+	path := filepath.Join(t.TempDir(), "big.png")
+	if err := os.WriteFile(path, testPNG(t, 3136, 1000), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	b := New(ModelClaudeSonnet).PreprocessImages().Image(path)
+	if len(b.images) != 1 {
+		t.Fatalf("expected 1 image, got %d", len(b.images))
+	}
+	img := b.images[0]
+	if img.Width != 1568 || img.Height != 500 {
+		t.Fatalf("expected 1568x500, got %dx%d", img.Width, img.Height)
+	}
+	if !strings.HasPrefix(img.URL, "data:image/png;base64,") {
+		t.Fatalf("expected png data URI, got %q", img.URL[:30])
+	}
+	if img.Detail != string(ImageDetailHigh) {
+		t.Fatalf("expected auto-picked high detail, got %q", img.Detail)
+	}
+	if got, want := b.ImageTokens(), 1046; got != want {
+		t.Fatalf("expected %d tokens, got %d", want, got)
+	}
+}
+
+func TestPreprocessImagesAppliesToExistingImages(t *testing.T) {
+	defer withTestGlobals(t)()
+	SetDefaultProvider(ProviderOpenAI)
+
+	data := base64.StdEncoding.EncodeToString(testPNG(t, 300, 200))
+	b := New(ModelGPT5).ImageBase64(data, "image/png").
+		PreprocessImages(ImageOptions{Format: ImageFormatJPEG, Quality: 70, AutoDetail: true})
+
+	img := b.images[0]
+	if !strings.HasPrefix(img.URL, "data:image/jpeg;base64,") {
+		t.Fatalf("expected jpeg data URI")
+	}
+	if img.Detail != string(ImageDetailLow) {
+		t.Fatalf("expected low detail for small image, got %q", img.Detail)
+	}
+
+	_, raw, err := decodeDataURI(img.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := jpeg.Decode(bytes.NewReader(raw)); err != nil {
+		t.Fatalf("output is not a valid jpeg: %v", err)
+	}
+	if got := b.ImageTokens(); got != 85 {
+		t.Fatalf("expected 85 tokens for low detail, got %d", got)
+	}
+}
+
+func TestPreprocessImagesLeavesUnsupportedFormats(t *testing.T) {
+	defer withTestGlobals(t)()
+
+	b := New(ModelGPT5).PreprocessImages().ImageBase64("UklGRg==", "image/webp")
+	if b.images[0].URL != "data:image/webp;base64,UklGRg==" {
+		t.Fatalf("unsupported image should be sent unchanged, got %q", b.images[0].URL)
+	}
+}
+
+func TestEstimateImageTokens(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider ProviderType
+		w, h     int
+		detail   ImageDetail
+		want     int
+	}{
+		{"openai low", ProviderOpenAI, 4000, 3000, ImageDetailLow, 85},
+		{"openai 1024 square", ProviderOpenAI, 1024, 1024, ImageDetailHigh, 765},
+		{"openai 2048x4096", ProviderOpenAI, 2048, 4096, ImageDetailHigh, 1105},
+		{"anthropic 1000x1000", ProviderAnthropic, 1000, 1000, ImageDetailAuto, 1334},
+		{"google small", ProviderGoogle, 300, 300, ImageDetailAuto, 258},
+		{"google 1536x1536", ProviderGoogle, 1536, 1536, ImageDetailAuto, 1032},
+		{"unknown size", ProviderOpenAI, 0, 0, ImageDetailHigh, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EstimateImageTokens(tt.provider, tt.w, tt.h, tt.detail); got != tt.want {
+				t.Fatalf("expected %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestImageVendorUsesOpenRouterNamespace(t *testing.T) {
+	if got := imageVendor(ProviderOpenRouter, ModelClaudeHaiku); got != ProviderAnthropic {
+		t.Fatalf("expected anthropic, got %s", got)
+	}
+	if got := imageVendor(ProviderOpenRouter, ModelGemini25Flash); got != ProviderGoogle {
+		t.Fatalf("expected google, got %s", got)
+	}
+	if got := imageVendor(ProviderOllama, ModelGPT5); got != ProviderOllama {
+		t.Fatalf("expected ollama, got %s", got)
+	}
+}
+
+func TestProcessImageAppliesEXIFOrientation(t *testing.T) {
+	// Stored 32x16: red on the left, blue on the right.
+	img := image.NewNRGBA(image.Rect(0, 0, 32, 16))
+	for y := 0; y < 16; y++ {
+		for x := 0; x < 32; x++ {
+			c := color.NRGBA{255, 0, 0, 255}
+			if x >= 16 {
+				c = color.NRGBA{0, 0, 255, 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 100}); err != nil {
+		t.Fatal(err)
+	}
+
+	// APP1 Exif segment with IFD0 Orientation = 6 (rotate 90° clockwise).
+	exif := []byte("Exif\x00\x00MM\x00\x2a\x00\x00\x00\x08" +
+		"\x00\x01" + "\x01\x12\x00\x03\x00\x00\x00\x01\x00\x06\x00\x00" + "\x00\x00\x00\x00")
+	app1 := append([]byte{0xFF, 0xE1, 0, byte(len(exif) + 2)}, exif...)
+	src := buf.Bytes()
+	data := append(append(append([]byte{}, src[:2]...), app1...), src[2:]...)
+
+	out, mimeType, w, h, err := processImage(data, "image/jpeg", ImageOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mimeType != "image/jpeg" || w != 16 || h != 32 {
+		t.Fatalf("expected upright 16x32 JPEG, got %s %dx%d", mimeType, w, h)
+	}
+	decoded, err := jpeg.Decode(bytes.NewReader(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Rotated clockwise, the left (red) half ends up on top.
+	if r, _, b, _ := decoded.At(8, 8).RGBA(); r < b {
+		t.Fatalf("expected red on top, got r=%d b=%d", r, b)
+	}
+	if r, _, b, _ := decoded.At(8, 24).RGBA(); b < r {
+		t.Fatalf("expected blue at the bottom, got r=%d b=%d", r, b)
+	}
+}
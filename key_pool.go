@@ -0,0 +1,327 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// API Key Pools
+// ═══════════════════════════════════════════════════════════════════════════
+
+// BalanceStrategy selects which pool member serves the next request.
+type BalanceStrategy string
+
+const (
+	BalanceRoundRobin  BalanceStrategy = "round_robin"  // rotate through healthy members
+	BalanceLeastLoaded BalanceStrategy = "least_loaded" // fewest in-flight requests
+)
+
+// DefaultQuarantine is how long a key is skipped after a 401/429 response.
+var DefaultQuarantine = time.Minute
+
+// keyPoolConfig collects pool options until NewClient builds the pool.
+type keyPoolConfig struct {
+	keys       []string
+	configs    []ProviderConfig
+	strategy   BalanceStrategy
+	quarantine time.Duration
+}
+
+func (c *ProviderConfig) keyPool() *keyPoolConfig {
+	if c.pool == nil {
+		c.pool = &keyPoolConfig{}
+	}
+	return c.pool
+}
+
+// WithAPIKeys spreads requests across several API keys for the same provider.
+// Every other setting is shared.
+func WithAPIKeys(keys ...string) ClientOption {
+	return func(c *ProviderConfig) {
+		pool := c.keyPool()
+		pool.keys = append(pool.keys, keys...)
+	}
+}
+
+// WithConfigs spreads requests across several complete configurations, e.g.
+// different regions, deployments or endpoints of the same provider.
+func WithConfigs(configs ...ProviderConfig) ClientOption {
+	return func(c *ProviderConfig) {
+		pool := c.keyPool()
+		pool.configs = append(pool.configs, configs...)
+	}
+}
+
+// WithBalancing sets how a key pool picks a member (default BalanceRoundRobin).
+func WithBalancing(strategy BalanceStrategy) ClientOption {
+	return func(c *ProviderConfig) {
+		c.keyPool().strategy = strategy
+	}
+}
+
+// WithQuarantine sets how long a pool member is skipped after a 401/429
+// response (default DefaultQuarantine).
+func WithQuarantine(d time.Duration) ClientOption {
+	return func(c *ProviderConfig) {
+		c.keyPool().quarantine = d
+	}
+}
+
+// poolMember is one key (or config) in a PoolProvider.
+type poolMember struct {
+	label    string
+	provider Provider
+
+	inFlight         int
+	requests         int
+	quarantinedUntil time.Time
+}
+
+// PoolProvider balances requests across providers built from different keys
+// or configs. A member that returns an auth or rate-limit error is
+// quarantined and the request moves to the next healthy member.
+type PoolProvider struct {
+	name       string
+	strategy   BalanceStrategy
+	quarantine time.Duration
+
+	mu      sync.Mutex
+	members []*poolMember
+	next    int
+}
+
+// newPoolProvider builds one provider per key/config with factory.
+func newPoolProvider(name ProviderType, factory ProviderFactory, base ProviderConfig) *PoolProvider {
+	spec := base.pool
+	base.pool = nil
+
+	var configs []ProviderConfig
+	for _, key := range spec.keys {
+		c := base
+		c.APIKey = key
+		configs = append(configs, c)
+	}
+	configs = append(configs, spec.configs...)
+
+	p := &PoolProvider{name: string(name), strategy: spec.strategy, quarantine: spec.quarantine}
+	if p.strategy == "" {
+		p.strategy = BalanceRoundRobin
+	}
+	if p.quarantine <= 0 {
+		p.quarantine = DefaultQuarantine
+	}
+	seen := map[string]int{}
+	for _, c := range configs {
+		c.pool = nil
+		label := poolLabel(name, c)
+		if seen[label]++; seen[label] > 1 {
+			label = fmt.Sprintf("%s#%d", label, seen[label])
+		}
+		p.members = append(p.members, &poolMember{label: label, provider: factory(c)})
+	}
+	return p
+}
+
+// poolLabel identifies a member in Stats without exposing its key.
+func poolLabel(name ProviderType, c ProviderConfig) string {
+	label := string(name)
+	if c.APIKey != "" {
+		key := c.APIKey
+		if len(key) > 4 {
+			key = key[len(key)-4:]
+		}
+		label += ":…" + key
+	}
+	if c.Region != "" {
+		label += "@" + c.Region
+	} else if c.BaseURL != "" {
+		label += "@" + strings.TrimPrefix(strings.TrimPrefix(c.BaseURL, "https://"), "http://")
+	}
+	return label
+}
+
+// Name returns the pooled provider's name.
+func (p *PoolProvider) Name() string {
+	return p.name
+}
+
+// Capabilities reports the capabilities of the pooled provider.
+func (p *PoolProvider) Capabilities() ProviderCapabilities {
+	if len(p.members) == 0 {
+		return ProviderCapabilities{}
+	}
+	return p.members[0].provider.Capabilities()
+}
+
+// acquire picks a member, skipping quarantined ones and those in tried.
+// When every member is quarantined it picks the one released soonest.
+func (p *PoolProvider) acquire(tried map[*poolMember]bool) *poolMember {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	var best, soonest *poolMember
+	for i := range p.members {
+		m := p.members[(p.next+i)%len(p.members)]
+		if tried[m] {
+			continue
+		}
+		if now.Before(m.quarantinedUntil) {
+			if soonest == nil || m.quarantinedUntil.Before(soonest.quarantinedUntil) {
+				soonest = m
+			}
+			continue
+		}
+		if best == nil {
+			best = m
+			if p.strategy != BalanceLeastLoaded {
+				break
+			}
+		} else if m.inFlight < best.inFlight || m.inFlight == best.inFlight && m.requests < best.requests {
+			best = m
+		}
+	}
+	if best == nil {
+		best = soonest
+	}
+	if best == nil {
+		return nil
+	}
+	p.next = (p.next + 1) % len(p.members)
+	best.inFlight++
+	best.requests++
+	return best
+}
+
+// release records the outcome of a request on m.
+func (p *PoolProvider) release(m *poolMember, tokens int, err error) {
+	quarantined := err != nil && isKeyError(err)
+	p.mu.Lock()
+	m.inFlight--
+	if quarantined {
+		m.quarantinedUntil = time.Now().Add(p.quarantine)
+	}
+	p.mu.Unlock()
+	trackKey(m.label, tokens, err != nil, quarantined)
+}
+
+// poolDo runs fn on pool members until one succeeds or fails with an error
+// that is not key-specific. A non-nil stop can forbid moving to another member.
+func poolDo[T any](ctx context.Context, p *PoolProvider, stop func() bool, fn func(*poolMember) (T, int, error)) (T, error) {
+	var zero T
+	tried := map[*poolMember]bool{}
+	var lastErr error
+	for {
+		m := p.acquire(tried)
+		if m == nil {
+			if lastErr == nil {
+				lastErr = &ProviderError{Provider: p.name, Message: "key pool is empty"}
+			}
+			return zero, lastErr
+		}
+		tried[m] = true
+
+		result, tokens, err := fn(m)
+		p.release(m, tokens, err)
+		if err == nil {
+			return result, nil
+		}
+		if !isKeyError(err) || ctx.Err() != nil || stop != nil && stop() {
+			return zero, err
+		}
+		lastErr = err
+	}
+}
+
+// Send sends through the next healthy member, applying ServerLimits per member.
+func (p *PoolProvider) Send(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+	return poolDo(ctx, p, nil, func(m *poolMember) (*ProviderResponse, int, error) {
+		resp, err := serverLimited(ctx, m.label, req.Model, func() (*ProviderResponse, error) {
+			return m.provider.Send(ctx, req)
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+		return resp, resp.TotalTokens, nil
+	})
+}
+
+// SendStream streams through the next healthy member. A request is only
+// moved to another member if no chunk has been delivered yet.
+func (p *PoolProvider) SendStream(ctx context.Context, req *ProviderRequest, callback StreamCallback) (*ProviderResponse, error) {
+	started := false
+	return poolDo(ctx, p, func() bool { return started }, func(m *poolMember) (*ProviderResponse, int, error) {
+		resp, err := serverLimited(ctx, m.label, req.Model, func() (*ProviderResponse, error) {
+			return m.provider.SendStream(ctx, req, func(chunk string) {
+				started = true
+				callback(chunk)
+			})
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+		return resp, resp.TotalTokens, nil
+	})
+}
+
+// Embed embeds through the next healthy member.
+func (p *PoolProvider) Embed(ctx context.Context, req *EmbeddingRequest) (*EmbeddingResponse, error) {
+	return poolDo(ctx, p, nil, func(m *poolMember) (*EmbeddingResponse, int, error) {
+		embedder, ok := m.provider.(Embedder)
+		if !ok {
+			return nil, 0, fmt.Errorf("provider %s does not support embeddings", m.provider.Name())
+		}
+		resp, err := embedder.Embed(ctx, req)
+		if err != nil {
+			return nil, 0, err
+		}
+		return resp, resp.TotalTokens, nil
+	})
+}
+
+// TextToSpeech synthesizes speech through the next healthy member.
+func (p *PoolProvider) TextToSpeech(ctx context.Context, req *TTSRequest) (*TTSResponse, error) {
+	return poolDo(ctx, p, nil, func(m *poolMember) (*TTSResponse, int, error) {
+		audio, ok := m.provider.(AudioProvider)
+		if !ok {
+			return nil, 0, fmt.Errorf("provider %s does not support text-to-speech", m.provider.Name())
+		}
+		resp, err := audio.TextToSpeech(ctx, req)
+		return resp, 0, err
+	})
+}
+
+// SpeechToText transcribes through the next healthy member.
+func (p *PoolProvider) SpeechToText(ctx context.Context, req *STTRequest) (*STTResponse, error) {
+	return poolDo(ctx, p, nil, func(m *poolMember) (*STTResponse, int, error) {
+		audio, ok := m.provider.(AudioProvider)
+		if !ok {
+			return nil, 0, fmt.Errorf("provider %s does not support speech-to-text", m.provider.Name())
+		}
+		resp, err := audio.SpeechToText(ctx, req)
+		return resp, 0, err
+	})
+}
+
+// ListModels lists models through the next healthy member.
+func (p *PoolProvider) ListModels(ctx context.Context) ([]ModelSpec, error) {
+	return poolDo(ctx, p, nil, func(m *poolMember) ([]ModelSpec, int, error) {
+		lister, ok := m.provider.(ModelLister)
+		if !ok {
+			return nil, 0, fmt.Errorf("provider %s does not support listing models", m.provider.Name())
+		}
+		specs, err := lister.ListModels(ctx)
+		return specs, 0, err
+	})
+}
+
+// isKeyError reports whether err is an authentication, quota or rate-limit
+// failure tied to the credential rather than the request.
+func isKeyError(err error) bool {
+	return errors.Is(err, ErrAuth) || errors.Is(err, ErrRateLimited) || errors.Is(err, ErrQuotaExhausted)
+}
@@ -0,0 +1,126 @@
+package ai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestKeyPool_RoundRobinAndQuarantine(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+	ResetStats()
+	defer ResetStats()
+
+	var mu sync.Mutex
+	calls := map[string]int{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		mu.Lock()
+		calls[key]++
+		mu.Unlock()
+		if key == "key-bbbb" {
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error":{"message":"slow down","code":"rate_limit_exceeded"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"content":"ok"},"finish_reason":"stop"}],"usage":{"total_tokens":3}}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(ProviderOpenAI, WithBaseURL(srv.URL),
+		WithAPIKeys("key-aaaa", "key-bbbb", "key-cccc"), WithQuarantine(time.Hour))
+	if _, ok := c.provider.(*PoolProvider); !ok {
+		t.Fatalf("expected *PoolProvider, got %T", c.provider)
+	}
+
+	for i := 0; i < 6; i++ {
+		resp, err := c.provider.Send(context.Background(), &ProviderRequest{
+			Model:    string(ModelGPT4o),
+			Messages: []Message{{Role: "user", Content: "hi"}},
+		})
+		if err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+		if resp.Content != "ok" {
+			t.Fatalf("unexpected content %q", resp.Content)
+		}
+	}
+
+	// The rate-limited key is hit once, then benched; the others share the load.
+	if calls["key-bbbb"] != 1 {
+		t.Fatalf("expected quarantined key to be used once, got %d", calls["key-bbbb"])
+	}
+	if calls["key-aaaa"] != 3 || calls["key-cccc"] != 3 {
+		t.Fatalf("expected even spread over healthy keys, got %v", calls)
+	}
+
+	keys := GetStats().Keys
+	if k := keys["openai:…bbbb@"+strings.TrimPrefix(srv.URL, "http://")]; k.Quarantines != 1 || k.Errors != 1 {
+		t.Fatalf("unexpected stats for rate-limited key: %+v (all: %v)", k, keys)
+	}
+	if k := keys["openai:…aaaa@"+strings.TrimPrefix(srv.URL, "http://")]; k.Requests != 3 || k.Tokens != 9 {
+		t.Fatalf("unexpected stats for healthy key: %+v", k)
+	}
+}
+
+func TestKeyPool_LeastLoadedAndConfigs(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+	defer ResetStats()
+
+	c := NewClient(ProviderBedrock, WithBalancing(BalanceLeastLoaded), WithConfigs(
+		ProviderConfig{Region: "us-east-1", APIKey: "k1"},
+		ProviderConfig{Region: "us-west-2", APIKey: "k2"},
+	))
+	p := c.provider.(*PoolProvider)
+	if len(p.members) != 2 || p.members[0].label != "bedrock:…k1@us-east-1" {
+		t.Fatalf("unexpected members %+v", p.members)
+	}
+
+	first := p.acquire(nil)
+	second := p.acquire(nil)
+	if first == second {
+		t.Fatal("least-loaded should pick the idle member while the other is busy")
+	}
+	p.release(first, 0, nil)
+	if got := p.acquire(nil); got != first {
+		t.Fatalf("expected the released member, got %s", got.label)
+	}
+}
+
+func TestKeyPool_ServerLimitsPerMember(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "Bearer key-aaaa" {
+			w.Header().Set("x-ratelimit-limit-requests", "100")
+			w.Header().Set("x-ratelimit-remaining-requests", "0")
+			w.Header().Set("x-ratelimit-reset-requests", "1m")
+		}
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"content":"ok"},"finish_reason":"stop"}]}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(ProviderOpenAI, WithBaseURL(srv.URL), WithAPIKeys("key-aaaa", "key-bbbb"))
+	if meta := c.New(ModelGPT4o).User("hi").SendWithMeta(); meta.Error != nil {
+		t.Fatal(meta.Error)
+	}
+
+	// Only the exhausted key waits; the pool's other key and the provider as a whole don't.
+	label := c.provider.(*PoolProvider).members[0].label
+	if d := ServerLimits.Delay(label, string(ModelGPT4o)); d < 59*time.Second {
+		t.Fatalf("expected the first key to wait ~1m, got %v", d)
+	}
+	if d := ServerLimits.Delay(c.provider.(*PoolProvider).members[1].label, string(ModelGPT4o)); d != 0 {
+		t.Fatalf("expected the second key to be free, got %v", d)
+	}
+	if d := ServerLimits.Delay("openai", string(ModelGPT4o)); d != 0 {
+		t.Fatalf("expected no provider-wide limit, got %v", d)
+	}
+}
@@ -0,0 +1,448 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Structured Logging
+// ═══════════════════════════════════════════════════════════════════════════
+
+// Logger receives structured request, response, retry and tool events. It is
+// the default for clients without WithLogger. When nil, Debug (and Pretty, for
+// final answers) prints the same events through a PrettyHandler on stdout.
+var Logger *slog.Logger
+
+// LogRedaction masks secrets in logged prompts, completions, errors and tool
+// data and caps their length. It is the default for clients without
+// WithLogRedaction.
+var LogRedaction = &Redaction{MaxLen: 2000}
+
+// Event names, used as the slog record message.
+const (
+	EventRequest        = "llm.request"         // Debug: model, provider, messages
+	EventResponse       = "llm.response"        // Info: model, provider, latency, tokens, content
+	EventError          = "llm.error"           // Error: model, provider, latency, error
+	EventCacheHit       = "llm.cache_hit"       // Debug: model
+	EventRetry          = "llm.retry"           // Warn: attempt, max_retries, delay, error
+	EventRetrySkipped   = "llm.retry_skipped"   // Debug: error (not retryable)
+	EventRetryExhausted = "llm.retry_exhausted" // Error: max_retries, elapsed, error
+	EventToolCall       = "llm.tool_call"       // Debug: tool, call_id, arguments
+	EventToolResult     = "llm.tool_result"     // Debug: tool, call_id, result, error
+	EventImages         = "llm.images"          // Debug: model, images (estimates), image_tokens
+	EventAnswer         = "llm.answer"          // Info: model, content, prompt (conversations); only with Pretty
+)
+
+// WithLogger sends this client's events to l instead of Logger.
+//
+//	client := ai.NewClient(ai.ProviderOpenAI,
+//		ai.WithLogger(slog.New(slog.NewJSONHandler(os.Stderr, nil))))
+func WithLogger(l *slog.Logger) ClientOption {
+	return func(c *ProviderConfig) { c.clientSettings().logger = l }
+}
+
+// WithLogRedaction redacts this client's logged content with r instead of LogRedaction.
+func WithLogRedaction(r *Redaction) ClientOption {
+	return func(c *ProviderConfig) { c.clientSettings().redaction = r }
+}
+
+// Logger sends this request's events to l instead of the client's logger.
+func (b *Builder) Logger(l *slog.Logger) *Builder {
+	b.settings.logger = l
+	return b
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Redaction
+// ═══════════════════════════════════════════════════════════════════════════
+
+// Redaction masks secrets in logged text. API keys, bearer tokens, key=value
+// credentials and email addresses are always masked; Patterns adds more.
+// Text longer than MaxLen bytes is truncated (0 = no limit).
+type Redaction struct {
+	Patterns []*regexp.Regexp
+	MaxLen   int
+}
+
+const redacted = "[REDACTED]"
+
+var (
+	secretPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`\b(?:sk|pk|rk)-[A-Za-z0-9_\-]{16,}`), // OpenAI, Anthropic, OpenRouter...
+		regexp.MustCompile(`\bxai-[A-Za-z0-9]{20,}`),             // xAI
+		regexp.MustCompile(`\bAIza[0-9A-Za-z_\-]{35}`),           // Google
+		regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`),      // AWS access key IDs
+		regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}`),       // GitHub
+		regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9._~+/\-]+=*`),
+	}
+	secretAssignment = regexp.MustCompile(`(?i)\b(api[_-]?key|secret|password|access[_-]?token)(["']?\s*[:=]\s*["']?)[^\s"',&]+`)
+	emailPattern     = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(?:\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,}`)
+)
+
+// Redact masks secrets in s and truncates it to MaxLen. A nil Redaction
+// still masks the built-in secrets.
+func (r *Redaction) Redact(s string) string {
+	if r != nil {
+		for _, p := range r.Patterns {
+			s = p.ReplaceAllLiteralString(s, redacted)
+		}
+	}
+	for _, p := range secretPatterns {
+		s = p.ReplaceAllLiteralString(s, redacted)
+	}
+	s = secretAssignment.ReplaceAllString(s, "${1}${2}"+redacted)
+	s = emailPattern.ReplaceAllLiteralString(s, "[EMAIL]")
+	if r != nil {
+		s = capLength(s, r.MaxLen)
+	}
+	return s
+}
+
+// capLength truncates s to at most n bytes on a rune boundary.
+func capLength(s string, n int) string {
+	if n <= 0 || len(s) <= n {
+		return s
+	}
+	cut := runeCut(s, n)
+	return fmt.Sprintf("%s… [%d bytes truncated]", s[:cut], len(s)-cut)
+}
+
+// runeCut returns the largest index <= n that doesn't split a UTF-8 rune.
+func runeCut(s string, n int) int {
+	if n >= len(s) {
+		return len(s)
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return n
+}
+
+// loggedMessage is a message as it appears in logs: role and redacted text.
+type loggedMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+func loggedMessages(msgs []Message, r *Redaction) []loggedMessage {
+	out := make([]loggedMessage, len(msgs))
+	for i, m := range msgs {
+		out[i] = loggedMessage{Role: m.Role, Content: r.Redact(messageText(m))}
+	}
+	return out
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Events (called internally)
+// ═══════════════════════════════════════════════════════════════════════════
+
+// eventLogger returns the request's logger if it logs at level: the
+// configured one, or a pretty stdout logger when only Debug is on.
+func eventLogger(ctx context.Context, level slog.Level) (*slog.Logger, *Redaction) {
+	set := settingsFrom(ctx)
+	l := set.logger
+	if l == nil {
+		if !set.debug {
+			return nil, nil
+		}
+		l = slog.New(NewPrettyHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	}
+	if !l.Enabled(ctx, level) {
+		return nil, nil
+	}
+	return l, set.redaction
+}
+
+func logRequest(ctx context.Context, provider string, model Model, msgs []Message) {
+	l, r := eventLogger(ctx, slog.LevelDebug)
+	if l == nil {
+		return
+	}
+	l.LogAttrs(ctx, slog.LevelDebug, EventRequest,
+		slog.String("model", string(model)),
+		slog.String("provider", provider),
+		slog.Any("messages", loggedMessages(msgs, r)),
+	)
+}
+
+// logResult logs the outcome of one provider call.
+func logResult(ctx context.Context, provider, model string, resp *ProviderResponse, err error, latency time.Duration) {
+	level := slog.LevelInfo
+	if err != nil {
+		level = slog.LevelError
+	}
+	l, r := eventLogger(ctx, level)
+	if l == nil {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("model", model),
+		slog.String("provider", provider),
+		slog.Duration("latency", latency),
+	}
+	if err != nil {
+		l.LogAttrs(ctx, level, EventError, append(attrs, slog.String("error", r.Redact(err.Error())))...)
+		return
+	}
+	attrs = append(attrs,
+		slog.Int("input_tokens", resp.PromptTokens),
+		slog.Int("output_tokens", resp.CompletionTokens),
+	)
+	if resp.FinishReason != "" {
+		attrs = append(attrs, slog.String("finish_reason", resp.FinishReason))
+	}
+	if len(resp.ToolCalls) > 0 {
+		attrs = append(attrs, slog.Int("tool_calls", len(resp.ToolCalls)))
+	}
+	attrs = append(attrs, slog.String("content", r.Redact(resp.Content)))
+	l.LogAttrs(ctx, level, EventResponse, attrs...)
+}
+
+func logCacheHit(ctx context.Context, model Model) {
+	if l, _ := eventLogger(ctx, slog.LevelDebug); l != nil {
+		l.LogAttrs(ctx, slog.LevelDebug, EventCacheHit, slog.String("model", string(model)))
+	}
+}
+
+func logRetry(ctx context.Context, attempt, maxRetries int, delay time.Duration, err error) {
+	if l, r := eventLogger(ctx, slog.LevelWarn); l != nil {
+		l.LogAttrs(ctx, slog.LevelWarn, EventRetry,
+			slog.Int("attempt", attempt),
+			slog.Int("max_retries", maxRetries),
+			slog.Duration("delay", delay),
+			slog.String("error", r.Redact(err.Error())),
+		)
+	}
+}
+
+func logRetrySkipped(ctx context.Context, err error) {
+	if l, r := eventLogger(ctx, slog.LevelDebug); l != nil {
+		l.LogAttrs(ctx, slog.LevelDebug, EventRetrySkipped, slog.String("error", r.Redact(err.Error())))
+	}
+}
+
+func logRetryExhausted(ctx context.Context, maxRetries int, elapsed time.Duration, err error) {
+	if l, r := eventLogger(ctx, slog.LevelError); l != nil {
+		l.LogAttrs(ctx, slog.LevelError, EventRetryExhausted,
+			slog.Int("max_retries", maxRetries),
+			slog.Duration("elapsed", elapsed),
+			slog.String("error", r.Redact(err.Error())),
+		)
+	}
+}
+
+// logImages logs the estimated token cost of each attached image.
+func logImages(ctx context.Context, model Model, estimate func(Model) []ImageEstimate) {
+	l, _ := eventLogger(ctx, slog.LevelDebug)
+	if l == nil {
+		return
+	}
+	estimates := estimate(model)
+	total := 0
+	for _, est := range estimates {
+		total += est.Tokens
+	}
+	l.LogAttrs(ctx, slog.LevelDebug, EventImages,
+		slog.String("model", string(model)),
+		slog.Any("images", estimates),
+		slog.Int("image_tokens", total),
+	)
+}
+
+// logAnswer reports a final answer when Pretty is on: to the configured
+// logger (redacted), or through a PrettyHandler on stdout. prompt is set for
+// conversation turns.
+func logAnswer(ctx context.Context, model Model, prompt, content string) {
+	set := settingsFrom(ctx)
+	if !set.pretty {
+		return
+	}
+	l, r := set.logger, set.redaction
+	if l == nil {
+		l, r = slog.New(NewPrettyHandler(os.Stdout, nil)), nil
+	}
+	redact := func(s string) string {
+		if r == nil {
+			return s
+		}
+		return r.Redact(s)
+	}
+	attrs := []slog.Attr{slog.String("model", string(model)), slog.String("content", redact(content))}
+	if prompt != "" {
+		attrs = append(attrs, slog.String("prompt", redact(prompt)))
+	}
+	l.LogAttrs(ctx, slog.LevelInfo, EventAnswer, attrs...)
+}
+
+func logToolCall(ctx context.Context, tc ToolCall) {
+	if l, r := eventLogger(ctx, slog.LevelDebug); l != nil {
+		l.LogAttrs(ctx, slog.LevelDebug, EventToolCall,
+			slog.String("tool", tc.Function.Name),
+			slog.String("call_id", tc.ID),
+			slog.String("arguments", r.Redact(tc.Function.Arguments)),
+		)
+	}
+}
+
+func logToolResult(ctx context.Context, tc ToolCall, result string, err error) {
+	l, r := eventLogger(ctx, slog.LevelDebug)
+	if l == nil {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("tool", tc.Function.Name),
+		slog.String("call_id", tc.ID),
+		slog.String("result", r.Redact(result)),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", r.Redact(err.Error())))
+	}
+	l.LogAttrs(ctx, slog.LevelDebug, EventToolResult, attrs...)
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Pretty Handler
+// ═══════════════════════════════════════════════════════════════════════════
+
+// PrettyHandler is a slog.Handler that prints the library's events as the
+// colored boxes and lines of Debug output. Other records print as one line.
+// Use it like any handler, or swap it for slog.NewJSONHandler in production.
+type PrettyHandler struct {
+	mu     *sync.Mutex
+	w      io.Writer
+	level  slog.Leveler
+	attrs  []slog.Attr
+	prefix string
+}
+
+// NewPrettyHandler creates a PrettyHandler writing to w. Only opts.Level is
+// used; the default level is Info.
+func NewPrettyHandler(w io.Writer, opts *slog.HandlerOptions) *PrettyHandler {
+	h := &PrettyHandler{mu: &sync.Mutex{}, w: w, level: slog.LevelInfo}
+	if opts != nil && opts.Level != nil {
+		h.level = opts.Level
+	}
+	return h
+}
+
+// Enabled reports whether records at level are printed.
+func (h *PrettyHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+// WithAttrs returns a handler that adds attrs to every record.
+func (h *PrettyHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.attrs = append([]slog.Attr(nil), h.attrs...)
+	for _, a := range attrs {
+		h2.attrs = append(h2.attrs, slog.Attr{Key: h.prefix + a.Key, Value: a.Value})
+	}
+	return &h2
+}
+
+// WithGroup returns a handler that prefixes later attribute keys with name.
+func (h *PrettyHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.prefix = h.prefix + name + "."
+	return &h2
+}
+
+// Handle prints r.
+func (h *PrettyHandler) Handle(_ context.Context, r slog.Record) error {
+	attrs := append([]slog.Attr(nil), h.attrs...)
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, slog.Attr{Key: h.prefix + a.Key, Value: a.Value.Resolve()})
+		return true
+	})
+	get := func(key string) slog.Value {
+		for _, a := range attrs {
+			if a.Key == key {
+				return a.Value
+			}
+		}
+		return slog.Value{}
+	}
+	str := func(key string) string { return get(key).String() }
+	num := func(key string) int64 {
+		if v := get(key); v.Kind() == slog.KindInt64 {
+			return v.Int64()
+		}
+		return 0
+	}
+	dur := func(key string) time.Duration {
+		if v := get(key); v.Kind() == slog.KindDuration {
+			return v.Duration().Round(time.Millisecond)
+		}
+		return 0
+	}
+
+	var b strings.Builder
+	switch r.Message {
+	case EventRequest:
+		msgs, _ := get("messages").Any().([]loggedMessage)
+		writeDebugRequest(&b, str("model"), msgs)
+	case EventResponse:
+		writeDebugResponse(&b, str("model"), str("content"), int(num("input_tokens")), int(num("output_tokens")), dur("latency"))
+	case EventError:
+		fmt.Fprintf(&b, "%s Error from %s: %s\n", colorRed("✗"), str("model"), str("error"))
+	case EventCacheHit:
+		fmt.Fprintln(&b, colorYellow("⚡ Cache hit"))
+	case EventRetry:
+		fmt.Fprintf(&b, "%s Retry %d/%d after %v (error: %s)\n",
+			colorYellow("↻"), num("attempt"), num("max_retries"), dur("delay"), str("error"))
+	case EventRetrySkipped:
+		fmt.Fprintf(&b, "%s Not retrying: error not retryable\n", colorRed("✗"))
+	case EventRetryExhausted:
+		fmt.Fprintf(&b, "%s All %d retries exhausted after %v\n", colorRed("✗"), num("max_retries"), dur("elapsed"))
+	case EventToolCall:
+		fmt.Fprintf(&b, "%s Calling tool: %s(%s)\n", colorYellow("🔧"), str("tool"), str("arguments"))
+	case EventToolResult:
+		fmt.Fprintf(&b, "%s Tool result: %s\n", colorGreen("✓"), truncate(str("result"), 100))
+	case EventImages:
+		estimates, _ := get("images").Any().([]ImageEstimate)
+		writeDebugImages(&b, estimates)
+	case EventAnswer:
+		if prompt := str("prompt"); prompt != "" {
+			writePrettyConversation(&b, str("model"), prompt, str("content"))
+		} else {
+			writePrettyResponse(&b, str("model"), str("content"))
+		}
+	default:
+		writeLogLine(&b, r.Level, r.Message, attrs)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.w, b.String())
+	return err
+}
+
+// writeLogLine prints a record that isn't a library event.
+func writeLogLine(w io.Writer, level slog.Level, msg string, attrs []slog.Attr) {
+	tag := level.String()
+	switch {
+	case level >= slog.LevelError:
+		tag = colorRed(tag)
+	case level >= slog.LevelWarn:
+		tag = colorYellow(tag)
+	default:
+		tag = colorDim(tag)
+	}
+	fmt.Fprintf(w, "%s %s", tag, msg)
+	for _, a := range attrs {
+		fmt.Fprintf(w, " %s=%v", colorDim(a.Key), a.Value)
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,143 @@
+package ai
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Remote Media Fetching
+// ═══════════════════════════════════════════════════════════════════════════
+
+// DefaultMaxMediaBytes is the default size cap for fetched images and documents.
+const DefaultMaxMediaBytes = 20 << 20 // 20 MB
+
+// MediaFetcher downloads remote images and documents for providers that
+// cannot reference a URL natively and need the content inlined.
+type MediaFetcher struct {
+	HTTPClient *http.Client // HTTP client to use (nil = http.DefaultClient)
+	MaxBytes   int64        // Maximum download size (0 = DefaultMaxMediaBytes)
+}
+
+// DefaultMediaFetcher is used by providers when no client-specific fetcher is configured.
+var DefaultMediaFetcher = &MediaFetcher{}
+
+// WithMediaFetcher sets the fetcher used to inline remote media for this client.
+func WithMediaFetcher(f *MediaFetcher) ClientOption {
+	return func(c *ProviderConfig) {
+		c.MediaFetcher = f
+	}
+}
+
+// Fetch downloads url and returns its bytes and MIME type.
+// Only http and https URLs are supported.
+func (f *MediaFetcher) Fetch(ctx context.Context, url string) ([]byte, string, error) {
+	if !isHTTPURL(url) {
+		return nil, "", fmt.Errorf("unsupported media URL scheme: %s", url)
+	}
+
+	client := f.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	maxBytes := f.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxMediaBytes
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if debugOn(ctx) {
+		fmt.Printf("%s [media] GET %s\n", colorDim("→"), url)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("fetch %s: HTTP %d", url, resp.StatusCode)
+	}
+	if resp.ContentLength > maxBytes {
+		return nil, "", fmt.Errorf("fetch %s: %d bytes exceeds limit of %d", url, resp.ContentLength, maxBytes)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("fetch %s: %w", url, err)
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, "", fmt.Errorf("fetch %s: response exceeds limit of %d bytes", url, maxBytes)
+	}
+
+	mimeType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mimeType == "" || mimeType == "application/octet-stream" {
+		mimeType, _, _ = mime.ParseMediaType(http.DetectContentType(data))
+	}
+	return data, mimeType, nil
+}
+
+// FetchBase64 downloads url and returns its base64-encoded content and MIME type.
+func (f *MediaFetcher) FetchBase64(ctx context.Context, url string) (string, string, error) {
+	data, mimeType, err := f.Fetch(ctx, url)
+	if err != nil {
+		return "", "", err
+	}
+	return base64.StdEncoding.EncodeToString(data), mimeType, nil
+}
+
+// mediaFetcher returns the fetcher configured for a provider, or the package default.
+func mediaFetcher(config ProviderConfig) *MediaFetcher {
+	if config.MediaFetcher != nil {
+		return config.MediaFetcher
+	}
+	return DefaultMediaFetcher
+}
+
+// splitDataURI returns the MIME type and base64 payload of a data URI.
+// Percent-encoded payloads (no ";base64" flag) are decoded and re-encoded.
+func splitDataURI(uri string) (mimeType, data string, ok bool) {
+	mimeType, payload, isBase64, ok := parseDataURI(uri)
+	if !ok {
+		return "", "", false
+	}
+	if isBase64 {
+		return mimeType, payload, true
+	}
+	raw, err := url.PathUnescape(payload)
+	if err != nil {
+		return "", "", false
+	}
+	return mimeType, base64.StdEncoding.EncodeToString([]byte(raw)), true
+}
+
+// parseDataURI splits a data URI into its MIME type, raw payload and
+// whether the payload is base64 (otherwise it is percent-encoded).
+func parseDataURI(uri string) (mimeType, payload string, isBase64, ok bool) {
+	header, payload, found := strings.Cut(uri, ",")
+	if !found || !strings.HasPrefix(header, "data:") {
+		return "", "", false, false
+	}
+	params := strings.Split(strings.TrimPrefix(header, "data:"), ";")
+	for _, p := range params[1:] {
+		if strings.EqualFold(p, "base64") {
+			isBase64 = true
+		}
+	}
+	return params[0], payload, isBase64, true
+}
+
+func isHTTPURL(url string) bool {
+	return strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://")
+}
@@ -0,0 +1,165 @@
+package ai
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func imageMessage(url string) []Message {
+	return []Message{{Role: "user", Content: []ContentPart{
+		{Type: "text", Text: "describe"},
+		{Type: "image_url", ImageURL: &ImageURL{URL: url}},
+	}}}
+}
+
+func mediaServer(t *testing.T, body []byte, contentType string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestAnthropicBuildRequest_RemoteImageUsesURLSource(t *testing.T) {
+	p := NewAnthropicProvider(ProviderConfig{APIKey: "k"})
+	req, err := p.buildRequest(&ProviderRequest{
+		Model:    string(ModelClaudeSonnet),
+		Messages: imageMessage("https://example.com/cat.png"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	body, _ := json.Marshal(req)
+	if !strings.Contains(string(body), `"source":{"type":"url","url":"https://example.com/cat.png"}`) {
+		t.Fatalf("expected url image source, got %s", body)
+	}
+
+	if _, err := p.buildRequest(&ProviderRequest{Messages: imageMessage("ftp://example.com/cat.png")}); err == nil {
+		t.Fatal("expected error for unsupported image URL")
+	}
+}
+
+func TestGoogleBuildRequest_FetchesAndInlinesRemoteImage(t *testing.T) {
+	img := []byte("\x89PNG\r\n\x1a\nfake")
+	srv := mediaServer(t, img, "image/png")
+
+	p := NewGoogleProvider(ProviderConfig{APIKey: "k"})
+	req, err := p.buildRequest(context.Background(), &ProviderRequest{Messages: imageMessage(srv.URL + "/cat.png")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	inline := req.Contents[0].Parts[1].InlineData
+	if inline == nil {
+		t.Fatalf("expected inline data, got %+v", req.Contents[0].Parts[1])
+	}
+	if inline.MimeType != "image/png" || inline.Data != base64.StdEncoding.EncodeToString(img) {
+		t.Fatalf("unexpected inline data: %+v", inline)
+	}
+}
+
+func TestGoogleBuildRequest_NativeFileURIs(t *testing.T) {
+	p := NewGoogleProvider(ProviderConfig{APIKey: "k"})
+	req, err := p.buildRequest(context.Background(), &ProviderRequest{Messages: []Message{{Role: "user", Content: []ContentPart{
+		{Type: "document", Document: &DocumentRef{URL: "gs://bucket/report.pdf", MimeType: "application/pdf"}},
+		{Type: "image_url", ImageURL: &ImageURL{URL: "https://www.youtube.com/watch?v=abc"}},
+	}}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	parts := req.Contents[0].Parts
+	if parts[0].FileData == nil || parts[0].FileData.FileURI != "gs://bucket/report.pdf" {
+		t.Fatalf("expected gs:// fileData, got %+v", parts[0])
+	}
+	if parts[1].FileData == nil || parts[1].FileData.FileURI != "https://www.youtube.com/watch?v=abc" {
+		t.Fatalf("expected YouTube fileData, got %+v", parts[1])
+	}
+}
+
+func TestOllamaBuildRequest_RemoteMedia(t *testing.T) {
+	img := []byte("GIF89a-fake")
+	srv := mediaServer(t, img, "image/gif")
+
+	p := NewOllamaProvider(ProviderConfig{})
+	req, err := p.buildRequest(context.Background(), &ProviderRequest{Messages: imageMessage(srv.URL)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(req.Messages[0].Images) != 1 || req.Messages[0].Images[0] != base64.StdEncoding.EncodeToString(img) {
+		t.Fatalf("expected fetched image, got %+v", req.Messages[0].Images)
+	}
+
+	_, err = p.buildRequest(context.Background(), &ProviderRequest{Messages: []Message{{Role: "user", Content: []ContentPart{
+		{Type: "document", Document: &DocumentRef{URL: "https://example.com/a.pdf"}},
+	}}}})
+	if err == nil {
+		t.Fatal("expected explicit error for documents instead of dropping them")
+	}
+}
+
+func TestMediaFetcher_EnforcesSizeLimit(t *testing.T) {
+	srv := mediaServer(t, []byte(strings.Repeat("x", 100)), "text/plain")
+
+	f := &MediaFetcher{MaxBytes: 10}
+	if _, _, err := f.Fetch(context.Background(), srv.URL); err == nil {
+		t.Fatal("expected size limit error")
+	}
+
+	f = &MediaFetcher{MaxBytes: 1000}
+	data, mimeType, err := f.Fetch(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(data) != 100 || mimeType != "text/plain" {
+		t.Fatalf("unexpected result: %d bytes, %q", len(data), mimeType)
+	}
+}
+
+func TestMediaFetcher_UsesClientOption(t *testing.T) {
+	var hits int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		_, _ = io.WriteString(w, "img")
+	}))
+	defer srv.Close()
+
+	custom := &MediaFetcher{HTTPClient: srv.Client()}
+	c := NewClient(ProviderOllama, WithMediaFetcher(custom))
+	p := c.Provider().(*OllamaProvider)
+	if mediaFetcher(p.config) != custom {
+		t.Fatal("expected client-specific media fetcher")
+	}
+	if _, err := p.buildRequest(context.Background(), &ProviderRequest{Messages: imageMessage(srv.URL)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hits != 1 {
+		t.Fatalf("expected 1 fetch, got %d", hits)
+	}
+}
+
+func TestDataURIs_Base64FlagAndPercentEncoding(t *testing.T) {
+	mimeType, data, ok := splitDataURI("data:image/svg+xml,%3Csvg%3E+%3C%2Fsvg%3E")
+	if !ok || mimeType != "image/svg+xml" || data != base64.StdEncoding.EncodeToString([]byte("<svg>+</svg>")) {
+		t.Fatalf("unexpected percent-encoded result %q %q %v", mimeType, data, ok)
+	}
+	mimeType, data, ok = splitDataURI("data:image/png;name=a.png;base64,aGVsbG8=")
+	if !ok || mimeType != "image/png" || data != "aGVsbG8=" {
+		t.Fatalf("unexpected base64 result %q %q %v", mimeType, data, ok)
+	}
+	if _, raw, err := decodeDataURI("data:text/plain;charset=utf-8,caf%C3%A9"); err != nil || string(raw) != "café" {
+		t.Fatalf("unexpected decoded payload %q (%v)", raw, err)
+	}
+	if _, _, err := decodeDataURI("data:text/plain,%zz"); err == nil {
+		t.Fatal("expected error for invalid percent-encoding")
+	}
+}
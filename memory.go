@@ -0,0 +1,216 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Conversation Memory Strategies
+// ═══════════════════════════════════════════════════════════════════════════
+
+// MemoryState is the conversation memory a strategy works on.
+type MemoryState struct {
+	History []Message // messages sent verbatim
+	Summary string    // rolling summary of compacted turns (sent as a system note)
+
+	Model  Model   // conversation model (read-only)
+	Client *Client // conversation client, nil = default (read-only)
+}
+
+// MemoryStrategy compacts conversation history before each turn.
+// Implementations should drop whole turns (see SplitTurns) so an assistant
+// tool-call message is never separated from its tool results.
+type MemoryStrategy interface {
+	Compact(ctx context.Context, state MemoryState) (MemoryState, error)
+}
+
+// MemoryFunc adapts a function to the MemoryStrategy interface.
+type MemoryFunc func(ctx context.Context, state MemoryState) (MemoryState, error)
+
+// Compact calls f(ctx, state).
+func (f MemoryFunc) Compact(ctx context.Context, state MemoryState) (MemoryState, error) {
+	return f(ctx, state)
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Conversation Methods
+// ═══════════════════════════════════════════════════════════════════════════
+
+// Memory sets the strategy used to compact history before each turn.
+func (c *Conversation) Memory(strategy MemoryStrategy) *Conversation {
+	c.memory = strategy
+	return c
+}
+
+// Compact applies the memory strategy to the current history immediately.
+func (c *Conversation) Compact() error {
+	if c.memory == nil {
+		return nil
+	}
+	state, err := c.memory.Compact(c.builder.getContext(), MemoryState{
+		History: c.history,
+		Summary: c.summary,
+		Model:   c.builder.model,
+		Client:  c.builder.client,
+	})
+	if err != nil {
+		return err
+	}
+	if c.builder.currentSettings().debug && len(state.History) < len(c.history) {
+		fmt.Printf("%s [memory] compacted %d → %d messages\n", colorDim("↻"), len(c.history), len(state.History))
+	}
+	c.history = state.History
+	c.summary = state.Summary
+	return nil
+}
+
+// Summary returns the rolling summary of compacted turns, if any.
+func (c *Conversation) Summary() string {
+	return c.summary
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Built-in Strategies
+// ═══════════════════════════════════════════════════════════════════════════
+
+// LastTurns keeps only the n most recent turns. A turn is a user message plus
+// every assistant and tool message that follows it.
+func LastTurns(n int) MemoryStrategy {
+	return MemoryFunc(func(ctx context.Context, state MemoryState) (MemoryState, error) {
+		turns := SplitTurns(state.History)
+		if n > 0 && len(turns) > n {
+			state.History = joinTurns(turns[len(turns)-n:])
+		}
+		return state, nil
+	})
+}
+
+// TokenWindow drops the oldest turns until the history fits within budget
+// tokens (see CountMessageTokens). The most recent turn is always kept.
+func TokenWindow(budget int) MemoryStrategy {
+	return MemoryFunc(func(ctx context.Context, state MemoryState) (MemoryState, error) {
+		turns := SplitTurns(state.History)
+		start := 0
+		for start < len(turns)-1 && CountMessageTokens(state.Model, joinTurns(turns[start:])) > budget {
+			start++
+		}
+		state.History = joinTurns(turns[start:])
+		return state, nil
+	})
+}
+
+// SummaryMemory compresses older turns into a rolling summary using an LLM.
+// Once the history exceeds MaxTokens, everything except the last KeepTurns
+// turns is summarized and removed.
+type SummaryMemory struct {
+	Model     Model   // Model used for summaries, typically a cheap one ("" = conversation model)
+	Client    *Client // Client used for summaries (nil = conversation client)
+	MaxTokens int     // Summarize when history exceeds this many tokens (default 4000)
+	KeepTurns int     // Recent turns kept verbatim (default 4)
+	Prompt    string  // Summarization instruction (default: a concise factual summary)
+}
+
+const defaultSummaryPrompt = "You maintain a running summary of a conversation. " +
+	"Merge the previous summary with the new messages into a concise summary that keeps " +
+	"facts, decisions, names, numbers and open questions. Reply with the summary only."
+
+// SummarizeOlder returns a SummaryMemory strategy with the given summary model.
+func SummarizeOlder(model Model, maxTokens, keepTurns int) *SummaryMemory {
+	return &SummaryMemory{Model: model, MaxTokens: maxTokens, KeepTurns: keepTurns}
+}
+
+// Compact summarizes older turns when the history is over budget.
+func (s *SummaryMemory) Compact(ctx context.Context, state MemoryState) (MemoryState, error) {
+	maxTokens := s.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = 4000
+	}
+	keep := s.KeepTurns
+	if keep <= 0 {
+		keep = 4
+	}
+	if CountMessageTokens(state.Model, state.History) <= maxTokens {
+		return state, nil
+	}
+	turns := SplitTurns(state.History)
+	if len(turns) <= keep {
+		return state, nil
+	}
+
+	older := joinTurns(turns[:len(turns)-keep])
+	model := s.Model
+	if model == "" {
+		model = state.Model
+	}
+	client := s.Client
+	if client == nil {
+		client = state.Client
+	}
+	prompt := s.Prompt
+	if prompt == "" {
+		prompt = defaultSummaryPrompt
+	}
+
+	var b *Builder
+	if client != nil {
+		b = client.New(model)
+	} else {
+		b = New(model)
+	}
+	b.quiet = true
+
+	var input strings.Builder
+	if state.Summary != "" {
+		input.WriteString("Previous summary:\n" + state.Summary + "\n\n")
+	}
+	input.WriteString("New messages:\n" + transcript(older))
+
+	meta := b.WithContext(ctx).System(prompt).User(input.String()).SendWithMeta()
+	if meta.Error != nil {
+		return state, fmt.Errorf("summarize conversation: %w", meta.Error)
+	}
+
+	state.Summary = strings.TrimSpace(meta.Content)
+	state.History = joinTurns(turns[len(turns)-keep:])
+	return state, nil
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Helpers
+// ═══════════════════════════════════════════════════════════════════════════
+
+// SplitTurns groups history into turns, each starting at a user message.
+// Messages before the first user message form their own leading turn.
+func SplitTurns(history []Message) [][]Message {
+	var turns [][]Message
+	for _, m := range history {
+		if m.Role == "user" || len(turns) == 0 {
+			turns = append(turns, nil)
+		}
+		turns[len(turns)-1] = append(turns[len(turns)-1], m)
+	}
+	return turns
+}
+
+func joinTurns(turns [][]Message) []Message {
+	var out []Message
+	for _, t := range turns {
+		out = append(out, t...)
+	}
+	return out
+}
+
+// transcript renders messages as plain "role: text" lines for summarization.
+func transcript(msgs []Message) string {
+	var sb strings.Builder
+	for _, m := range msgs {
+		text := messageText(m)
+		for _, tc := range m.ToolCalls {
+			text += fmt.Sprintf("\n[called %s(%s)]", tc.Function.Name, tc.Function.Arguments)
+		}
+		fmt.Fprintf(&sb, "%s: %s\n", m.Role, text)
+	}
+	return sb.String()
+}
@@ -0,0 +1,125 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func memoryTestHistory() []Message {
+	tc := ToolCall{ID: "tc_1", Type: "function"}
+	tc.Function.Name = "lookup"
+	tc.Function.Arguments = `{}`
+
+	return []Message{
+		{Role: "user", Content: "first"},
+		{Role: "assistant", Content: "one"},
+		{Role: "user", Content: "second"},
+		{Role: "assistant", ToolCalls: []ToolCall{tc}},
+		{Role: "tool", Content: "result", ToolCallID: "tc_1"},
+		{Role: "assistant", Content: "two"},
+		{Role: "user", Content: "third"},
+	}
+}
+
+func TestSplitTurns(t *testing.T) {
+	turns := SplitTurns(memoryTestHistory())
+	if len(turns) != 3 {
+		t.Fatalf("expected 3 turns, got %d", len(turns))
+	}
+	if len(turns[1]) != 4 {
+		t.Fatalf("expected tool call and result in the same turn, got %d messages", len(turns[1]))
+	}
+}
+
+func TestLastTurnsKeepsToolPairs(t *testing.T) {
+	state, err := LastTurns(2).Compact(context.Background(), MemoryState{History: memoryTestHistory()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(state.History) != 5 {
+		t.Fatalf("expected 5 messages, got %d", len(state.History))
+	}
+	if state.History[0].Content != "second" || state.History[2].ToolCallID != "tc_1" {
+		t.Fatalf("unexpected history: %+v", state.History)
+	}
+}
+
+func TestTokenWindowDropsOldestTurns(t *testing.T) {
+	history := []Message{
+		{Role: "user", Content: strings.Repeat("a", 400)},
+		{Role: "assistant", Content: strings.Repeat("b", 400)},
+		{Role: "user", Content: "short"},
+	}
+	state, err := TokenWindow(50).Compact(context.Background(), MemoryState{History: history})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(state.History) != 1 || state.History[0].Content != "short" {
+		t.Fatalf("expected only the last turn, got %+v", state.History)
+	}
+
+	// The latest turn is kept even if it alone exceeds the budget.
+	state, _ = TokenWindow(1).Compact(context.Background(), MemoryState{History: history})
+	if len(state.History) != 1 {
+		t.Fatalf("expected latest turn to be kept, got %d messages", len(state.History))
+	}
+}
+
+func TestSummaryMemoryCompactsIntoSystemNote(t *testing.T) {
+	defer withTestGlobals(t)()
+
+	var summaryReq *ProviderRequest
+	sp := &stubProvider{
+		name: "stub",
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			if req.Model == string(ModelGPT5Nano) {
+				summaryReq = req
+				return &ProviderResponse{Content: "User asked about orders."}, nil
+			}
+			return &ProviderResponse{Content: "ok"}, nil
+		},
+	}
+	client := &Client{provider: sp, providerType: ProviderOpenAI}
+
+	chat := client.New(ModelGPT5).System("sys").Chat().
+		Memory(&SummaryMemory{Model: ModelGPT5Nano, MaxTokens: 10, KeepTurns: 1})
+	chat.history = memoryTestHistory()[:6]
+
+	if _, err := chat.Say(strings.Repeat("x", 100)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if summaryReq == nil {
+		t.Fatal("expected a summarization request on the summary model")
+	}
+	if !strings.Contains(summaryReq.Messages[1].Content.(string), "[called lookup({})]") {
+		t.Fatalf("expected tool calls in transcript, got %q", summaryReq.Messages[1].Content)
+	}
+	if chat.Summary() != "User asked about orders." {
+		t.Fatalf("unexpected summary: %q", chat.Summary())
+	}
+	if len(chat.History()) != 2 {
+		t.Fatalf("expected current turn only, got %d messages", len(chat.History()))
+	}
+
+	reqs := sp.Requests()
+	last := reqs[len(reqs)-1]
+	system := last.Messages[0].Content.(string)
+	if !strings.HasPrefix(system, "sys") || !strings.Contains(system, "User asked about orders.") {
+		t.Fatalf("expected summary in system prompt, got %q", system)
+	}
+
+	var buf bytes.Buffer
+	if err := chat.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	restored := New(ModelGPT5).Chat()
+	if err := restored.Load(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if restored.Summary() != chat.Summary() {
+		t.Fatalf("expected summary to survive Save/Load, got %q", restored.Summary())
+	}
+}
@@ -0,0 +1,393 @@
+package ai
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Prometheus Metrics
+// ═══════════════════════════════════════════════════════════════════════════
+
+// MetricsHandler serves the package's request metrics, GetStats totals and
+// per-key usage in the Prometheus text format:
+//
+//	http.Handle("/metrics", ai.MetricsHandler())
+//
+// Counters restart from zero after ResetStats.
+func MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = WriteMetrics(w)
+	})
+}
+
+// WriteMetrics writes the metrics served by MetricsHandler to w.
+func WriteMetrics(w io.Writer) error {
+	pw := &promWriter{w: w}
+	metrics.write(pw)
+
+	s := GetStats()
+	lookups := s.CacheHits + s.CacheMisses
+	ratio := 0.0
+	if lookups > 0 {
+		ratio = float64(s.CacheHits) / float64(lookups)
+	}
+	pw.header("llm_cache_hit_ratio", "gauge", "Share of cache lookups served from the cache.")
+	pw.sample("llm_cache_hit_ratio", nil, ratio)
+
+	if len(s.Keys) > 0 {
+		labels := make([]string, 0, len(s.Keys))
+		for label := range s.Keys {
+			labels = append(labels, label)
+		}
+		sort.Strings(labels)
+		pw.header("llm_key_requests_total", "counter", "Requests per pooled API key.")
+		for _, label := range labels {
+			pw.sample("llm_key_requests_total", promLabels{"key", label}, float64(s.Keys[label].Requests))
+		}
+		pw.header("llm_key_errors_total", "counter", "Failed requests per pooled API key.")
+		for _, label := range labels {
+			pw.sample("llm_key_errors_total", promLabels{"key", label}, float64(s.Keys[label].Errors))
+		}
+		pw.header("llm_key_quarantines_total", "counter", "Times each pooled API key was benched.")
+		for _, label := range labels {
+			pw.sample("llm_key_quarantines_total", promLabels{"key", label}, float64(s.Keys[label].Quarantines))
+		}
+	}
+	return pw.err
+}
+
+// latencyBuckets are the upper bounds (seconds) of the latency histograms.
+var latencyBuckets = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120}
+
+// requestMetrics holds the labeled series behind MetricsHandler.
+type requestMetrics struct {
+	mu sync.Mutex
+
+	requests  *counterVec
+	tokens    *counterVec
+	cost      *counterVec
+	retries   *counterVec
+	fallbacks *counterVec
+	cache     *counterVec
+	inFlight  *counterVec // a gauge: goes up and down
+	latency   *histogramVec
+	ttft      *histogramVec
+}
+
+var metrics = newRequestMetrics()
+
+func newRequestMetrics() *requestMetrics {
+	return &requestMetrics{
+		requests:  newCounterVec("llm_requests_total", "counter", "Provider calls by provider, model and status."),
+		tokens:    newCounterVec("llm_tokens_total", "counter", "Tokens used by provider, model and type (input or output)."),
+		cost:      newCounterVec("llm_cost_usd_total", "counter", "Estimated cost in USD by provider and model."),
+		retries:   newCounterVec("llm_retries_total", "counter", "Retried provider calls by provider and model."),
+		fallbacks: newCounterVec("llm_fallbacks_total", "counter", "Requests that moved on from a model to its next fallback."),
+		cache:     newCounterVec("llm_cache_lookups_total", "counter", "Response cache lookups by result (hit or miss)."),
+		inFlight:  newCounterVec("llm_requests_in_flight", "gauge", "Provider calls in progress by provider."),
+		latency:   newHistogramVec("llm_request_duration_seconds", "Provider call latency by provider and model.", latencyBuckets),
+		ttft:      newHistogramVec("llm_time_to_first_token_seconds", "Time until the first streamed chunk by provider and model.", latencyBuckets),
+	}
+}
+
+// reset clears the counters and histograms. The in-flight gauge is kept:
+// calls running across the reset still decrement it when they finish.
+func (m *requestMetrics) reset() {
+	fresh := newRequestMetrics()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests, m.tokens, m.cost = fresh.requests, fresh.tokens, fresh.cost
+	m.retries, m.fallbacks, m.cache = fresh.retries, fresh.fallbacks, fresh.cache
+	m.latency, m.ttft = fresh.latency, fresh.ttft
+}
+
+func (m *requestMetrics) write(pw *promWriter) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, c := range []*counterVec{m.requests, m.tokens, m.cost, m.retries, m.fallbacks, m.cache, m.inFlight} {
+		c.write(pw)
+	}
+	m.latency.write(pw)
+	m.ttft.write(pw)
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Recording (called internally)
+// ═══════════════════════════════════════════════════════════════════════════
+
+// startCall marks a provider call in flight and returns a func that records
+// its outcome.
+func (m *requestMetrics) startCall(provider, model string, attempt int) func(resp *ProviderResponse, err error) {
+	start := time.Now()
+	m.mu.Lock()
+	m.inFlight.add(promLabels{"provider", provider}, 1)
+	if attempt > 1 {
+		m.retries.add(promLabels{"provider", provider, "model", model}, 1)
+	}
+	m.mu.Unlock()
+
+	return func(resp *ProviderResponse, err error) {
+		status := "ok"
+		if err != nil {
+			status = errorType(err)
+		}
+		labels := promLabels{"provider", provider, "model", model}
+
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.inFlight.add(promLabels{"provider", provider}, -1)
+		m.requests.add(promLabels{"provider", provider, "model", model, "status", status}, 1)
+		m.latency.observe(labels, time.Since(start).Seconds())
+		if resp != nil {
+			m.tokens.add(promLabels{"provider", provider, "model", model, "type", "input"}, float64(resp.PromptTokens))
+			m.tokens.add(promLabels{"provider", provider, "model", model, "type", "output"}, float64(resp.CompletionTokens))
+			m.cost.add(labels, CalculateCost(Model(model), resp.PromptTokens, resp.CompletionTokens))
+		}
+	}
+}
+
+// timeFirstChunk records time-to-first-token when callback first fires.
+func (m *requestMetrics) timeFirstChunk(provider, model string, callback StreamCallback) StreamCallback {
+	start := time.Now()
+	var once sync.Once
+	return func(chunk string) {
+		once.Do(func() {
+			m.mu.Lock()
+			m.ttft.observe(promLabels{"provider", provider, "model", model}, time.Since(start).Seconds())
+			m.mu.Unlock()
+		})
+		callback(chunk)
+	}
+}
+
+func (m *requestMetrics) fallback(provider, model string) {
+	m.mu.Lock()
+	m.fallbacks.add(promLabels{"provider", provider, "model", model}, 1)
+	m.mu.Unlock()
+
+	statsLock.Lock()
+	stats.Fallbacks++
+	statsLock.Unlock()
+}
+
+func (m *requestMetrics) cacheLookup(hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	m.mu.Lock()
+	m.cache.add(promLabels{"result", result}, 1)
+	m.mu.Unlock()
+
+	statsLock.Lock()
+	if hit {
+		stats.CacheHits++
+	} else {
+		stats.CacheMisses++
+	}
+	statsLock.Unlock()
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Prometheus Text Format
+// ═══════════════════════════════════════════════════════════════════════════
+
+// promLabels is a list of alternating label names and values.
+type promLabels []string
+
+func (l promLabels) String() string {
+	if len(l) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteByte('{')
+	for i := 0; i+1 < len(l); i += 2 {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(l[i])
+		sb.WriteString(`="`)
+		sb.WriteString(promEscaper.Replace(l[i+1]))
+		sb.WriteByte('"')
+	}
+	sb.WriteByte('}')
+	return sb.String()
+}
+
+var promEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// promWriter writes the Prometheus text exposition format, keeping the first error.
+type promWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (p *promWriter) header(name, typ, help string) {
+	p.printf("# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+func (p *promWriter) sample(name string, labels promLabels, value float64) {
+	p.printf("%s%s %s\n", name, labels, formatPromValue(value))
+}
+
+func (p *promWriter) printf(format string, args ...any) {
+	if p.err == nil {
+		_, p.err = fmt.Fprintf(p.w, format, args...)
+	}
+}
+
+func formatPromValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// counterVec is a counter (or gauge) per label set.
+type counterVec struct {
+	name, typ, help string
+	series          map[string]*counterSeries
+}
+
+type counterSeries struct {
+	labels promLabels
+	value  float64
+}
+
+func newCounterVec(name, typ, help string) *counterVec {
+	return &counterVec{name: name, typ: typ, help: help, series: map[string]*counterSeries{}}
+}
+
+func (c *counterVec) add(labels promLabels, v float64) {
+	key := labels.String()
+	s, ok := c.series[key]
+	if !ok {
+		s = &counterSeries{labels: labels}
+		c.series[key] = s
+	}
+	s.value += v
+}
+
+func (c *counterVec) write(pw *promWriter) {
+	if len(c.series) == 0 {
+		return
+	}
+	pw.header(c.name, c.typ, c.help)
+	for _, key := range sortedKeys(c.series) {
+		s := c.series[key]
+		pw.sample(c.name, s.labels, s.value)
+	}
+}
+
+// histogramVec is a histogram per label set.
+type histogramVec struct {
+	name, help string
+	buckets    []float64
+	series     map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	labels promLabels
+	counts []uint64 // per bucket, not cumulative
+	count  uint64
+	sum    float64
+}
+
+func newHistogramVec(name, help string, buckets []float64) *histogramVec {
+	return &histogramVec{name: name, help: help, buckets: buckets, series: map[string]*histogramSeries{}}
+}
+
+func (h *histogramVec) observe(labels promLabels, v float64) {
+	key := labels.String()
+	s, ok := h.series[key]
+	if !ok {
+		s = &histogramSeries{labels: labels, counts: make([]uint64, len(h.buckets))}
+		h.series[key] = s
+	}
+	for i, upper := range h.buckets {
+		if v <= upper {
+			s.counts[i]++
+			break
+		}
+	}
+	s.count++
+	s.sum += v
+}
+
+func (h *histogramVec) write(pw *promWriter) {
+	if len(h.series) == 0 {
+		return
+	}
+	pw.header(h.name, "histogram", h.help)
+	for _, key := range sortedKeys(h.series) {
+		s := h.series[key]
+		var cumulative uint64
+		for i, upper := range h.buckets {
+			cumulative += s.counts[i]
+			pw.sample(h.name+"_bucket", append(append(promLabels{}, s.labels...), "le", formatPromValue(upper)), float64(cumulative))
+		}
+		pw.sample(h.name+"_bucket", append(append(promLabels{}, s.labels...), "le", "+Inf"), float64(s.count))
+		pw.sample(h.name+"_sum", s.labels, s.sum)
+		pw.sample(h.name+"_count", s.labels, float64(s.count))
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// MetricsCollector Export
+// ═══════════════════════════════════════════════════════════════════════════
+
+// ServeHTTP serves the collector's counters in the Prometheus text format,
+// so a collector registered on a client's hooks can be scraped on its own.
+func (m *MetricsCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = m.WritePrometheus(w)
+}
+
+// WritePrometheus writes the collector's counters to w.
+func (m *MetricsCollector) WritePrometheus(w io.Writer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pw := &promWriter{w: w}
+	pw.header("llm_collector_requests_total", "counter", "Successful responses seen by the collector.")
+	pw.sample("llm_collector_requests_total", nil, float64(m.RequestCount))
+	pw.header("llm_collector_errors_total", "counter", "Errors seen by the collector.")
+	pw.sample("llm_collector_errors_total", nil, float64(m.ErrorCount))
+	pw.header("llm_collector_tokens_total", "counter", "Tokens seen by the collector.")
+	pw.sample("llm_collector_tokens_total", nil, float64(m.TotalTokens))
+	pw.header("llm_collector_duration_seconds_total", "counter", "Total response time seen by the collector.")
+	pw.sample("llm_collector_duration_seconds_total", nil, m.TotalDuration.Seconds())
+	if len(m.ModelCounts) > 0 {
+		models := make([]string, 0, len(m.ModelCounts))
+		for model := range m.ModelCounts {
+			models = append(models, string(model))
+		}
+		sort.Strings(models)
+		pw.header("llm_collector_model_requests_total", "counter", "Successful responses per model.")
+		for _, model := range models {
+			pw.sample("llm_collector_model_requests_total", promLabels{"model", model}, float64(m.ModelCounts[Model(model)]))
+		}
+	}
+	return pw.err
+}
@@ -0,0 +1,108 @@
+package ai
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetricsHandler_PrometheusText(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+	ResetStats()
+	defer ResetStats()
+
+	p := &stubProvider{name: "openai", caps: ProviderCapabilities{Streaming: true}, sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+		if req.Model == string(ModelGPT4o) && !req.Stream {
+			return nil, &ProviderError{Provider: "openai", StatusCode: 429, Kind: ErrRateLimited}
+		}
+		return &ProviderResponse{Content: "ok", PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15}, nil
+	}}
+	c := NewClientWithProvider(p, WithCache(true))
+
+	for i := 0; i < 2; i++ {
+		meta := c.New(ModelGPT4o).Fallback(ModelGPT4oMini).RetryConfig(noSleepRetryConfig(1)).User("hi").SendWithMeta()
+		if meta.Error != nil {
+			t.Fatal(meta.Error)
+		}
+	}
+	if _, err := c.New(ModelGPT4o).User("stream").StreamResponse(func(string) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	MetricsHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+
+	gpt4o, mini := string(ModelGPT4o), string(ModelGPT4oMini)
+	for _, want := range []string{
+		"# TYPE llm_requests_total counter",
+		`llm_requests_total{provider="openai",model="` + gpt4o + `",status="429"} 4`,
+		`llm_requests_total{provider="openai",model="` + mini + `",status="ok"} 1`,
+		`llm_retries_total{provider="openai",model="` + gpt4o + `"} 2`,
+		// Both requests fall back; the second one finds the fallback's answer in the cache.
+		`llm_fallbacks_total{provider="openai",model="` + gpt4o + `"} 2`,
+		`llm_tokens_total{provider="openai",model="` + mini + `",type="input"} 10`,
+		`llm_cache_lookups_total{result="hit"} 1`,
+		`llm_cache_hit_ratio 0.25`,
+		`llm_requests_in_flight{provider="openai"} 0`,
+		"# TYPE llm_request_duration_seconds histogram",
+		`llm_request_duration_seconds_bucket{provider="openai",model="` + mini + `",le="+Inf"} 1`,
+		`llm_time_to_first_token_seconds_count{provider="openai",model="` + gpt4o + `"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("missing %q in\n%s", want, body)
+		}
+	}
+	if !strings.Contains(body, `llm_cost_usd_total{provider="openai",model="`+mini+`"} `) {
+		t.Error("missing cost counter")
+	}
+
+	s := GetStats()
+	if s.Fallbacks != 2 || s.CacheHits != 1 || s.CacheMisses != 3 {
+		t.Fatalf("unexpected stats %+v", s)
+	}
+}
+
+func TestMetricsCollector_ServeHTTP(t *testing.T) {
+	m := NewMetricsCollector()
+	m.Hook()(ModelGPT4o, "ok", 1500*time.Millisecond)
+	m.TokenHook()(ModelGPT4o, 3, 4)
+
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+	for _, want := range []string{
+		"llm_collector_requests_total 1",
+		"llm_collector_tokens_total 7",
+		"llm_collector_duration_seconds_total 1.5",
+		`llm_collector_model_requests_total{model="` + string(ModelGPT4o) + `"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("missing %q in\n%s", want, body)
+		}
+	}
+	if got := (promLabels{"k", "a\"b\\c\nd"}).String(); got != `{k="a\"b\\c\nd"}` {
+		t.Fatalf("bad escaping %s", got)
+	}
+}
+
+func TestMetrics_ResetKeepsInFlightGauge(t *testing.T) {
+	ResetStats()
+	defer ResetStats()
+
+	done := metrics.startCall("openai", string(ModelGPT4o), 1)
+	ResetStats()
+	done(&ProviderResponse{}, nil)
+
+	rec := httptest.NewRecorder()
+	MetricsHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if body := rec.Body.String(); !strings.Contains(body, `llm_requests_in_flight{provider="openai"} 0`) {
+		t.Fatalf("expected the gauge to return to 0 across a reset, got\n%s", body)
+	}
+}
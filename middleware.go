@@ -0,0 +1,116 @@
+package ai
+
+import (
+	"context"
+	"reflect"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Middleware
+// ═══════════════════════════════════════════════════════════════════════════
+
+// Handler executes provider requests. Every Provider is a Handler.
+type Handler interface {
+	Send(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error)
+	SendStream(ctx context.Context, req *ProviderRequest, callback StreamCallback) (*ProviderResponse, error)
+}
+
+// Middleware wraps a Handler. It can change the request, return a response
+// without calling next, or wrap the call (timing, tracing, policy checks).
+type Middleware func(next Handler) Handler
+
+// SendFunc handles a non-streaming request.
+type SendFunc func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error)
+
+// StreamFunc handles a streaming request.
+type StreamFunc func(ctx context.Context, req *ProviderRequest, callback StreamCallback) (*ProviderResponse, error)
+
+// Intercept builds a Handler from functions. A nil function passes that kind
+// of request through to next unchanged.
+//
+//	logging := func(next ai.Handler) ai.Handler {
+//		return ai.Intercept(next, func(ctx context.Context, req *ai.ProviderRequest) (*ai.ProviderResponse, error) {
+//			log.Println("→", req.Model)
+//			return next.Send(ctx, req)
+//		}, nil)
+//	}
+func Intercept(next Handler, send SendFunc, stream StreamFunc) Handler {
+	if send == nil {
+		send = next.Send
+	}
+	if stream == nil {
+		stream = next.SendStream
+	}
+	return &funcHandler{send: send, stream: stream}
+}
+
+type funcHandler struct {
+	send   SendFunc
+	stream StreamFunc
+}
+
+func (h *funcHandler) Send(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+	return h.send(ctx, req)
+}
+
+func (h *funcHandler) SendStream(ctx context.Context, req *ProviderRequest, callback StreamCallback) (*ProviderResponse, error) {
+	return h.stream(ctx, req, callback)
+}
+
+// WithMiddleware wraps every Send and SendStream call of the client. The
+// first middleware is the outermost; the last one calls the provider.
+// The chain is built once per client and provider, so middleware may keep state.
+func WithMiddleware(mw ...Middleware) ClientOption {
+	return func(c *ProviderConfig) {
+		c.middleware = append(c.middleware, mw...)
+	}
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Client Integration
+// ═══════════════════════════════════════════════════════════════════════════
+
+// buildChain composes mw around target, the provider the chain ends in.
+func buildChain(mw []Middleware, target Handler) Handler {
+	h := target
+	for i := len(mw) - 1; i >= 0; i-- {
+		h = mw[i](h)
+	}
+	return h
+}
+
+// middlewareProvider sends a provider's requests through the client's chain.
+type middlewareProvider struct {
+	Provider
+	chain Handler
+}
+
+func (p *middlewareProvider) Send(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+	return p.chain.Send(ctx, req)
+}
+
+func (p *middlewareProvider) SendStream(ctx context.Context, req *ProviderRequest, callback StreamCallback) (*ProviderResponse, error) {
+	return p.chain.SendStream(ctx, req, callback)
+}
+
+// withMiddleware returns p wrapped in the client's middleware chain. Each
+// provider gets its own chain, built on first use and reused afterwards.
+func (c *Client) withMiddleware(p Provider) Provider {
+	if len(c.middleware) == 0 {
+		return p
+	}
+	if !reflect.TypeOf(p).Comparable() {
+		return &middlewareProvider{Provider: p, chain: buildChain(c.middleware, p)}
+	}
+	c.chainsMu.Lock()
+	defer c.chainsMu.Unlock()
+	chain, ok := c.chains[p]
+	if !ok {
+		if c.chains == nil {
+			c.chains = map[Provider]Handler{}
+		}
+		chain = buildChain(c.middleware, p)
+		c.chains[p] = chain
+	}
+	return &middlewareProvider{Provider: p, chain: chain}
+}
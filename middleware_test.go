@@ -0,0 +1,116 @@
+package ai
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestMiddleware_ChainOrderRewriteAndShortCircuit(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	var order []string
+	trace := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return Intercept(next, func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+				order = append(order, name)
+				return next.Send(ctx, req)
+			}, nil)
+		}
+	}
+	redact := func(next Handler) Handler {
+		return Intercept(next, func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			msgs := append([]Message(nil), req.Messages...)
+			for i := range msgs {
+				if s, ok := msgs[i].Content.(string); ok {
+					msgs[i].Content = strings.ReplaceAll(s, "secret", "[redacted]")
+				}
+			}
+			r := *req
+			r.Messages = msgs
+			return next.Send(ctx, &r)
+		}, nil)
+	}
+
+	p := &stubProvider{name: "openai", sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+		return &ProviderResponse{Content: req.Messages[0].Content.(string)}, nil
+	}}
+	c := NewClientWithProvider(p, WithMiddleware(trace("outer"), trace("inner"), redact))
+
+	got, err := c.New(ModelGPT4o).User("my secret").Send()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "my [redacted]" {
+		t.Fatalf("expected the request to be rewritten, got %q", got)
+	}
+	if strings.Join(order, ",") != "outer,inner" {
+		t.Fatalf("unexpected order %v", order)
+	}
+
+	// A middleware can answer without calling the provider.
+	canned := func(next Handler) Handler {
+		return Intercept(next, func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			return &ProviderResponse{Content: "canned"}, nil
+		}, nil)
+	}
+	c2 := NewClientWithProvider(p, WithMiddleware(canned))
+	if got, _ := c2.New(ModelGPT4o).User("hi").Send(); got != "canned" || p.Calls() != 1 {
+		t.Fatalf("expected a short-circuit, got %q after %d calls", got, p.Calls())
+	}
+}
+
+func TestMiddleware_CoversStreaming(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	var streamed int
+	count := func(next Handler) Handler {
+		return Intercept(next, nil, func(ctx context.Context, req *ProviderRequest, cb StreamCallback) (*ProviderResponse, error) {
+			streamed++
+			return next.SendStream(ctx, req, func(chunk string) { cb(strings.ToUpper(chunk)) })
+		})
+	}
+	p := &stubProvider{name: "openai", caps: ProviderCapabilities{Streaming: true}, sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+		return &ProviderResponse{Content: "chunk"}, nil
+	}}
+	c := NewClientWithProvider(p, WithMiddleware(count))
+
+	var got string
+	if _, err := c.New(ModelGPT4o).User("hi").StreamResponse(func(s string) { got += s }); err != nil {
+		t.Fatal(err)
+	}
+	if streamed != 1 || got != "CHUNK" {
+		t.Fatalf("expected the stream to pass through middleware, got %d calls and %q", streamed, got)
+	}
+}
+
+func TestMiddleware_TargetSurvivesUnrelatedContext(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	detach := func(next Handler) Handler {
+		return Intercept(next, func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			return next.Send(context.Background(), req)
+		}, nil)
+	}
+	reply := func(name string) *stubProvider {
+		return &stubProvider{name: name, sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			return &ProviderResponse{Content: name}, nil
+		}}
+	}
+	acme, other := reply("acme"), reply("other")
+	r := NewRouterProvider(ProviderConfig{}, RouteRule{Prefix: "acme/", Provider: acme}, RouteRule{Prefix: "other/", Provider: other})
+	c := NewClientWithProvider(r, WithMiddleware(detach))
+
+	for _, want := range []string{"acme", "other", "acme"} {
+		got, err := c.New(Model(want + "/m")).User("hi").Send()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Fatalf("expected %s, got %q", want, got)
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package ai
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Model Lookup
+// ═══════════════════════════════════════════════════════════════════════════
+
+// LookupModel returns catalog metadata for a model. Bare provider IDs
+// (e.g. "gpt-4o" via UseModel) match their namespaced entry.
+func LookupModel(model Model) (ModelInfo, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	if info, ok := ModelCatalog[model]; ok {
+		return info, true
+	}
+	if !strings.Contains(string(model), "/") {
+		for id, info := range ModelCatalog {
+			if strings.HasSuffix(string(id), "/"+string(model)) {
+				return info, true
+			}
+		}
+	}
+	return ModelInfo{}, false
+}
+
+// ContextWindow returns the context window of a known model in tokens, or 0 if unknown.
+func ContextWindow(model Model) int {
+	info, _ := LookupModel(model)
+	return info.ContextWindow
+}
+
+// Accepts reports whether the model takes the given input modality.
+// Models without listed input modalities accept text only.
+func (m ModelInfo) Accepts(mod Modality) bool {
+	if len(m.InputModalities) == 0 {
+		return mod == ModalityText
+	}
+	return containsModality(m.InputModalities, mod)
+}
+
+// Produces reports whether the model can output the given modality.
+func (m ModelInfo) Produces(mod Modality) bool {
+	if len(m.OutputModalities) == 0 {
+		return mod == ModalityText
+	}
+	return containsModality(m.OutputModalities, mod)
+}
+
+// IsDeprecated reports whether the model's retirement date has passed.
+func (m ModelInfo) IsDeprecated() bool {
+	if m.Deprecation == "" {
+		return false
+	}
+	t, err := time.Parse("2006-01-02", m.Deprecation)
+	return err == nil && !time.Now().Before(t)
+}
+
+// Pricing returns the model's pricing, if known.
+func (m ModelInfo) Pricing() (ModelPricing, bool) {
+	return lookupPricing(m.ID)
+}
+
+func containsModality(list []Modality, mod Modality) bool {
+	for _, m := range list {
+		if m == mod {
+			return true
+		}
+	}
+	return false
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Catalog Queries
+// ═══════════════════════════════════════════════════════════════════════════
+
+// ModelQuery is a filterable view of the model catalog. Each filter returns
+// a new query, so partial queries can be reused.
+//
+//	models := ai.Models().WithVision().WithTools().MaxCost(5).List()
+type ModelQuery struct {
+	models []ModelInfo
+}
+
+// Models starts a query over all catalog models, sorted by ID.
+func Models() *ModelQuery {
+	registryMu.RLock()
+	list := make([]ModelInfo, 0, len(ModelCatalog))
+	for _, info := range ModelCatalog {
+		list = append(list, info)
+	}
+	registryMu.RUnlock()
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return &ModelQuery{models: list}
+}
+
+// Where keeps models matching fn.
+func (q *ModelQuery) Where(fn func(ModelInfo) bool) *ModelQuery {
+	var out []ModelInfo
+	for _, m := range q.models {
+		if fn(m) {
+			out = append(out, m)
+		}
+	}
+	return &ModelQuery{models: out}
+}
+
+// WithInput keeps models that accept the given input modality.
+func (q *ModelQuery) WithInput(mod Modality) *ModelQuery {
+	return q.Where(func(m ModelInfo) bool { return m.Accepts(mod) })
+}
+
+// WithOutput keeps models that produce the given output modality.
+func (q *ModelQuery) WithOutput(mod Modality) *ModelQuery {
+	return q.Where(func(m ModelInfo) bool { return m.Produces(mod) })
+}
+
+// WithVision keeps models that accept image input.
+func (q *ModelQuery) WithVision() *ModelQuery { return q.WithInput(ModalityImage) }
+
+// WithDocuments keeps models that read PDFs natively.
+func (q *ModelQuery) WithDocuments() *ModelQuery { return q.WithInput(ModalityDocument) }
+
+// WithAudio keeps models that accept audio input.
+func (q *ModelQuery) WithAudio() *ModelQuery { return q.WithInput(ModalityAudio) }
+
+// WithTools keeps models that support function calling.
+func (q *ModelQuery) WithTools() *ModelQuery {
+	return q.Where(func(m ModelInfo) bool { return m.Tools })
+}
+
+// WithJSON keeps models that support JSON mode.
+func (q *ModelQuery) WithJSON() *ModelQuery {
+	return q.Where(func(m ModelInfo) bool { return m.JSON })
+}
+
+// WithThinking keeps reasoning models.
+func (q *ModelQuery) WithThinking() *ModelQuery {
+	return q.Where(func(m ModelInfo) bool { return m.Thinking })
+}
+
+// FromProvider keeps models from a vendor (e.g. "OpenAI", "Anthropic"), case-insensitive.
+func (q *ModelQuery) FromProvider(name string) *ModelQuery {
+	return q.Where(func(m ModelInfo) bool { return strings.EqualFold(m.Provider, name) })
+}
+
+// MinContext keeps models with a context window of at least tokens.
+func (q *ModelQuery) MinContext(tokens int) *ModelQuery {
+	return q.Where(func(m ModelInfo) bool { return m.ContextWindow >= tokens })
+}
+
+// MinOutput keeps models that can generate at least tokens in one response.
+func (q *ModelQuery) MinOutput(tokens int) *ModelQuery {
+	return q.Where(func(m ModelInfo) bool { return m.MaxOutput >= tokens })
+}
+
+// MaxCost keeps models whose average input/output price is at most
+// perMillion USD per 1M tokens. Models without pricing are dropped.
+func (q *ModelQuery) MaxCost(perMillion float64) *ModelQuery {
+	return q.Where(func(m ModelInfo) bool {
+		p, ok := m.Pricing()
+		return ok && (p.InputPerMillion+p.OutputPerMillion)/2 <= perMillion
+	})
+}
+
+// Active drops models past their retirement date.
+func (q *ModelQuery) Active() *ModelQuery {
+	return q.Where(func(m ModelInfo) bool { return !m.IsDeprecated() })
+}
+
+// List returns the matching models.
+func (q *ModelQuery) List() []ModelInfo {
+	return append([]ModelInfo(nil), q.models...)
+}
+
+// IDs returns the matching model IDs.
+func (q *ModelQuery) IDs() []Model {
+	ids := make([]Model, len(q.models))
+	for i, m := range q.models {
+		ids[i] = m.ID
+	}
+	return ids
+}
+
+// Count returns the number of matching models.
+func (q *ModelQuery) Count() int {
+	return len(q.models)
+}
+
+// Cheapest returns the matching model with the lowest average price.
+func (q *ModelQuery) Cheapest() (ModelInfo, bool) {
+	var best ModelInfo
+	bestCost, found := 0.0, false
+	for _, m := range q.models {
+		p, ok := m.Pricing()
+		if !ok {
+			continue
+		}
+		if cost := (p.InputPerMillion + p.OutputPerMillion) / 2; !found || cost < bestCost {
+			best, bestCost, found = m, cost, true
+		}
+	}
+	return best, found
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Per-Model Capability Checks
+// ═══════════════════════════════════════════════════════════════════════════
+
+// ErrUnsupportedFeature is returned (wrapped in *UnsupportedFeatureError) when
+// a request needs a feature the catalog says the model lacks.
+var ErrUnsupportedFeature = errors.New("feature not supported by model")
+
+// UnsupportedFeatureError reports a request skipped before it was sent.
+type UnsupportedFeatureError struct {
+	Model   Model
+	Feature string
+}
+
+// Error implements the error interface.
+func (e *UnsupportedFeatureError) Error() string {
+	return fmt.Sprintf("%s does not support %s", e.Model, e.Feature)
+}
+
+// Unwrap returns ErrUnsupportedFeature so errors.Is works.
+func (e *UnsupportedFeatureError) Unwrap() error {
+	return ErrUnsupportedFeature
+}
+
+// checkModelSupport rejects requests that need image or document input or
+// tools the model lacks, and warns (in debug mode) about JSON mode and thinking, which degrade
+// gracefully. Models missing from the catalog, or discovered without
+// capability data, are not checked.
+func (b *Builder) checkModelSupport(model Model, msgs []Message) error {
+	info, ok := LookupModel(model)
+	if !ok || info.capsUnknown {
+		return nil
+	}
+	if hasImageParts(msgs) && !info.Accepts(ModalityImage) {
+		return &UnsupportedFeatureError{Model: model, Feature: "image input"}
+	}
+	if hasDocumentParts(msgs) && !info.Accepts(ModalityDocument) {
+		return &UnsupportedFeatureError{Model: model, Feature: "document input"}
+	}
+	if len(b.tools) > 0 && !info.Tools {
+		return &UnsupportedFeatureError{Model: model, Feature: "tools"}
+	}
+	if b.currentSettings().debug {
+		if b.jsonMode && !info.JSON {
+			fmt.Printf("%s Warning: %s does not support JSON mode\n", colorYellow("⚠"), model)
+		}
+		if b.thinking != "" && !info.Thinking {
+			fmt.Printf("%s Warning: %s does not support thinking/reasoning\n", colorYellow("⚠"), model)
+		}
+	}
+	return nil
+}
+
+func hasImageParts(msgs []Message) bool {
+	for _, m := range msgs {
+		if parts, ok := m.Content.([]ContentPart); ok {
+			for _, p := range parts {
+				if p.ImageURL != nil {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func hasDocumentParts(msgs []Message) bool {
+	for _, m := range msgs {
+		if parts, ok := m.Content.([]ContentPart); ok {
+			for _, p := range parts {
+				if p.Document != nil {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
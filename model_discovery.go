@@ -0,0 +1,114 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Live Model Discovery
+// ═══════════════════════════════════════════════════════════════════════════
+
+// ListModels fetches the provider's model list and merges it into the catalog.
+// Live limits and pricing override the built-in tables; curated names,
+// descriptions and capability flags are kept.
+//
+//	models, err := ai.NewClient(ai.ProviderOpenRouter).ListModels(ctx)
+func (c *Client) ListModels(ctx context.Context) ([]ModelInfo, error) {
+	lister, ok := c.provider.(ModelLister)
+	if !ok {
+		return nil, fmt.Errorf("provider %s does not support listing models", c.provider.Name())
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx = withSettings(ctx, c.resolve(settings{}))
+
+	specs, err := lister.ListModels(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := mergeModels(specs)
+	sort.Slice(infos, func(i, j int) bool { return infos[i].ID < infos[j].ID })
+
+	if debugOn(ctx) {
+		fmt.Printf("%s [%s] discovered %d model(s)\n", colorGreen("✓"), c.provider.Name(), len(infos))
+	}
+	return infos, nil
+}
+
+// ListModels lists models using the default client.
+func ListModels(ctx context.Context) ([]ModelInfo, error) {
+	return getDefaultClient().ListModels(ctx)
+}
+
+// getJSON performs a GET against a provider list endpoint and decodes the body into out.
+func getJSON(ctx context.Context, client *http.Client, provider, url string, setHeaders func(*http.Request), out any) error {
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return &ProviderError{Provider: provider, Message: "failed to create request", Err: err}
+	}
+	setHeaders(httpReq)
+
+	if debugOn(ctx) {
+		fmt.Printf("%s [%s] GET %s\n", colorDim("→"), provider, httpReq.URL.Path)
+	}
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return &ProviderError{Provider: provider, Message: "request failed", Err: err}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return &ProviderError{Provider: provider, Message: "failed to read response", Err: err}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return newHTTPError(provider, resp, body)
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return &ProviderError{Provider: provider, Message: "parse error", Err: err}
+	}
+	return nil
+}
+
+// catalogIndex maps provider-native IDs back to catalog IDs, so discovered
+// models update existing entries (e.g. "claude-sonnet-4-5-20250929" →
+// "anthropic/claude-sonnet-4.5") instead of creating duplicates.
+func catalogIndex(provider ProviderType) map[string]Model {
+	registryMu.RLock()
+	ids := make([]Model, 0, len(ModelCatalog))
+	for id := range ModelCatalog {
+		ids = append(ids, id)
+	}
+	registryMu.RUnlock()
+
+	// Sort so aliases resolve deterministically.
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	index := make(map[string]Model, len(ids))
+	for _, id := range ids {
+		native := resolveModel(provider, id)
+		if _, taken := index[native]; !taken {
+			index[native] = id
+		}
+	}
+	return index
+}
+
+// discoveredSpec builds the spec for a native model ID, reusing the catalog
+// entry when one maps to it and otherwise namespacing it under prefix.
+func discoveredSpec(index map[string]Model, provider ProviderType, prefix, native string) ModelSpec {
+	if id, ok := index[native]; ok {
+		return ModelSpec{ID: id}
+	}
+	return ModelSpec{
+		ID:          Model(prefix + native),
+		ProviderIDs: map[ProviderType]string{provider: native},
+	}
+}
@@ -0,0 +1,216 @@
+package ai
+
+import (
+	"context"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// withRegistrySnapshot restores the model catalog, pricing and mappings after the test.
+func withRegistrySnapshot(t *testing.T) {
+	t.Helper()
+
+	registryMu.Lock()
+	models := maps.Clone(ModelCatalog)
+	pricing := maps.Clone(ModelPricingMap)
+	mappings := make(map[ProviderType]map[Model]string, len(modelMappings))
+	for p, m := range modelMappings {
+		mappings[p] = maps.Clone(m)
+	}
+	registryMu.Unlock()
+
+	t.Cleanup(func() {
+		registryMu.Lock()
+		ModelCatalog, ModelPricingMap, modelMappings = models, pricing, mappings
+		registryMu.Unlock()
+	})
+}
+
+func listModelsServer(t *testing.T, path string, handler func(w http.ResponseWriter, r *http.Request)) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != path {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		handler(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestListModels_OpenRouterRefreshesPricing(t *testing.T) {
+	defer withTestGlobals(t)()
+	withRegistrySnapshot(t)
+
+	srv := listModelsServer(t, "/models", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data": [
+			{"id": "openai/gpt-4o", "name": "OpenAI: GPT-4o (renamed)", "context_length": 256000,
+			 "pricing": {"prompt": "0.000002", "completion": "0.000008"},
+			 "architecture": {"input_modalities": ["text", "image", "file"], "output_modalities": ["text"]},
+			 "top_provider": {"max_completion_tokens": 32768},
+			 "supported_parameters": ["tools", "response_format"]},
+			{"id": "acme/new-model", "name": "Acme: New Model", "context_length": 64000,
+			 "pricing": {"prompt": "0.0000001", "completion": "0.0000004"},
+			 "architecture": {"input_modalities": ["text"], "output_modalities": ["text"]},
+			 "top_provider": {"max_completion_tokens": null},
+			 "supported_parameters": ["reasoning"]},
+			{"id": "openrouter/auto", "name": "Auto Router", "context_length": 2000000,
+			 "pricing": {"prompt": "-1", "completion": "-1"}}
+		]}`))
+	})
+
+	client := NewClientWithProvider(NewOpenRouterProvider(ProviderConfig{APIKey: "k", BaseURL: srv.URL}))
+	infos, err := client.ListModels(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(infos) != 3 {
+		t.Fatalf("expected 3 models, got %d", len(infos))
+	}
+
+	gpt4o, _ := LookupModel(ModelGPT4o)
+	if gpt4o.Name != "GPT-4o" || gpt4o.ContextWindow != 256000 || gpt4o.MaxOutput != 32768 || !gpt4o.Accepts(ModalityDocument) {
+		t.Fatalf("expected live limits with curated name, got %+v", gpt4o)
+	}
+	if p, _ := lookupPricing(ModelGPT4o); p.InputPerMillion != 2 || p.OutputPerMillion != 8 {
+		t.Fatalf("expected refreshed pricing, got %+v", p)
+	}
+
+	acme, ok := LookupModel("acme/new-model")
+	if !ok || acme.Provider != "Acme" || !acme.Thinking || acme.Tools || acme.ContextWindow != 64000 {
+		t.Fatalf("unexpected discovered model: %+v", acme)
+	}
+	if cost := CalculateCost("acme/new-model", 1_000_000, 1_000_000); cost != 0.5 {
+		t.Fatalf("expected discovered pricing, got %v", cost)
+	}
+	if _, ok := lookupPricing("openrouter/auto"); ok {
+		t.Fatal("variable pricing should not be recorded")
+	}
+}
+
+func TestListModels_OpenAI(t *testing.T) {
+	defer withTestGlobals(t)()
+	withRegistrySnapshot(t)
+
+	var gotAuth string
+	srv := listModelsServer(t, "/models", func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`{"data": [
+			{"id": "gpt-4o", "owned_by": "system"},
+			{"id": "gpt-9-preview", "owned_by": "system"},
+			{"id": "text-embedding-3-small", "owned_by": "system"}
+		]}`))
+	})
+
+	client := NewClientWithProvider(NewOpenAIProvider(ProviderConfig{APIKey: "k", BaseURL: srv.URL}))
+	infos, err := client.ListModels(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotAuth != "Bearer k" {
+		t.Fatalf("unexpected auth header %q", gotAuth)
+	}
+	if len(infos) != 2 || infos[0].ID != ModelGPT4o || infos[1].ID != "openai/gpt-9-preview" {
+		t.Fatalf("unexpected models: %+v", infos)
+	}
+	if got := resolveModel(ProviderOpenAI, "openai/gpt-9-preview"); got != "gpt-9-preview" {
+		t.Fatalf("unexpected mapping %q", got)
+	}
+
+	// Listing a model without capability data must not block tool requests to it.
+	b := client.New("openai/gpt-9-preview").Tool("lookup", "Look up", Params().Build())
+	if err := b.checkModelSupport("openai/gpt-9-preview", nil); err != nil {
+		t.Fatalf("expected unknown capabilities to be allowed, got %v", err)
+	}
+	if err := b.checkModelSupport(ModelO1Mini, nil); err == nil {
+		t.Fatal("expected catalog models to still be checked")
+	}
+}
+
+func TestListModels_AnthropicPaginates(t *testing.T) {
+	defer withTestGlobals(t)()
+	withRegistrySnapshot(t)
+
+	srv := listModelsServer(t, "/models", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-api-key") != "k" {
+			t.Errorf("missing x-api-key header")
+		}
+		if r.URL.Query().Get("after_id") == "" {
+			_, _ = w.Write([]byte(`{"data": [{"id": "claude-sonnet-4-5-20250929", "display_name": "Claude Sonnet 4.5"}],
+				"has_more": true, "last_id": "claude-sonnet-4-5-20250929"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data": [{"id": "claude-next-20270101", "display_name": "Claude Next"},
+			{"id": "claude-3-haiku-20240307", "display_name": "Claude Haiku 3"}], "has_more": false}`))
+	})
+
+	client := NewClientWithProvider(NewAnthropicProvider(ProviderConfig{APIKey: "k", BaseURL: srv.URL}))
+	infos, err := client.ListModels(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(infos) != 3 || infos[1].ID != "anthropic/claude-next-20270101" || infos[2].ID != ModelClaudeSonnet {
+		t.Fatalf("expected snapshot to map onto the catalog entry, got %+v", infos)
+	}
+	if !infos[1].Tools || !infos[1].Accepts(ModalityImage) || infos[1].Name != "Claude Next" {
+		t.Fatalf("unexpected new Claude model: %+v", infos[1])
+	}
+	// Known models keep the catalog's modalities: Haiku 3 reads images but not PDFs.
+	if infos[0].ID != ModelClaudeHaiku3 || !infos[0].Accepts(ModalityImage) || infos[0].Accepts(ModalityDocument) {
+		t.Fatalf("expected catalog modalities for Haiku 3, got %+v", infos[0])
+	}
+	if got := resolveModel(ProviderAnthropic, infos[1].ID); got != "claude-next-20270101" {
+		t.Fatalf("unexpected mapping %q", got)
+	}
+}
+
+func TestListModels_GeminiAndOllama(t *testing.T) {
+	defer withTestGlobals(t)()
+	withRegistrySnapshot(t)
+
+	gemini := listModelsServer(t, "/models", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("key") != "k" {
+			t.Errorf("missing API key")
+		}
+		_, _ = w.Write([]byte(`{"models": [
+			{"name": "models/gemini-2.5-pro", "displayName": "Gemini 2.5 Pro", "inputTokenLimit": 1048576,
+			 "outputTokenLimit": 65536, "supportedGenerationMethods": ["generateContent", "countTokens"], "thinking": true},
+			{"name": "models/text-embedding-004", "supportedGenerationMethods": ["embedContent"]}
+		]}`))
+	})
+	infos, err := NewClientWithProvider(NewGoogleProvider(ProviderConfig{APIKey: "k", BaseURL: gemini.URL})).ListModels(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(infos) != 1 || infos[0].ID != ModelGemini25Pro || infos[0].MaxOutput != 65536 {
+		t.Fatalf("unexpected Gemini models: %+v", infos)
+	}
+
+	ollama := listModelsServer(t, "/api/tags", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"models": [{"name": "llama3.2:3b",
+			"details": {"family": "llama", "parameter_size": "3.2B", "quantization_level": "Q4_K_M"}}]}`))
+	})
+	infos, err = NewClientWithProvider(NewOllamaProvider(ProviderConfig{BaseURL: ollama.URL})).ListModels(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(infos) != 1 || infos[0].ID != "llama3.2:3b" || infos[0].Description != "llama 3.2B Q4_K_M" {
+		t.Fatalf("unexpected Ollama models: %+v", infos)
+	}
+}
+
+func TestListModels_Unsupported(t *testing.T) {
+	defer withTestGlobals(t)()
+
+	if _, err := NewClientWithProvider(&stubProvider{name: "stub"}).ListModels(context.Background()); err == nil {
+		t.Fatal("expected error for provider without model listing")
+	}
+	if _, err := NewClient(ProviderAzure, WithAPIKey("k")).ListModels(context.Background()); err == nil {
+		t.Fatal("expected error for Azure")
+	}
+}
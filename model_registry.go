@@ -0,0 +1,556 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Runtime Model Registration
+// ═══════════════════════════════════════════════════════════════════════════
+
+// registryMu guards ModelCatalog, ModelPricingMap and modelMappings.
+// Use RegisterModel rather than writing those maps directly once requests
+// may be in flight.
+var registryMu sync.RWMutex
+
+// ModelSpec declares a model at runtime: catalog metadata, per-provider IDs and pricing.
+type ModelSpec struct {
+	ID          Model  `json:"id"`
+	Name        string `json:"name,omitempty"`
+	Provider    string `json:"provider,omitempty"` // Vendor display name, e.g. "OpenAI"
+	Description string `json:"description,omitempty"`
+
+	// ProviderIDs maps a provider to its native model ID,
+	// e.g. {"openai": "ft:gpt-4.1-mini:acme::abc123"}.
+	ProviderIDs map[ProviderType]string `json:"provider_ids,omitempty"`
+
+	Pricing *ModelPricing `json:"pricing,omitempty"` // nil = leave pricing unchanged
+
+	ContextWindow    int        `json:"context_window,omitempty"`
+	MaxOutput        int        `json:"max_output,omitempty"`
+	InputModalities  []Modality `json:"input_modalities,omitempty"`
+	OutputModalities []Modality `json:"output_modalities,omitempty"`
+
+	Tools    bool `json:"tools,omitempty"`
+	JSON     bool `json:"json,omitempty"`
+	Thinking bool `json:"thinking,omitempty"`
+
+	KnowledgeCutoff string `json:"knowledge_cutoff,omitempty"`
+	Deprecation     string `json:"deprecation,omitempty"`
+}
+
+// RegisterModel adds or replaces a model in the catalog, its provider ID
+// mappings and (if set) its pricing. Safe for concurrent use.
+func RegisterModel(spec ModelSpec) error {
+	if spec.ID == "" {
+		return fmt.Errorf("register model: missing id")
+	}
+	if spec.Name == "" {
+		spec.Name = string(spec.ID)
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	ModelCatalog[spec.ID] = ModelInfo{
+		ID:               spec.ID,
+		Name:             spec.Name,
+		Provider:         spec.Provider,
+		Description:      spec.Description,
+		ContextWindow:    spec.ContextWindow,
+		MaxOutput:        spec.MaxOutput,
+		InputModalities:  spec.InputModalities,
+		OutputModalities: spec.OutputModalities,
+		Tools:            spec.Tools,
+		JSON:             spec.JSON,
+		Thinking:         spec.Thinking,
+		KnowledgeCutoff:  spec.KnowledgeCutoff,
+		Deprecation:      spec.Deprecation,
+	}
+	if spec.Pricing != nil {
+		ModelPricingMap[spec.ID] = *spec.Pricing
+	}
+	addMappingsLocked(spec)
+	return nil
+}
+
+// RegisterModels registers several models, stopping at the first invalid spec.
+func RegisterModels(specs ...ModelSpec) error {
+	for _, spec := range specs {
+		if err := RegisterModel(spec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UnregisterModel removes a model from the catalog, pricing and provider mappings.
+func UnregisterModel(id Model) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	delete(ModelCatalog, id)
+	delete(ModelPricingMap, id)
+	for _, mapping := range modelMappings {
+		delete(mapping, id)
+	}
+}
+
+// mergeModels folds discovered specs into the catalog and returns the merged
+// entries. Non-zero limits and pricing replace existing values; names,
+// descriptions, modalities and dates only fill blanks; capability flags are OR'd.
+// New models listed without any capability data are not checked per request,
+// so discovering a model never blocks requests that worked before.
+func mergeModels(specs []ModelSpec) []ModelInfo {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	infos := make([]ModelInfo, 0, len(specs))
+	for _, spec := range specs {
+		if spec.ID == "" {
+			continue
+		}
+		info, ok := ModelCatalog[spec.ID]
+		if !ok {
+			info = ModelInfo{ID: spec.ID, capsUnknown: true}
+		}
+		if len(spec.InputModalities) > 0 || spec.Tools || spec.JSON || spec.Thinking {
+			info.capsUnknown = false
+		}
+		if info.Name == "" {
+			info.Name = spec.Name
+		}
+		if info.Name == "" {
+			info.Name = string(spec.ID)
+		}
+		if info.Provider == "" {
+			info.Provider = spec.Provider
+		}
+		if info.Description == "" {
+			info.Description = spec.Description
+		}
+		if spec.ContextWindow > 0 {
+			info.ContextWindow = spec.ContextWindow
+		}
+		if spec.MaxOutput > 0 {
+			info.MaxOutput = spec.MaxOutput
+		}
+		if len(info.InputModalities) == 0 {
+			info.InputModalities = spec.InputModalities
+		}
+		if len(info.OutputModalities) == 0 {
+			info.OutputModalities = spec.OutputModalities
+		}
+		info.Tools = info.Tools || spec.Tools
+		info.JSON = info.JSON || spec.JSON
+		info.Thinking = info.Thinking || spec.Thinking
+		if info.KnowledgeCutoff == "" {
+			info.KnowledgeCutoff = spec.KnowledgeCutoff
+		}
+		if info.Deprecation == "" {
+			info.Deprecation = spec.Deprecation
+		}
+
+		ModelCatalog[spec.ID] = info
+		if spec.Pricing != nil {
+			ModelPricingMap[spec.ID] = *spec.Pricing
+		}
+		addMappingsLocked(spec)
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// addMappingsLocked records spec's provider IDs. registryMu must be held.
+func addMappingsLocked(spec ModelSpec) {
+	for provider, id := range spec.ProviderIDs {
+		if modelMappings[provider] == nil {
+			modelMappings[provider] = map[Model]string{}
+		}
+		modelMappings[provider][spec.ID] = id
+	}
+}
+
+// lookupPricing returns the pricing registered for model.
+func lookupPricing(model Model) (ModelPricing, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	p, ok := ModelPricingMap[model]
+	return p, ok
+}
+
+// lookupMapping returns the provider-native ID registered for model. Azure
+// falls back to the OpenAI IDs.
+func lookupMapping(provider ProviderType, model Model) (string, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	id, ok := modelMappings[provider][model]
+	if !ok && provider == ProviderAzure {
+		id, ok = modelMappings[ProviderOpenAI][model]
+	}
+	return id, ok
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Loading from JSON / YAML
+// ═══════════════════════════════════════════════════════════════════════════
+
+// modelFile is the on-disk format: either a bare list of specs or {"models": [...]}.
+type modelFile struct {
+	Models []ModelSpec `json:"models"`
+}
+
+// LoadModelsJSON registers models from JSON, either a list of specs or an
+// object with a "models" list.
+func LoadModelsJSON(r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	specs, err := decodeModelSpecs(data)
+	if err != nil {
+		return err
+	}
+	return RegisterModels(specs...)
+}
+
+// LoadModelsYAML registers models from YAML with the same layout as LoadModelsJSON.
+// It understands the block-style subset used by config files: nested maps,
+// "- " lists, [flow, lists], scalars, quotes and comments (no anchors or multi-line strings).
+//
+//	models:
+//	  - id: acme/support-bot
+//	    name: Support Bot
+//	    provider_ids: {openai: "ft:gpt-4.1-mini:acme::abc123"}
+//	    pricing: {input_per_million: 0.8, output_per_million: 3.2}
+//	    context_window: 1047576
+//	    tools: true
+func LoadModelsYAML(r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	doc, err := parseYAML(string(data))
+	if err != nil {
+		return err
+	}
+	asJSON, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	specs, err := decodeModelSpecs(asJSON)
+	if err != nil {
+		return err
+	}
+	return RegisterModels(specs...)
+}
+
+// LoadModelsFile registers models from a .json, .yaml or .yml file.
+func LoadModelsFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return LoadModelsYAML(f)
+	default:
+		return LoadModelsJSON(f)
+	}
+}
+
+func decodeModelSpecs(data []byte) ([]ModelSpec, error) {
+	trimmed := strings.TrimSpace(string(data))
+	if strings.HasPrefix(trimmed, "[") {
+		var specs []ModelSpec
+		if err := json.Unmarshal(data, &specs); err != nil {
+			return nil, fmt.Errorf("invalid model list: %w", err)
+		}
+		return specs, nil
+	}
+	var file modelFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid model file: %w", err)
+	}
+	return file.Models, nil
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Minimal YAML Reader
+// ═══════════════════════════════════════════════════════════════════════════
+
+type yamlLine struct {
+	num    int
+	indent int
+	text   string
+}
+
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+// parseYAML converts a block-style YAML document into maps, slices and scalars.
+func parseYAML(src string) (any, error) {
+	p := &yamlParser{}
+	for i, raw := range strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n") {
+		text := stripYAMLComment(raw)
+		trimmed := strings.TrimLeft(text, " ")
+		if strings.TrimSpace(trimmed) == "" || trimmed == "---" {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "\t") {
+			return nil, fmt.Errorf("yaml line %d: tabs are not allowed for indentation", i+1)
+		}
+		p.lines = append(p.lines, yamlLine{num: i + 1, indent: len(text) - len(trimmed), text: strings.TrimRight(trimmed, " \t")})
+	}
+	if len(p.lines) == 0 {
+		return nil, nil
+	}
+	v, err := p.parseNode(p.lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.lines) {
+		return nil, fmt.Errorf("yaml line %d: unexpected indentation", p.lines[p.pos].num)
+	}
+	return v, nil
+}
+
+func (p *yamlParser) parseNode(indent int) (any, error) {
+	if isYAMLItem(p.lines[p.pos].text) {
+		return p.parseSeq(indent)
+	}
+	return p.parseMap(indent)
+}
+
+func (p *yamlParser) parseSeq(indent int) ([]any, error) {
+	out := []any{}
+	for p.pos < len(p.lines) && p.lines[p.pos].indent == indent && isYAMLItem(p.lines[p.pos].text) {
+		line := p.lines[p.pos]
+		rest := strings.TrimLeft(line.text[1:], " ")
+		switch {
+		case rest == "":
+			p.pos++
+			if p.pos >= len(p.lines) || p.lines[p.pos].indent <= indent {
+				out = append(out, nil)
+				continue
+			}
+			v, err := p.parseNode(p.lines[p.pos].indent)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+		case yamlKeyValue(rest) >= 0 && !strings.HasPrefix(rest, "{") && !strings.HasPrefix(rest, "["):
+			// "- key: value" starts a map indented at the key's column
+			childIndent := line.indent + len(line.text) - len(rest)
+			p.lines[p.pos] = yamlLine{num: line.num, indent: childIndent, text: rest}
+			v, err := p.parseMap(childIndent)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+		default:
+			p.pos++
+			v, err := parseYAMLScalar(rest)
+			if err != nil {
+				return nil, fmt.Errorf("yaml line %d: %w", line.num, err)
+			}
+			out = append(out, v)
+		}
+	}
+	return out, nil
+}
+
+func (p *yamlParser) parseMap(indent int) (map[string]any, error) {
+	out := map[string]any{}
+	for p.pos < len(p.lines) && p.lines[p.pos].indent == indent && !isYAMLItem(p.lines[p.pos].text) {
+		line := p.lines[p.pos]
+		sep := yamlKeyValue(line.text)
+		if sep < 0 {
+			return nil, fmt.Errorf("yaml line %d: expected \"key: value\"", line.num)
+		}
+		key := unquoteYAML(strings.TrimSpace(line.text[:sep]))
+		rest := strings.TrimSpace(line.text[sep+1:])
+		p.pos++
+
+		if rest != "" {
+			v, err := parseYAMLScalar(rest)
+			if err != nil {
+				return nil, fmt.Errorf("yaml line %d: %w", line.num, err)
+			}
+			out[key] = v
+			continue
+		}
+		// Nested block: deeper indentation, or a list at the same indentation
+		if p.pos < len(p.lines) {
+			next := p.lines[p.pos]
+			if next.indent > indent || (next.indent == indent && isYAMLItem(next.text)) {
+				v, err := p.parseNode(next.indent)
+				if err != nil {
+					return nil, err
+				}
+				out[key] = v
+				continue
+			}
+		}
+		out[key] = nil
+	}
+	if p.pos < len(p.lines) && p.lines[p.pos].indent > indent {
+		return nil, fmt.Errorf("yaml line %d: unexpected indentation", p.lines[p.pos].num)
+	}
+	return out, nil
+}
+
+func isYAMLItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// yamlKeyValue returns the index of the ':' separating key and value, or -1.
+func yamlKeyValue(text string) int {
+	quote := byte(0)
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case (c == '"' || c == '\'') && opensYAMLQuote(text, i):
+			quote = c
+		case c == ':' && (i+1 == len(text) || text[i+1] == ' '):
+			return i
+		}
+	}
+	return -1
+}
+
+// parseYAMLScalar parses a scalar or a single-line [list] / {map}.
+func parseYAMLScalar(s string) (any, error) {
+	s = strings.TrimSpace(s)
+	switch {
+	case strings.HasPrefix(s, "["):
+		if !strings.HasSuffix(s, "]") {
+			return nil, fmt.Errorf("unterminated flow list %q", s)
+		}
+		out := []any{}
+		for _, item := range splitYAMLFlow(s[1 : len(s)-1]) {
+			v, err := parseYAMLScalar(item)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+		}
+		return out, nil
+	case strings.HasPrefix(s, "{"):
+		if !strings.HasSuffix(s, "}") {
+			return nil, fmt.Errorf("unterminated flow map %q", s)
+		}
+		out := map[string]any{}
+		for _, item := range splitYAMLFlow(s[1 : len(s)-1]) {
+			sep := yamlKeyValue(item)
+			if sep < 0 {
+				return nil, fmt.Errorf("expected \"key: value\" in %q", item)
+			}
+			v, err := parseYAMLScalar(item[sep+1:])
+			if err != nil {
+				return nil, err
+			}
+			out[unquoteYAML(strings.TrimSpace(item[:sep]))] = v
+		}
+		return out, nil
+	case strings.HasPrefix(s, `"`) || strings.HasPrefix(s, "'"):
+		return unquoteYAML(s), nil
+	}
+
+	switch s {
+	case "", "~", "null", "Null", "NULL":
+		return nil, nil
+	case "true", "True", "TRUE":
+		return true, nil
+	case "false", "False", "FALSE":
+		return false, nil
+	}
+	if n, err := strconv.ParseInt(strings.ReplaceAll(s, "_", ""), 10, 64); err == nil {
+		return n, nil
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f, nil
+	}
+	return s, nil
+}
+
+// splitYAMLFlow splits "a, [b, c], 'd, e'" on top-level commas.
+func splitYAMLFlow(s string) []string {
+	var parts []string
+	depth, start := 0, 0
+	quote := byte(0)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case (c == '"' || c == '\'') && opensYAMLQuote(s, i):
+			quote = c
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		case c == ',' && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	if last := strings.TrimSpace(s[start:]); last != "" {
+		parts = append(parts, last)
+	}
+	return parts
+}
+
+func unquoteYAML(s string) string {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		if u, err := strconv.Unquote(s); err == nil {
+			return u
+		}
+		return s[1 : len(s)-1]
+	}
+	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'")
+	}
+	return s
+}
+
+func stripYAMLComment(line string) string {
+	quote := byte(0)
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case (c == '"' || c == '\'') && opensYAMLQuote(line, i):
+			quote = c
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// opensYAMLQuote reports whether the quote at s[i] starts a quoted token
+// (so apostrophes inside plain words, like "Acme's", are ignored).
+func opensYAMLQuote(s string, i int) bool {
+	return i == 0 || strings.IndexByte(" \t:,[{'", s[i-1]) >= 0
+}
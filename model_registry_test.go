@@ -0,0 +1,150 @@
+package ai
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestRegisterModel(t *testing.T) {
+	id := Model("acme/support-bot")
+	defer UnregisterModel(id)
+
+	err := RegisterModel(ModelSpec{
+		ID:              id,
+		Name:            "Support Bot",
+		ProviderIDs:     map[ProviderType]string{ProviderOpenAI: "ft:gpt-4.1-mini:acme::abc123"},
+		Pricing:         &ModelPricing{InputPerMillion: 1, OutputPerMillion: 3},
+		ContextWindow:   1_047_576,
+		InputModalities: []Modality{ModalityText, ModalityImage},
+		Tools:           true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := resolveModel(ProviderOpenAI, id); got != "ft:gpt-4.1-mini:acme::abc123" {
+		t.Fatalf("unexpected provider ID: %q", got)
+	}
+	if got := resolveModel(ProviderAzure, id); got != "ft:gpt-4.1-mini:acme::abc123" {
+		t.Fatalf("expected Azure to share OpenAI mappings, got %q", got)
+	}
+	if cost := CalculateCost(id, 1_000_000, 1_000_000); cost != 4 {
+		t.Fatalf("expected registered pricing, got %v", cost)
+	}
+	if ContextWindow(id) != 1_047_576 {
+		t.Fatal("expected registered context window")
+	}
+	found := false
+	for _, m := range Models().WithVision().WithTools().IDs() {
+		found = found || m == id
+	}
+	if !found {
+		t.Fatal("expected registered model in catalog queries")
+	}
+
+	UnregisterModel(id)
+	if _, ok := LookupModel(id); ok {
+		t.Fatal("expected model to be removed")
+	}
+	if got := resolveModel(ProviderOpenAI, id); got != string(id) {
+		t.Fatalf("expected mapping to be removed, got %q", got)
+	}
+}
+
+func TestRegisterModel_AzureOverridesStayAzure(t *testing.T) {
+	withRegistrySnapshot(t)
+
+	if err := RegisterModel(ModelSpec{ID: ModelGPT4o, ProviderIDs: map[ProviderType]string{ProviderAzure: "my-deployment"}}); err != nil {
+		t.Fatal(err)
+	}
+	if got := resolveModel(ProviderAzure, ModelGPT4o); got != "my-deployment" {
+		t.Fatalf("expected the Azure override, got %q", got)
+	}
+	if got := resolveModel(ProviderOpenAI, ModelGPT4o); got != "gpt-4o" {
+		t.Fatalf("expected OpenAI to keep its ID, got %q", got)
+	}
+}
+
+func TestLoadModelsJSONAndYAML(t *testing.T) {
+	defer UnregisterModel("acme/json-model")
+	defer UnregisterModel("acme/yaml-model")
+
+	jsonSrc := `{"models": [{
+		"id": "acme/json-model",
+		"name": "Acme's Model",
+		"provider_ids": {"openai": "ft:gpt-4o:acme::1"},
+		"pricing": {"input_per_million": 0.5, "output_per_million": 1.5},
+		"context_window": 128000,
+		"input_modalities": ["text", "image"],
+		"tools": true,
+		"deprecation": "2027-01-01"
+	}]}`
+	if err := LoadModelsJSON(strings.NewReader(jsonSrc)); err != nil {
+		t.Fatal(err)
+	}
+
+	yamlSrc := `
+# Shipped by ops
+models:
+  - id: acme/yaml-model
+    name: Acme's Model   # apostrophe is not a quote
+    provider_ids:
+      openai: "ft:gpt-4o:acme::1"
+    pricing: {input_per_million: 0.5, output_per_million: 1.5}
+    context_window: 128_000
+    input_modalities: [text, image]
+    tools: true
+    deprecation: '2027-01-01'
+`
+	if err := LoadModelsYAML(strings.NewReader(yamlSrc)); err != nil {
+		t.Fatal(err)
+	}
+
+	fromJSON, ok1 := LookupModel("acme/json-model")
+	fromYAML, ok2 := LookupModel("acme/yaml-model")
+	if !ok1 || !ok2 {
+		t.Fatal("expected both models to be registered")
+	}
+	fromYAML.ID = fromJSON.ID
+	if !reflect.DeepEqual(fromJSON, fromYAML) {
+		t.Fatalf("YAML and JSON should produce the same model:\n%+v\n%+v", fromJSON, fromYAML)
+	}
+	if fromJSON.Name != "Acme's Model" || fromJSON.ContextWindow != 128_000 || !fromJSON.Accepts(ModalityImage) {
+		t.Fatalf("unexpected model: %+v", fromJSON)
+	}
+	if p, _ := lookupPricing("acme/yaml-model"); p.OutputPerMillion != 1.5 {
+		t.Fatalf("unexpected YAML pricing: %+v", p)
+	}
+}
+
+func TestParseYAMLErrors(t *testing.T) {
+	if _, err := parseYAML("models:\n  - id: a\n      name: b\n"); err == nil {
+		t.Fatal("expected indentation error")
+	}
+	if _, err := parseYAML("pricing: {input_per_million: 1"); err == nil {
+		t.Fatal("expected unterminated flow map error")
+	}
+}
+
+func TestRegisterModelConcurrent(t *testing.T) {
+	id := Model("acme/concurrent")
+	defer UnregisterModel(id)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_ = RegisterModel(ModelSpec{ID: id, ProviderIDs: map[ProviderType]string{ProviderOpenAI: "x"}, Pricing: &ModelPricing{1, 1}})
+		}()
+		go func() {
+			defer wg.Done()
+			_ = resolveModel(ProviderOpenAI, id)
+			_ = CalculateCost(id, 10, 10)
+			_ = Models().Count()
+		}()
+	}
+	wg.Wait()
+}
@@ -144,44 +144,312 @@ const (
 	ModelMistralLarge Model = "mistralai/mistral-large"
 )
 
-// ModelInfo contains descriptive metadata about a model.
+// ModelInfo contains descriptive metadata, limits and capabilities of a model.
 type ModelInfo struct {
 	ID          Model
 	Name        string
 	Provider    string
 	Description string
+
+	ContextWindow    int        // Total tokens (prompt + output), 0 = unknown
+	MaxOutput        int        // Max output tokens, 0 = unknown
+	InputModalities  []Modality // Accepted inputs (nil = text only)
+	OutputModalities []Modality // Produced outputs (nil = text only)
+
+	Tools    bool // Function calling
+	JSON     bool // JSON mode / structured output
+	Thinking bool // Reasoning effort / extended thinking
+
+	KnowledgeCutoff string // "YYYY-MM"
+	Deprecation     string // Retirement date "YYYY-MM-DD" ("" = none announced)
+
+	capsUnknown bool // discovered without capability data, so requests aren't checked against it
 }
 
-// Models is a registry of known models with their metadata.
-// Useful for UI lists or validation.
-var Models = map[Model]ModelInfo{
-	ModelGPT5:              {ModelGPT5, "GPT-5.2", "OpenAI", "General purpose, long context"},
-	ModelGPT5Mini:          {ModelGPT5Mini, "GPT-5 mini", "OpenAI", "Faster, cost-efficient version of GPT-5"},
-	ModelGPT5Nano:          {ModelGPT5Nano, "GPT-5 nano", "OpenAI", "Fastest, most cost-efficient version of GPT-5"},
-	ModelGPT5Codex:         {ModelGPT5Codex, "GPT-5.1 Codex Max", "OpenAI", "Most intelligent agentic coding model (Codex)"},
-	ModelGPT4o:             {ModelGPT4o, "GPT-4o", "OpenAI", "Multimodal flagship"},
-	ModelClaudeOpus:        {ModelClaudeOpus, "Claude Opus 4.5", "Anthropic", "Premium model combining maximum intelligence with practical performance"},
-	ModelClaudeSonnet:      {ModelClaudeSonnet, "Claude Sonnet 4.5", "Anthropic", "Best balance of intelligence, speed, and cost for most use cases"},
-	ModelClaudeHaiku:       {ModelClaudeHaiku, "Claude Haiku 4.5", "Anthropic", "Fastest Claude with near-frontier intelligence"},
-	ModelClaudeOpus41:      {ModelClaudeOpus41, "Claude Opus 4.1", "Anthropic", "Legacy (still available): premium Opus snapshot"},
-	ModelClaudeOpus4:       {ModelClaudeOpus4, "Claude Opus 4", "Anthropic", "Legacy (still available): Opus 4 snapshot"},
-	ModelClaudeSonnet4:     {ModelClaudeSonnet4, "Claude Sonnet 4", "Anthropic", "Legacy (still available): Sonnet 4 snapshot"},
-	ModelClaudeSonnet37:    {ModelClaudeSonnet37, "Claude Sonnet 3.7", "Anthropic", "Legacy/deprecated: Sonnet 3.7"},
-	ModelClaudeHaiku35:     {ModelClaudeHaiku35, "Claude Haiku 3.5", "Anthropic", "Legacy/deprecated: Haiku 3.5"},
-	ModelClaudeHaiku3:      {ModelClaudeHaiku3, "Claude Haiku 3", "Anthropic", "Legacy: Haiku 3"},
-	ModelClaudeOpus3:       {ModelClaudeOpus3, "Claude Opus 3", "Anthropic", "Legacy/deprecated: Opus 3"},
-	ModelClaudeSonnet3:     {ModelClaudeSonnet3, "Claude Sonnet 3", "Anthropic", "Legacy/retired: Sonnet 3"},
-	ModelGemini3Pro:        {ModelGemini3Pro, "Gemini 3 Pro (Preview)", "Google", "Flagship reasoning (preview), 1M context"},
-	ModelGemini3Flash:      {ModelGemini3Flash, "Gemini 3 Flash (Preview)", "Google", "Fast + strong reasoning (preview), 1M context"},
-	ModelGemini25Pro:       {ModelGemini25Pro, "Gemini 2.5 Pro", "Google", "Advanced reasoning, long context"},
-	ModelGemini25Flash:     {ModelGemini25Flash, "Gemini 2.5 Flash", "Google", "Best price-performance workhorse"},
-	ModelGemini25FlashLite: {ModelGemini25FlashLite, "Gemini 2.5 Flash-Lite", "Google", "Ultra-fast + cost efficient"},
-	ModelGemini2Flash:      {ModelGemini2Flash, "Gemini 2.0 Flash", "Google", "2.0 workhorse (OpenRouter -001)"},
-	ModelGemini2FlashLite:  {ModelGemini2FlashLite, "Gemini 2.0 Flash-Lite", "Google", "2.0 lightweight (OpenRouter -001)"},
-	ModelGrok41Fast:        {ModelGrok41Fast, "Grok 4.1 Fast", "xAI", "2M context, speed optimized"},
-	ModelGrok3:             {ModelGrok3, "Grok 3", "xAI", "Real-time knowledge"},
-	ModelQwen3Next:         {ModelQwen3Next, "Qwen3-Next", "Alibaba", "Long context specialist"},
-	ModelLlama4:            {ModelLlama4, "Llama 4 Maverick", "Meta", "Open weights leader"},
+// Modality is a kind of model input or output.
+type Modality string
+
+const (
+	ModalityText     Modality = "text"
+	ModalityImage    Modality = "image"
+	ModalityAudio    Modality = "audio"
+	ModalityVideo    Modality = "video"
+	ModalityDocument Modality = "document" // Native PDF input
+)
+
+var (
+	inText          = []Modality{ModalityText}
+	inTextImage     = []Modality{ModalityText, ModalityImage}
+	inTextImageDoc  = []Modality{ModalityText, ModalityImage, ModalityDocument}
+	inMultimodal    = []Modality{ModalityText, ModalityImage, ModalityAudio, ModalityVideo, ModalityDocument}
+	inTextAudio     = []Modality{ModalityText, ModalityAudio}
+	outImage        = []Modality{ModalityImage}
+	outAudio        = []Modality{ModalityAudio}
+	outTextAndAudio = []Modality{ModalityText, ModalityAudio}
+)
+
+// ModelCatalog is a registry of known models with their metadata.
+// Useful for UI lists or validation; see Models for queries.
+var ModelCatalog = map[Model]ModelInfo{
+	// OpenAI
+	ModelGPT5: {
+		ID: ModelGPT5, Name: "GPT-5.2", Provider: "OpenAI", Description: "General purpose, long context",
+		ContextWindow: 400_000, MaxOutput: 128_000, InputModalities: inTextImageDoc,
+		Tools: true, JSON: true, Thinking: true, KnowledgeCutoff: "2025-08",
+	},
+	ModelGPT52Pro: {
+		ID: ModelGPT52Pro, Name: "GPT-5.2 Pro", Provider: "OpenAI", Description: "Highest-effort GPT-5.2 for hard problems",
+		ContextWindow: 400_000, MaxOutput: 128_000, InputModalities: inTextImageDoc,
+		Tools: true, JSON: true, Thinking: true, KnowledgeCutoff: "2025-08",
+	},
+	ModelGPT51: {
+		ID: ModelGPT51, Name: "GPT-5.1", Provider: "OpenAI", Description: "Previous GPT-5.x flagship",
+		ContextWindow: 400_000, MaxOutput: 128_000, InputModalities: inTextImageDoc,
+		Tools: true, JSON: true, Thinking: true, KnowledgeCutoff: "2024-09",
+	},
+	ModelGPT5Base: {
+		ID: ModelGPT5Base, Name: "GPT-5", Provider: "OpenAI", Description: "Original GPT-5 release",
+		ContextWindow: 400_000, MaxOutput: 128_000, InputModalities: inTextImageDoc,
+		Tools: true, JSON: true, Thinking: true, KnowledgeCutoff: "2024-09",
+	},
+	ModelGPT5Pro: {
+		ID: ModelGPT5Pro, Name: "GPT-5 Pro", Provider: "OpenAI", Description: "Highest-effort GPT-5",
+		ContextWindow: 400_000, MaxOutput: 272_000, InputModalities: inTextImageDoc,
+		Tools: true, JSON: true, Thinking: true, KnowledgeCutoff: "2024-09",
+	},
+	ModelGPT5Mini: {
+		ID: ModelGPT5Mini, Name: "GPT-5 mini", Provider: "OpenAI", Description: "Faster, cost-efficient version of GPT-5",
+		ContextWindow: 400_000, MaxOutput: 128_000, InputModalities: inTextImageDoc,
+		Tools: true, JSON: true, Thinking: true, KnowledgeCutoff: "2024-05",
+	},
+	ModelGPT5Nano: {
+		ID: ModelGPT5Nano, Name: "GPT-5 nano", Provider: "OpenAI", Description: "Fastest, most cost-efficient version of GPT-5",
+		ContextWindow: 400_000, MaxOutput: 128_000, InputModalities: inTextImageDoc,
+		Tools: true, JSON: true, Thinking: true, KnowledgeCutoff: "2024-05",
+	},
+	ModelGPT5Codex: {
+		ID: ModelGPT5Codex, Name: "GPT-5.1 Codex Max", Provider: "OpenAI", Description: "Most intelligent agentic coding model (Codex)",
+		ContextWindow: 400_000, MaxOutput: 128_000, InputModalities: inTextImage,
+		Tools: true, JSON: true, Thinking: true, KnowledgeCutoff: "2024-09",
+	},
+	ModelGPT51Codex: {
+		ID: ModelGPT51Codex, Name: "GPT-5.1 Codex", Provider: "OpenAI", Description: "Agentic coding model",
+		ContextWindow: 400_000, MaxOutput: 128_000, InputModalities: inTextImage,
+		Tools: true, JSON: true, Thinking: true, KnowledgeCutoff: "2024-09",
+	},
+	ModelGPT51CodexMini: {
+		ID: ModelGPT51CodexMini, Name: "GPT-5.1 Codex mini", Provider: "OpenAI", Description: "Smaller, cheaper Codex",
+		ContextWindow: 400_000, MaxOutput: 128_000, InputModalities: inTextImage,
+		Tools: true, JSON: true, Thinking: true, KnowledgeCutoff: "2024-09",
+	},
+	ModelGPT41: {
+		ID: ModelGPT41, Name: "GPT-4.1", Provider: "OpenAI", Description: "1M context, non-reasoning",
+		ContextWindow: 1_047_576, MaxOutput: 32_768, InputModalities: inTextImageDoc,
+		Tools: true, JSON: true, KnowledgeCutoff: "2024-06",
+	},
+	ModelGPT41Mini: {
+		ID: ModelGPT41Mini, Name: "GPT-4.1 mini", Provider: "OpenAI", Description: "Cost-efficient GPT-4.1",
+		ContextWindow: 1_047_576, MaxOutput: 32_768, InputModalities: inTextImageDoc,
+		Tools: true, JSON: true, KnowledgeCutoff: "2024-06",
+	},
+	ModelGPT41Nano: {
+		ID: ModelGPT41Nano, Name: "GPT-4.1 nano", Provider: "OpenAI", Description: "Fastest GPT-4.1",
+		ContextWindow: 1_047_576, MaxOutput: 32_768, InputModalities: inTextImageDoc,
+		Tools: true, JSON: true, KnowledgeCutoff: "2024-06",
+	},
+	ModelGPT4o: {
+		ID: ModelGPT4o, Name: "GPT-4o", Provider: "OpenAI", Description: "Multimodal flagship",
+		ContextWindow: 128_000, MaxOutput: 16_384, InputModalities: inTextImageDoc,
+		Tools: true, JSON: true, KnowledgeCutoff: "2023-10",
+	},
+	ModelGPT4oMini: {
+		ID: ModelGPT4oMini, Name: "GPT-4o mini", Provider: "OpenAI", Description: "Small, cheap multimodal model",
+		ContextWindow: 128_000, MaxOutput: 16_384, InputModalities: inTextImageDoc,
+		Tools: true, JSON: true, KnowledgeCutoff: "2023-10",
+	},
+	ModelO1: {
+		ID: ModelO1, Name: "o1", Provider: "OpenAI", Description: "First-generation reasoning model",
+		ContextWindow: 200_000, MaxOutput: 100_000, InputModalities: inTextImage,
+		Tools: true, JSON: true, Thinking: true, KnowledgeCutoff: "2023-10",
+	},
+	ModelO1Mini: {
+		ID: ModelO1Mini, Name: "o1-mini", Provider: "OpenAI", Description: "Legacy small reasoning model",
+		ContextWindow: 128_000, MaxOutput: 65_536,
+		Thinking: true, KnowledgeCutoff: "2023-10",
+	},
+	ModelO3: {
+		ID: ModelO3, Name: "o3", Provider: "OpenAI", Description: "Reasoning model for complex tasks",
+		ContextWindow: 200_000, MaxOutput: 100_000, InputModalities: inTextImageDoc,
+		Tools: true, JSON: true, Thinking: true, KnowledgeCutoff: "2024-06",
+	},
+	ModelO3Mini: {
+		ID: ModelO3Mini, Name: "o3-mini", Provider: "OpenAI", Description: "Small text-only reasoning model",
+		ContextWindow: 200_000, MaxOutput: 100_000,
+		Tools: true, JSON: true, Thinking: true, KnowledgeCutoff: "2023-10",
+	},
+	ModelO3Pro: {
+		ID: ModelO3Pro, Name: "o3-pro", Provider: "OpenAI", Description: "o3 with more compute",
+		ContextWindow: 200_000, MaxOutput: 100_000, InputModalities: inTextImageDoc,
+		Tools: true, JSON: true, Thinking: true, KnowledgeCutoff: "2024-06",
+	},
+	ModelO4Mini: {
+		ID: ModelO4Mini, Name: "o4-mini", Provider: "OpenAI", Description: "Fast, cost-efficient reasoning",
+		ContextWindow: 200_000, MaxOutput: 100_000, InputModalities: inTextImageDoc,
+		Tools: true, JSON: true, Thinking: true, KnowledgeCutoff: "2024-06",
+	},
+	ModelGPTOSS120B: {
+		ID: ModelGPTOSS120B, Name: "gpt-oss-120b", Provider: "OpenAI", Description: "Open-weight reasoning model",
+		ContextWindow: 131_072, MaxOutput: 131_072,
+		Tools: true, JSON: true, Thinking: true, KnowledgeCutoff: "2024-06",
+	},
+	ModelGPTOSS20B: {
+		ID: ModelGPTOSS20B, Name: "gpt-oss-20b", Provider: "OpenAI", Description: "Small open-weight reasoning model",
+		ContextWindow: 131_072, MaxOutput: 131_072,
+		Tools: true, JSON: true, Thinking: true, KnowledgeCutoff: "2024-06",
+	},
+	ModelGPTAudio: {
+		ID: ModelGPTAudio, Name: "GPT Audio", Provider: "OpenAI", Description: "Audio in, audio out chat model",
+		ContextWindow: 128_000, MaxOutput: 16_384, InputModalities: inTextAudio, OutputModalities: outTextAndAudio,
+		Tools: true, KnowledgeCutoff: "2023-10",
+	},
+	ModelGPT4oMiniTTS: {
+		ID: ModelGPT4oMiniTTS, Name: "GPT-4o mini TTS", Provider: "OpenAI", Description: "Text-to-speech",
+		OutputModalities: outAudio,
+	},
+	ModelGPT4oTranscribe: {
+		ID: ModelGPT4oTranscribe, Name: "GPT-4o Transcribe", Provider: "OpenAI", Description: "Speech-to-text",
+		InputModalities: []Modality{ModalityAudio},
+	},
+	ModelGPTImage1: {
+		ID: ModelGPTImage1, Name: "GPT Image 1", Provider: "OpenAI", Description: "Image generation and editing",
+		InputModalities: inTextImage, OutputModalities: outImage,
+	},
+
+	// Anthropic
+	ModelClaudeOpus: {
+		ID: ModelClaudeOpus, Name: "Claude Opus 4.5", Provider: "Anthropic", Description: "Premium model combining maximum intelligence with practical performance",
+		ContextWindow: 200_000, MaxOutput: 64_000, InputModalities: inTextImageDoc,
+		Tools: true, JSON: true, Thinking: true, KnowledgeCutoff: "2025-03",
+	},
+	ModelClaudeSonnet: {
+		ID: ModelClaudeSonnet, Name: "Claude Sonnet 4.5", Provider: "Anthropic", Description: "Best balance of intelligence, speed, and cost for most use cases",
+		ContextWindow: 200_000, MaxOutput: 64_000, InputModalities: inTextImageDoc,
+		Tools: true, JSON: true, Thinking: true, KnowledgeCutoff: "2025-01",
+	},
+	ModelClaudeHaiku: {
+		ID: ModelClaudeHaiku, Name: "Claude Haiku 4.5", Provider: "Anthropic", Description: "Fastest Claude with near-frontier intelligence",
+		ContextWindow: 200_000, MaxOutput: 64_000, InputModalities: inTextImageDoc,
+		Tools: true, JSON: true, Thinking: true, KnowledgeCutoff: "2025-02",
+	},
+	ModelClaudeOpus41: {
+		ID: ModelClaudeOpus41, Name: "Claude Opus 4.1", Provider: "Anthropic", Description: "Legacy (still available): premium Opus snapshot",
+		ContextWindow: 200_000, MaxOutput: 32_000, InputModalities: inTextImageDoc,
+		Tools: true, JSON: true, Thinking: true, KnowledgeCutoff: "2025-01",
+	},
+	ModelClaudeOpus4: {
+		ID: ModelClaudeOpus4, Name: "Claude Opus 4", Provider: "Anthropic", Description: "Legacy (still available): Opus 4 snapshot",
+		ContextWindow: 200_000, MaxOutput: 32_000, InputModalities: inTextImageDoc,
+		Tools: true, JSON: true, Thinking: true, KnowledgeCutoff: "2025-01",
+	},
+	ModelClaudeSonnet4: {
+		ID: ModelClaudeSonnet4, Name: "Claude Sonnet 4", Provider: "Anthropic", Description: "Legacy (still available): Sonnet 4 snapshot",
+		ContextWindow: 200_000, MaxOutput: 64_000, InputModalities: inTextImageDoc,
+		Tools: true, JSON: true, Thinking: true, KnowledgeCutoff: "2025-01",
+	},
+	ModelClaudeSonnet37: {
+		ID: ModelClaudeSonnet37, Name: "Claude Sonnet 3.7", Provider: "Anthropic", Description: "Legacy/deprecated: Sonnet 3.7",
+		ContextWindow: 200_000, MaxOutput: 64_000, InputModalities: inTextImageDoc,
+		Tools: true, JSON: true, Thinking: true, KnowledgeCutoff: "2024-10", Deprecation: "2026-02-19",
+	},
+	ModelClaudeHaiku35: {
+		ID: ModelClaudeHaiku35, Name: "Claude Haiku 3.5", Provider: "Anthropic", Description: "Legacy/deprecated: Haiku 3.5",
+		ContextWindow: 200_000, MaxOutput: 8_192, InputModalities: inTextImage,
+		Tools: true, JSON: true, KnowledgeCutoff: "2024-07", Deprecation: "2026-02-19",
+	},
+	ModelClaudeHaiku3: {
+		ID: ModelClaudeHaiku3, Name: "Claude Haiku 3", Provider: "Anthropic", Description: "Legacy: Haiku 3",
+		ContextWindow: 200_000, MaxOutput: 4_096, InputModalities: inTextImage,
+		Tools: true, JSON: true, KnowledgeCutoff: "2023-08",
+	},
+	ModelClaudeOpus3: {
+		ID: ModelClaudeOpus3, Name: "Claude Opus 3", Provider: "Anthropic", Description: "Legacy/deprecated: Opus 3",
+		ContextWindow: 200_000, MaxOutput: 4_096, InputModalities: inTextImage,
+		Tools: true, JSON: true, KnowledgeCutoff: "2023-08", Deprecation: "2026-01-05",
+	},
+	ModelClaudeSonnet3: {
+		ID: ModelClaudeSonnet3, Name: "Claude Sonnet 3", Provider: "Anthropic", Description: "Legacy/retired: Sonnet 3",
+		ContextWindow: 200_000, MaxOutput: 4_096, InputModalities: inTextImage,
+		Tools: true, JSON: true, KnowledgeCutoff: "2023-08", Deprecation: "2025-07-21",
+	},
+
+	// Google
+	ModelGemini3Pro: {
+		ID: ModelGemini3Pro, Name: "Gemini 3 Pro (Preview)", Provider: "Google", Description: "Flagship reasoning (preview), 1M context",
+		ContextWindow: 1_048_576, MaxOutput: 65_536, InputModalities: inMultimodal,
+		Tools: true, JSON: true, Thinking: true, KnowledgeCutoff: "2025-01",
+	},
+	ModelGemini3Flash: {
+		ID: ModelGemini3Flash, Name: "Gemini 3 Flash (Preview)", Provider: "Google", Description: "Fast + strong reasoning (preview), 1M context",
+		ContextWindow: 1_048_576, MaxOutput: 65_536, InputModalities: inMultimodal,
+		Tools: true, JSON: true, Thinking: true, KnowledgeCutoff: "2025-01",
+	},
+	ModelGemini25Pro: {
+		ID: ModelGemini25Pro, Name: "Gemini 2.5 Pro", Provider: "Google", Description: "Advanced reasoning, long context",
+		ContextWindow: 1_048_576, MaxOutput: 65_536, InputModalities: inMultimodal,
+		Tools: true, JSON: true, Thinking: true, KnowledgeCutoff: "2025-01",
+	},
+	ModelGemini25Flash: {
+		ID: ModelGemini25Flash, Name: "Gemini 2.5 Flash", Provider: "Google", Description: "Best price-performance workhorse",
+		ContextWindow: 1_048_576, MaxOutput: 65_536, InputModalities: inMultimodal,
+		Tools: true, JSON: true, Thinking: true, KnowledgeCutoff: "2025-01",
+	},
+	ModelGemini25FlashLite: {
+		ID: ModelGemini25FlashLite, Name: "Gemini 2.5 Flash-Lite", Provider: "Google", Description: "Ultra-fast + cost efficient",
+		ContextWindow: 1_048_576, MaxOutput: 65_536, InputModalities: inMultimodal,
+		Tools: true, JSON: true, Thinking: true, KnowledgeCutoff: "2025-01",
+	},
+	ModelGemini2Flash: {
+		ID: ModelGemini2Flash, Name: "Gemini 2.0 Flash", Provider: "Google", Description: "2.0 workhorse (OpenRouter -001)",
+		ContextWindow: 1_048_576, MaxOutput: 8_192, InputModalities: inMultimodal,
+		Tools: true, JSON: true, KnowledgeCutoff: "2024-08",
+	},
+	ModelGemini2FlashLite: {
+		ID: ModelGemini2FlashLite, Name: "Gemini 2.0 Flash-Lite", Provider: "Google", Description: "2.0 lightweight (OpenRouter -001)",
+		ContextWindow: 1_048_576, MaxOutput: 8_192, InputModalities: inMultimodal,
+		Tools: true, JSON: true, KnowledgeCutoff: "2024-08",
+	},
+
+	// Others (via OpenRouter)
+	ModelGrok41Fast: {
+		ID: ModelGrok41Fast, Name: "Grok 4.1 Fast", Provider: "xAI", Description: "2M context, speed optimized",
+		ContextWindow: 2_000_000, MaxOutput: 30_000, InputModalities: inTextImage,
+		Tools: true, JSON: true, Thinking: true,
+	},
+	ModelGrok3: {
+		ID: ModelGrok3, Name: "Grok 3", Provider: "xAI", Description: "Real-time knowledge",
+		ContextWindow: 131_072,
+		Tools:         true, JSON: true,
+	},
+	ModelGrok3Mini: {
+		ID: ModelGrok3Mini, Name: "Grok 3 Mini", Provider: "xAI", Description: "Small reasoning model",
+		ContextWindow: 131_072,
+		Tools:         true, JSON: true, Thinking: true,
+	},
+	ModelQwen3Next: {
+		ID: ModelQwen3Next, Name: "Qwen3-Next", Provider: "Alibaba", Description: "Long context specialist",
+		ContextWindow: 262_144,
+		Tools:         true, JSON: true, Thinking: true,
+	},
+	ModelLlama4: {
+		ID: ModelLlama4, Name: "Llama 4 Maverick", Provider: "Meta", Description: "Open weights leader",
+		ContextWindow: 1_048_576, MaxOutput: 16_384, InputModalities: inTextImage,
+		Tools: true, JSON: true,
+	},
+	ModelMistralLarge: {
+		ID: ModelMistralLarge, Name: "Mistral Large", Provider: "Mistral", Description: "Mistral's flagship model",
+		ContextWindow: 128_000,
+		Tools:         true, JSON: true,
+	},
 }
 
 // String returns the provider-qualified model ID string.
@@ -1,6 +1,7 @@
 package ai
 
 import (
+	"errors"
 	"testing"
 )
 
@@ -56,7 +57,7 @@ func TestModelsRegistry(t *testing.T) {
 	}
 
 	for _, model := range expectedModels {
-		info, exists := Models[model]
+		info, exists := ModelCatalog[model]
 		if !exists {
 			t.Errorf("model %s should be in registry", model)
 			continue
@@ -74,7 +75,7 @@ func TestModelsRegistry(t *testing.T) {
 }
 
 func TestModelInfoFields(t *testing.T) {
-	info := Models[ModelGPT5]
+	info := ModelCatalog[ModelGPT5]
 
 	if info.ID != ModelGPT5 {
 		t.Error("ID mismatch")
@@ -145,8 +146,101 @@ func TestCustomModel(t *testing.T) {
 	}
 
 	// Custom model won't be in registry
-	_, exists := Models[custom]
+	_, exists := ModelCatalog[custom]
 	if exists {
 		t.Error("custom model should not be in registry")
 	}
 }
+
+func TestModelsQuery(t *testing.T) {
+	vision := Models().WithVision().WithTools()
+	if vision.Count() == 0 {
+		t.Fatal("expected vision + tools models in catalog")
+	}
+	for _, m := range vision.List() {
+		if !m.Accepts(ModalityImage) || !m.Tools {
+			t.Fatalf("%s should not match WithVision().WithTools()", m.ID)
+		}
+	}
+	for _, id := range Models().WithVision().IDs() {
+		if id == ModelO3Mini {
+			t.Fatal("o3-mini is text-only")
+		}
+	}
+
+	cheap := Models().MaxCost(1)
+	for _, m := range cheap.List() {
+		p, _ := m.Pricing()
+		if (p.InputPerMillion+p.OutputPerMillion)/2 > 1 {
+			t.Fatalf("%s exceeds MaxCost(1)", m.ID)
+		}
+	}
+	if best, ok := Models().FromProvider("openai").WithVision().Cheapest(); !ok || best.Provider != "OpenAI" {
+		t.Fatalf("unexpected cheapest OpenAI vision model: %+v", best)
+	}
+
+	if Models().Active().Count() >= Models().Count() {
+		t.Fatal("expected retired models to be filtered by Active()")
+	}
+}
+
+func TestLookupModelBareID(t *testing.T) {
+	info, ok := LookupModel("gpt-4o")
+	if !ok || info.ID != ModelGPT4o {
+		t.Fatalf("expected bare ID to resolve to %s, got %+v", ModelGPT4o, info)
+	}
+	if ContextWindow(Model("my-provider/custom-model")) != 0 {
+		t.Fatal("unknown models should have no context window")
+	}
+}
+
+func TestSendWithMeta_SkipsModelsLackingFeatures(t *testing.T) {
+	defer withTestGlobals(t)()
+
+	sp := &stubProvider{name: "stub"}
+	client := &Client{provider: sp, providerType: ProviderOpenRouter}
+
+	meta := client.New(ModelO3Mini).
+		ImageBase64("aGVsbG8=", "image/png").
+		Fallback(ModelGPT4o).
+		User("describe").
+		SendWithMeta()
+	if meta.Error != nil || meta.Model != ModelGPT4o {
+		t.Fatalf("expected fallback to vision model, got %v on %s", meta.Error, meta.Model)
+	}
+	if reqs := sp.Requests(); len(reqs) != 1 || reqs[0].Model != string(ModelGPT4o) {
+		t.Fatalf("expected only the vision model to be called, got %d requests", len(reqs))
+	}
+
+	meta = client.New(ModelO1Mini).Tool("lookup", "Look up", Params().Build()).User("hi").SendWithMeta()
+	var featErr *UnsupportedFeatureError
+	if !errors.As(meta.Error, &featErr) || featErr.Feature != "tools" {
+		t.Fatalf("expected unsupported tools error, got %v", meta.Error)
+	}
+}
+
+func TestModelSupport_DocumentsAndStreaming(t *testing.T) {
+	defer withTestGlobals(t)()
+
+	doc := []Message{{Role: "user", Content: []ContentPart{{Type: "document", Document: &DocumentRef{Data: "eA==", MimeType: "application/pdf"}}}}}
+	var featErr *UnsupportedFeatureError
+	if err := (&Builder{}).checkModelSupport(ModelLlama4, doc); !errors.As(err, &featErr) || featErr.Feature != "document input" {
+		t.Fatalf("expected unsupported document error, got %v", err)
+	}
+	if err := (&Builder{}).checkModelSupport(ModelGPT5, doc); err != nil {
+		t.Fatalf("expected documents to be allowed, got %v", err)
+	}
+
+	sp := &stubProvider{name: "stub", caps: ProviderCapabilities{Streaming: true}}
+	client := &Client{provider: sp, providerType: ProviderOpenRouter}
+	_, err := client.New(ModelO3Mini).ImageBase64("aGVsbG8=", "image/png").User("describe").StreamResponse(func(string) {})
+	if !errors.As(err, &featErr) || featErr.Feature != "image input" {
+		t.Fatalf("expected unsupported image error when streaming, got %v", err)
+	}
+	if _, err := client.New(ModelO3Mini).ImageBase64("aGVsbG8=", "image/png").User("describe").StreamWithMeta(func(string) {}); !errors.As(err, &featErr) {
+		t.Fatalf("expected unsupported image error from StreamWithMeta, got %v", err)
+	}
+	if len(sp.Requests()) != 0 {
+		t.Fatal("expected no request to be sent")
+	}
+}
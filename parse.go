@@ -180,7 +180,7 @@ Important:
 
 		if attempt < config.MaxRetries {
 			messages = appendCorrectionMessages(messages, meta.Content, parseErr)
-			if Debug {
+			if b.currentSettings().debug {
 				fmt.Printf("%s Parse attempt %d/%d failed: %v\n",
 					colorYellow("↻"), attempt+1, config.MaxRetries+1, parseErr)
 			}
@@ -308,7 +308,7 @@ Important:
 			// Add the failed response and correction request
 			messages = appendCorrectionMessages(messages, meta.Content, parseErr)
 
-			if Debug {
+			if b.currentSettings().debug {
 				fmt.Printf("%s Parse attempt %d/%d failed: %v\n",
 					colorYellow("↻"), attempt+1, config.MaxRetries+1, parseErr)
 			}
@@ -0,0 +1,856 @@
+package ai
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// PDF Text Extraction (best effort, pure Go)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// This is a small reader that understands enough of the PDF format to pull
+// text out of typical documents: indirect objects, object streams, Flate
+// compression, the page tree, and ToUnicode CMaps. Scanned PDFs (images only)
+// and encrypted PDFs yield no text.
+
+// errPDFEncrypted is returned for password-protected PDFs.
+var errPDFEncrypted = errors.New("encrypted PDFs are not supported")
+
+// Limits that keep hostile files from exhausting the stack or memory.
+const (
+	pdfMaxNesting  = 256      // nested arrays and dictionaries
+	pdfMaxInflated = 64 << 20 // decompressed stream bytes per document
+)
+
+var (
+	errPDFTooDeep  = fmt.Errorf("PDF objects nested deeper than %d levels", pdfMaxNesting)
+	errPDFTooLarge = fmt.Errorf("PDF streams decompress to more than %d MB", pdfMaxInflated>>20)
+)
+
+type pdfName string
+type pdfKeyword string
+
+type pdfRef struct {
+	num int
+}
+
+type pdfObject struct {
+	value  any
+	stream []byte // raw (still encoded) stream data, nil if not a stream
+}
+
+type pdfFile struct {
+	objects  map[int]*pdfObject
+	cmaps    map[int]*pdfCMap
+	inflated int   // decompressed bytes so far
+	err      error // first limit hit; extraction stops
+}
+
+// fail records the first error that stops extraction.
+func (f *pdfFile) fail(err error) {
+	if f.err == nil {
+		f.err = err
+	}
+}
+
+// extractPDFPages returns the text of each page in document order.
+func extractPDFPages(data []byte) ([]string, error) {
+	if !bytes.HasPrefix(bytes.TrimLeft(data, "\x00\t\r\n "), []byte("%PDF")) {
+		return nil, fmt.Errorf("not a PDF file")
+	}
+	if bytes.Contains(data, []byte("/Encrypt")) {
+		return nil, errPDFEncrypted
+	}
+
+	f := &pdfFile{objects: map[int]*pdfObject{}, cmaps: map[int]*pdfCMap{}}
+	f.scanObjects(data)
+	f.expandObjectStreams()
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	pages := f.pages()
+	out := make([]string, 0, len(pages))
+	for _, page := range pages {
+		out = append(out, f.pageText(page))
+		if f.err != nil {
+			return nil, f.err
+		}
+	}
+	return out, nil
+}
+
+// ───────────────────────────────────────────────────────────────────────────
+// Object scanning
+// ───────────────────────────────────────────────────────────────────────────
+
+var pdfObjHeader = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+
+// scanObjects finds every "n g obj ... endobj" in the file. Scanning instead of
+// reading the xref table keeps this tolerant of damaged or incrementally
+// updated files; later definitions win, as they would in an update.
+func (f *pdfFile) scanObjects(data []byte) {
+	for _, m := range pdfObjHeader.FindAllSubmatchIndex(data, -1) {
+		num, err := strconv.Atoi(string(data[m[2]:m[3]]))
+		if err != nil {
+			continue
+		}
+		lex := &pdfLexer{data: data, pos: m[1]}
+		value := lex.parseValue()
+		if lex.err != nil {
+			f.fail(lex.err)
+			return
+		}
+		obj := &pdfObject{value: value}
+
+		if tok := lex.peekKeyword(); tok == "stream" {
+			lex.nextToken()
+			start := lex.pos
+			if start < len(data) && data[start] == '\r' {
+				start++
+			}
+			if start < len(data) && data[start] == '\n' {
+				start++
+			}
+			obj.stream = streamBytes(data, start, value)
+		}
+		f.objects[num] = obj
+	}
+}
+
+// streamBytes returns the raw stream content starting at start.
+func streamBytes(data []byte, start int, dict any) []byte {
+	if d, ok := dict.(map[string]any); ok {
+		if n, ok := d["Length"].(float64); ok && n >= 0 && n <= float64(len(data)-start) {
+			end := start + int(n)
+			if bytes.HasPrefix(bytes.TrimLeft(data[end:], "\r\n \t"), []byte("endstream")) {
+				return data[start:end]
+			}
+		}
+	}
+	// Indirect or wrong /Length: fall back to searching for the terminator.
+	end := bytes.Index(data[start:], []byte("endstream"))
+	if end < 0 {
+		return data[start:]
+	}
+	return bytes.TrimRight(data[start:start+end], "\r\n")
+}
+
+// expandObjectStreams unpacks objects stored inside /Type /ObjStm containers.
+func (f *pdfFile) expandObjectStreams() {
+	for _, obj := range f.objects {
+		dict, ok := obj.value.(map[string]any)
+		if !ok || dict["Type"] != pdfName("ObjStm") {
+			continue
+		}
+		data := f.decodeStream(obj)
+		if f.err != nil {
+			return
+		}
+		n, _ := dict["N"].(float64)
+		first, _ := dict["First"].(float64)
+		// Skip malformed headers instead of slicing with them; each entry
+		// needs at least four header bytes ("1 0 "), which bounds /N.
+		if data == nil || first < 0 || first > float64(len(data)) || n < 0 || n > first/2 {
+			continue
+		}
+
+		header := &pdfLexer{data: data[:int(first)]}
+		for i := 0; i < int(n); i++ {
+			num, ok1 := header.parseValue().(float64)
+			off, ok2 := header.parseValue().(float64)
+			if !ok1 || !ok2 {
+				break
+			}
+			if off < 0 || off >= float64(len(data))-first {
+				continue
+			}
+			// Objects defined directly in the file take precedence.
+			if _, exists := f.objects[int(num)]; exists {
+				continue
+			}
+			lex := &pdfLexer{data: data, pos: int(first) + int(off)}
+			f.objects[int(num)] = &pdfObject{value: lex.parseValue()}
+			if lex.err != nil {
+				f.fail(lex.err)
+				return
+			}
+		}
+	}
+}
+
+// resolve follows indirect references.
+func (f *pdfFile) resolve(v any) any {
+	for i := 0; i < 32; i++ {
+		ref, ok := v.(pdfRef)
+		if !ok {
+			return v
+		}
+		obj := f.objects[ref.num]
+		if obj == nil {
+			return nil
+		}
+		v = obj.value
+	}
+	return nil
+}
+
+func (f *pdfFile) dict(v any) map[string]any {
+	d, _ := f.resolve(v).(map[string]any)
+	return d
+}
+
+// decodeStream returns decompressed stream data, or nil for unsupported filters.
+func (f *pdfFile) decodeStream(obj *pdfObject) []byte {
+	if obj == nil || obj.stream == nil || f.err != nil {
+		return nil
+	}
+	dict, _ := obj.value.(map[string]any)
+
+	var filters []any
+	switch v := f.resolve(dict["Filter"]).(type) {
+	case pdfName:
+		filters = []any{v}
+	case []any:
+		filters = v
+	}
+
+	data := obj.stream
+	for _, filter := range filters {
+		switch f.resolve(filter) {
+		case pdfName("FlateDecode"), pdfName("Fl"):
+			r, err := zlib.NewReader(bytes.NewReader(data))
+			if err != nil {
+				return nil
+			}
+			// Truncated streams are common; keep whatever decoded cleanly.
+			out, _ := io.ReadAll(io.LimitReader(r, int64(pdfMaxInflated-f.inflated)+1))
+			if f.inflated += len(out); f.inflated > pdfMaxInflated {
+				f.fail(errPDFTooLarge)
+				return nil
+			}
+			data = out
+		default:
+			return nil
+		}
+	}
+	return data
+}
+
+// ───────────────────────────────────────────────────────────────────────────
+// Page tree
+// ───────────────────────────────────────────────────────────────────────────
+
+type pdfPage struct {
+	dict  map[string]any
+	fonts map[string]any // font resource name -> font dict or ref
+}
+
+// pages walks the page tree from the catalog, falling back to every /Page object.
+func (f *pdfFile) pages() []pdfPage {
+	var pages []pdfPage
+	for _, obj := range f.objects {
+		d, ok := obj.value.(map[string]any)
+		if ok && d["Type"] == pdfName("Catalog") {
+			f.walkPages(d["Pages"], nil, &pages, 0)
+			if len(pages) > 0 {
+				return pages
+			}
+		}
+	}
+
+	// No usable catalog: take /Page objects in object-number order.
+	nums := make([]int, 0, len(f.objects))
+	for num, obj := range f.objects {
+		if d, ok := obj.value.(map[string]any); ok && d["Type"] == pdfName("Page") {
+			nums = append(nums, num)
+		}
+	}
+	sort.Ints(nums)
+	for _, num := range nums {
+		d := f.objects[num].value.(map[string]any)
+		pages = append(pages, pdfPage{dict: d, fonts: f.fontResources(d["Resources"])})
+	}
+	return pages
+}
+
+func (f *pdfFile) walkPages(node any, inherited any, pages *[]pdfPage, depth int) {
+	d := f.dict(node)
+	if d == nil || depth > 64 {
+		return
+	}
+	resources := inherited
+	if r, ok := d["Resources"]; ok {
+		resources = r
+	}
+
+	if d["Type"] == pdfName("Page") || d["Kids"] == nil {
+		*pages = append(*pages, pdfPage{dict: d, fonts: f.fontResources(resources)})
+		return
+	}
+	kids, _ := f.resolve(d["Kids"]).([]any)
+	for _, kid := range kids {
+		f.walkPages(kid, resources, pages, depth+1)
+	}
+}
+
+func (f *pdfFile) fontResources(resources any) map[string]any {
+	res := f.dict(resources)
+	if res == nil {
+		return nil
+	}
+	return f.dict(res["Font"])
+}
+
+// pageText concatenates and interprets a page's content streams.
+func (f *pdfFile) pageText(page pdfPage) string {
+	var content []byte
+	switch c := page.dict["Contents"].(type) {
+	case pdfRef:
+		if arr, ok := f.resolve(c).([]any); ok {
+			for _, item := range arr {
+				content = append(content, f.streamFor(item)...)
+				content = append(content, '\n')
+			}
+		} else {
+			content = f.streamFor(c)
+		}
+	case []any:
+		for _, item := range c {
+			content = append(content, f.streamFor(item)...)
+			content = append(content, '\n')
+		}
+	}
+	return f.interpretContent(content, page.fonts)
+}
+
+func (f *pdfFile) streamFor(v any) []byte {
+	ref, ok := v.(pdfRef)
+	if !ok {
+		return nil
+	}
+	return f.decodeStream(f.objects[ref.num])
+}
+
+// ───────────────────────────────────────────────────────────────────────────
+// Content stream interpretation
+// ───────────────────────────────────────────────────────────────────────────
+
+func (f *pdfFile) interpretContent(content []byte, fonts map[string]any) string {
+	var sb strings.Builder
+	var operands []any
+	var cmap *pdfCMap
+	lastY := 0.0
+
+	newline := func() {
+		if sb.Len() > 0 && !strings.HasSuffix(sb.String(), "\n") {
+			sb.WriteByte('\n')
+		}
+	}
+	write := func(s []byte) {
+		sb.WriteString(cmap.decode(s))
+	}
+
+	lex := &pdfLexer{data: content}
+	for {
+		v, ok := lex.nextValue()
+		if !ok {
+			break
+		}
+		if lex.err != nil {
+			f.fail(lex.err)
+			break
+		}
+		op, isOp := v.(pdfKeyword)
+		if !isOp {
+			operands = append(operands, v)
+			continue
+		}
+
+		switch op {
+		case "Tf":
+			cmap = nil
+			if len(operands) >= 2 {
+				if name, ok := operands[0].(pdfName); ok && fonts != nil {
+					cmap = f.fontCMap(fonts[string(name)])
+				}
+			}
+		case "Tj":
+			if s, ok := lastOperand(operands).([]byte); ok {
+				write(s)
+			}
+		case "'":
+			newline()
+			if s, ok := lastOperand(operands).([]byte); ok {
+				write(s)
+			}
+		case "\"":
+			newline()
+			if s, ok := lastOperand(operands).([]byte); ok {
+				write(s)
+			}
+		case "TJ":
+			arr, _ := lastOperand(operands).([]any)
+			for _, item := range arr {
+				switch x := item.(type) {
+				case []byte:
+					write(x)
+				case float64:
+					// Large negative kerning is a word gap.
+					if x < -200 && !strings.HasSuffix(sb.String(), " ") {
+						sb.WriteByte(' ')
+					}
+				}
+			}
+		case "Td", "TD":
+			if len(operands) >= 2 {
+				if ty, ok := operands[1].(float64); ok && ty != 0 {
+					newline()
+				} else if sb.Len() > 0 && !strings.HasSuffix(sb.String(), " ") && !strings.HasSuffix(sb.String(), "\n") {
+					sb.WriteByte(' ')
+				}
+			}
+		case "Tm":
+			if len(operands) >= 6 {
+				if y, ok := operands[5].(float64); ok {
+					if y != lastY {
+						newline()
+					}
+					lastY = y
+				}
+			}
+		case "T*", "ET":
+			newline()
+		case "BI":
+			lex.skipInlineImage()
+		}
+		operands = operands[:0]
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+func lastOperand(operands []any) any {
+	if len(operands) == 0 {
+		return nil
+	}
+	return operands[len(operands)-1]
+}
+
+// ───────────────────────────────────────────────────────────────────────────
+// Fonts and ToUnicode CMaps
+// ───────────────────────────────────────────────────────────────────────────
+
+type pdfCMap struct {
+	width int // code width in bytes (1 or 2)
+	codes map[uint32]string
+}
+
+// fontCMap returns the ToUnicode mapping for a font, or nil to use PDFDocEncoding.
+func (f *pdfFile) fontCMap(font any) *pdfCMap {
+	fd := f.dict(font)
+	if fd == nil {
+		return nil
+	}
+	ref, ok := fd["ToUnicode"].(pdfRef)
+	if !ok {
+		return nil
+	}
+	if cm, ok := f.cmaps[ref.num]; ok {
+		return cm
+	}
+	cm := parseCMap(f.decodeStream(f.objects[ref.num]))
+	f.cmaps[ref.num] = cm
+	return cm
+}
+
+var (
+	cmapBFChar  = regexp.MustCompile(`(?s)beginbfchar(.*?)endbfchar`)
+	cmapBFRange = regexp.MustCompile(`(?s)beginbfrange(.*?)endbfrange`)
+	cmapHex     = regexp.MustCompile(`<([0-9A-Fa-f\s]*)>|\[([^\]]*)\]`)
+)
+
+func parseCMap(data []byte) *pdfCMap {
+	if data == nil {
+		return nil
+	}
+	cm := &pdfCMap{width: 1, codes: map[uint32]string{}}
+
+	for _, block := range cmapBFChar.FindAllSubmatch(data, -1) {
+		toks := cmapHex.FindAllSubmatch(block[1], -1)
+		for i := 0; i+1 < len(toks); i += 2 {
+			src := hexBytes(toks[i][1])
+			cm.noteWidth(src)
+			cm.codes[codeValue(src)] = utf16String(hexBytes(toks[i+1][1]))
+		}
+	}
+
+	for _, block := range cmapBFRange.FindAllSubmatch(data, -1) {
+		toks := cmapHex.FindAllSubmatch(block[1], -1)
+		for i := 0; i+2 < len(toks); i += 3 {
+			lo, hi := hexBytes(toks[i][1]), hexBytes(toks[i+1][1])
+			cm.noteWidth(lo)
+			start, end := codeValue(lo), codeValue(hi)
+			if end < start || end-start > 0xFFFF {
+				continue
+			}
+			if toks[i+2][2] != nil {
+				// Array form: one destination per code.
+				dsts := cmapHex.FindAllSubmatch(toks[i+2][2], -1)
+				for j, dst := range dsts {
+					cm.codes[start+uint32(j)] = utf16String(hexBytes(dst[1]))
+				}
+				continue
+			}
+			dst := []rune(utf16String(hexBytes(toks[i+2][1])))
+			if len(dst) == 0 {
+				continue
+			}
+			for code := start; code <= end; code++ {
+				r := append([]rune(nil), dst...)
+				r[len(r)-1] += rune(code - start)
+				cm.codes[code] = string(r)
+			}
+		}
+	}
+	return cm
+}
+
+func (cm *pdfCMap) noteWidth(src []byte) {
+	if len(src) > cm.width {
+		cm.width = len(src)
+	}
+}
+
+// decode maps string bytes to text using the CMap, or PDFDocEncoding when nil.
+func (cm *pdfCMap) decode(s []byte) string {
+	if cm == nil {
+		if len(s) >= 2 && s[0] == 0xFE && s[1] == 0xFF {
+			return utf16String(s[2:])
+		}
+		r := make([]rune, len(s))
+		for i, c := range s {
+			r[i] = rune(c)
+		}
+		return string(r)
+	}
+
+	var sb strings.Builder
+	for i := 0; i+cm.width <= len(s); i += cm.width {
+		code := codeValue(s[i : i+cm.width])
+		if text, ok := cm.codes[code]; ok {
+			sb.WriteString(text)
+		}
+	}
+	return sb.String()
+}
+
+func codeValue(b []byte) uint32 {
+	var v uint32
+	for _, c := range b {
+		v = v<<8 | uint32(c)
+	}
+	return v
+}
+
+func hexBytes(b []byte) []byte {
+	clean := bytes.Map(func(r rune) rune {
+		if r == ' ' || r == '\n' || r == '\r' || r == '\t' {
+			return -1
+		}
+		return r
+	}, b)
+	if len(clean)%2 == 1 {
+		clean = append(clean, '0')
+	}
+	out := make([]byte, hex.DecodedLen(len(clean)))
+	n, _ := hex.Decode(out, clean)
+	return out[:n]
+}
+
+func utf16String(b []byte) string {
+	if len(b)%2 == 1 {
+		b = append(b, 0)
+	}
+	u := make([]uint16, len(b)/2)
+	for i := range u {
+		u[i] = uint16(b[2*i])<<8 | uint16(b[2*i+1])
+	}
+	return string(utf16.Decode(u))
+}
+
+// ───────────────────────────────────────────────────────────────────────────
+// Lexer
+// ───────────────────────────────────────────────────────────────────────────
+
+type pdfLexer struct {
+	data  []byte
+	pos   int
+	depth int   // open arrays and dictionaries
+	err   error // set when nesting exceeds pdfMaxNesting
+}
+
+func isPDFWhitespace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\r' || c == '\t' || c == '\f' || c == 0
+}
+
+func isPDFDelimiter(c byte) bool {
+	return strings.IndexByte("()<>[]{}/%", c) >= 0
+}
+
+func (l *pdfLexer) skipSpace() {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if isPDFWhitespace(c) {
+			l.pos++
+		} else if c == '%' {
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+		} else {
+			return
+		}
+	}
+}
+
+// nextToken returns the next raw token: a delimiter, string, name, or regular word.
+func (l *pdfLexer) nextToken() any {
+	l.skipSpace()
+	if l.pos >= len(l.data) {
+		return nil
+	}
+	c := l.data[l.pos]
+	switch {
+	case c == '(':
+		return l.literalString()
+	case c == '<' && l.pos+1 < len(l.data) && l.data[l.pos+1] == '<':
+		l.pos += 2
+		return pdfKeyword("<<")
+	case c == '>' && l.pos+1 < len(l.data) && l.data[l.pos+1] == '>':
+		l.pos += 2
+		return pdfKeyword(">>")
+	case c == '<':
+		end := bytes.IndexByte(l.data[l.pos:], '>')
+		if end < 0 {
+			l.pos = len(l.data)
+			return []byte{}
+		}
+		s := hexBytes(l.data[l.pos+1 : l.pos+end])
+		l.pos += end + 1
+		return s
+	case c == '[' || c == ']' || c == '{' || c == '}':
+		l.pos++
+		return pdfKeyword(string(c))
+	case c == '/':
+		l.pos++
+		start := l.pos
+		for l.pos < len(l.data) && !isPDFWhitespace(l.data[l.pos]) && !isPDFDelimiter(l.data[l.pos]) {
+			l.pos++
+		}
+		return pdfName(decodePDFName(l.data[start:l.pos]))
+	case c == ')' || c == '>':
+		l.pos++
+		return l.nextToken()
+	}
+
+	start := l.pos
+	for l.pos < len(l.data) && !isPDFWhitespace(l.data[l.pos]) && !isPDFDelimiter(l.data[l.pos]) {
+		l.pos++
+	}
+	word := string(l.data[start:l.pos])
+	if n, err := strconv.ParseFloat(word, 64); err == nil {
+		return n
+	}
+	switch word {
+	case "true":
+		return true
+	case "false":
+		return false
+	case "null":
+		return nil
+	}
+	return pdfKeyword(word)
+}
+
+func decodePDFName(b []byte) string {
+	if bytes.IndexByte(b, '#') < 0 {
+		return string(b)
+	}
+	var out []byte
+	for i := 0; i < len(b); i++ {
+		if b[i] == '#' && i+2 < len(b) {
+			if v, err := strconv.ParseUint(string(b[i+1:i+3]), 16, 8); err == nil {
+				out = append(out, byte(v))
+				i += 2
+				continue
+			}
+		}
+		out = append(out, b[i])
+	}
+	return string(out)
+}
+
+func (l *pdfLexer) literalString() []byte {
+	l.pos++ // (
+	var out []byte
+	depth := 1
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return out
+			}
+		case '\\':
+			if l.pos >= len(l.data) {
+				return out
+			}
+			e := l.data[l.pos]
+			l.pos++
+			switch e {
+			case 'n':
+				out = append(out, '\n')
+			case 'r':
+				out = append(out, '\r')
+			case 't':
+				out = append(out, '\t')
+			case 'b':
+				out = append(out, '\b')
+			case 'f':
+				out = append(out, '\f')
+			case '\r':
+				if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+					l.pos++
+				}
+			case '\n':
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for k := 0; k < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; k++ {
+						v = v*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					out = append(out, byte(v))
+				} else {
+					out = append(out, e)
+				}
+			}
+			continue
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+// nextValue returns the next complete value (arrays and dicts assembled).
+func (l *pdfLexer) nextValue() (any, bool) {
+	l.skipSpace()
+	if l.pos >= len(l.data) {
+		return nil, false
+	}
+	return l.parseValue(), true
+}
+
+// parseValue parses one object, resolving "n g R" into a pdfRef. Nesting
+// beyond pdfMaxNesting sets l.err and consumes the rest of the input.
+func (l *pdfLexer) parseValue() any {
+	tok := l.nextToken()
+	switch t := tok.(type) {
+	case pdfKeyword:
+		if t == "[" || t == "<<" {
+			if l.depth >= pdfMaxNesting {
+				l.err = errPDFTooDeep
+				l.pos = len(l.data)
+				return nil
+			}
+			l.depth++
+			defer func() { l.depth-- }()
+		}
+		switch t {
+		case "[":
+			var arr []any
+			for {
+				l.skipSpace()
+				if l.pos >= len(l.data) {
+					return arr
+				}
+				if l.data[l.pos] == ']' {
+					l.pos++
+					return arr
+				}
+				arr = append(arr, l.parseValue())
+			}
+		case "<<":
+			dict := map[string]any{}
+			for {
+				l.skipSpace()
+				if l.pos >= len(l.data) {
+					return dict
+				}
+				if bytes.HasPrefix(l.data[l.pos:], []byte(">>")) {
+					l.pos += 2
+					return dict
+				}
+				key, ok := l.nextToken().(pdfName)
+				if !ok {
+					continue
+				}
+				dict[string(key)] = l.parseValue()
+			}
+		}
+		return t
+	case float64:
+		// Look ahead for "gen R".
+		save := l.pos
+		if gen, ok := l.nextToken().(float64); ok && gen >= 0 {
+			if kw, ok := l.nextToken().(pdfKeyword); ok && kw == "R" {
+				return pdfRef{num: int(t)}
+			}
+		}
+		l.pos = save
+		return t
+	}
+	return tok
+}
+
+func (l *pdfLexer) peekKeyword() pdfKeyword {
+	save := l.pos
+	tok := l.nextToken()
+	l.pos = save
+	kw, _ := tok.(pdfKeyword)
+	return kw
+}
+
+// skipInlineImage skips binary inline image data up to "EI".
+func (l *pdfLexer) skipInlineImage() {
+	idx := bytes.Index(l.data[l.pos:], []byte("ID"))
+	if idx < 0 {
+		l.pos = len(l.data)
+		return
+	}
+	l.pos += idx + 2
+	for l.pos < len(l.data) {
+		end := bytes.Index(l.data[l.pos:], []byte("EI"))
+		if end < 0 {
+			l.pos = len(l.data)
+			return
+		}
+		l.pos += end + 2
+		if isPDFWhitespace(l.data[l.pos-3]) && (l.pos >= len(l.data) || isPDFWhitespace(l.data[l.pos])) {
+			return
+		}
+	}
+}
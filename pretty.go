@@ -2,7 +2,10 @@ package ai
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"strings"
+	"time"
 )
 
 // ANSI color codes
@@ -27,10 +30,15 @@ func colorDim(s string) string     { return dim + s + reset }
 
 // printDebugRequest prints the outgoing request
 func printDebugRequest(model Model, messages []Message) {
-	fmt.Println()
-	fmt.Println(colorYellow("┌─────────────────────────────────────────────────────────────"))
-	fmt.Printf("%s DEBUG REQUEST → %s\n", colorYellow("│"), colorCyan(string(model)))
-	fmt.Println(colorYellow("├─────────────────────────────────────────────────────────────"))
+	writeDebugRequest(os.Stdout, string(model), loggedMessages(messages, LogRedaction))
+}
+
+// writeDebugRequest writes the request box of Debug output
+func writeDebugRequest(w io.Writer, model string, messages []loggedMessage) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, colorYellow("┌─────────────────────────────────────────────────────────────"))
+	fmt.Fprintf(w, "%s DEBUG REQUEST → %s\n", colorYellow("│"), colorCyan(model))
+	fmt.Fprintln(w, colorYellow("├─────────────────────────────────────────────────────────────"))
 
 	for _, m := range messages {
 		var role string
@@ -43,63 +51,73 @@ func printDebugRequest(model Model, messages []Message) {
 			role = colorDim(m.Role)
 		}
 
-		fmt.Printf("%s [%s]\n", colorYellow("│"), role)
+		fmt.Fprintf(w, "%s [%s]\n", colorYellow("│"), role)
 
 		// Indent content
-		var contentStr string
-		if str, ok := m.Content.(string); ok {
-			contentStr = str
-		}
-		lines := strings.Split(contentStr, "\n")
+		lines := strings.Split(m.Content, "\n")
 		for _, line := range lines {
 			if len(line) > 80 {
-				fmt.Printf("%s   %s...\n", colorYellow("│"), line[:77])
+				fmt.Fprintf(w, "%s   %s...\n", colorYellow("│"), line[:77])
 			} else {
-				fmt.Printf("%s   %s\n", colorYellow("│"), line)
+				fmt.Fprintf(w, "%s   %s\n", colorYellow("│"), line)
 			}
 		}
 	}
 
-	fmt.Println(colorYellow("└─────────────────────────────────────────────────────────────"))
+	fmt.Fprintln(w, colorYellow("└─────────────────────────────────────────────────────────────"))
+}
+
+// writeDebugImages writes the estimated token cost of each attached image
+func writeDebugImages(w io.Writer, estimates []ImageEstimate) {
+	total := 0
+	for _, est := range estimates {
+		size := "unknown size"
+		if est.Width > 0 {
+			size = fmt.Sprintf("%dx%d", est.Width, est.Height)
+		}
+		fmt.Fprintf(w, "%s image %d: %s, detail=%s, ~%d tokens\n", colorDim("│"), est.Index+1, size, est.Detail, est.Tokens)
+		total += est.Tokens
+	}
+	fmt.Fprintf(w, "%s images total: ~%d tokens\n", colorDim("│"), total)
 }
 
-// printDebugResponse prints the incoming response
-func printDebugResponse(content string, resp *Response) {
-	fmt.Println()
-	fmt.Println(colorGreen("┌─────────────────────────────────────────────────────────────"))
-	fmt.Printf("%s DEBUG RESPONSE\n", colorGreen("│"))
-	fmt.Println(colorGreen("├─────────────────────────────────────────────────────────────"))
+// writeDebugResponse writes the response box of Debug output
+func writeDebugResponse(w io.Writer, model, content string, promptTokens, completionTokens int, latency time.Duration) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, colorGreen("┌─────────────────────────────────────────────────────────────"))
+	fmt.Fprintf(w, "%s DEBUG RESPONSE ← %s %s\n", colorGreen("│"), colorCyan(model), colorDim(latency.String()))
+	fmt.Fprintln(w, colorGreen("├─────────────────────────────────────────────────────────────"))
 
 	lines := strings.Split(content, "\n")
 	for _, line := range lines {
-		fmt.Printf("%s %s\n", colorGreen("│"), line)
+		fmt.Fprintf(w, "%s %s\n", colorGreen("│"), line)
 	}
 
-	fmt.Println(colorGreen("├─────────────────────────────────────────────────────────────"))
-	fmt.Printf("%s Tokens: prompt=%d, completion=%d, total=%d\n",
+	fmt.Fprintln(w, colorGreen("├─────────────────────────────────────────────────────────────"))
+	fmt.Fprintf(w, "%s Tokens: prompt=%d, completion=%d, total=%d\n",
 		colorGreen("│"),
-		resp.Usage.PromptTokens,
-		resp.Usage.CompletionTokens,
-		resp.Usage.TotalTokens,
+		promptTokens,
+		completionTokens,
+		promptTokens+completionTokens,
 	)
-	fmt.Println(colorGreen("└─────────────────────────────────────────────────────────────"))
+	fmt.Fprintln(w, colorGreen("└─────────────────────────────────────────────────────────────"))
 }
 
-// printPrettyResponse prints a formatted response
-func printPrettyResponse(model Model, content string) {
-	fmt.Println()
-	fmt.Printf("%s %s\n", colorCyan("▸"), colorDim(string(model)))
-	fmt.Println(colorDim("─────────────────────────────────────────────────────────────"))
-	fmt.Println(content)
-	fmt.Println()
+// writePrettyResponse writes a formatted response
+func writePrettyResponse(w io.Writer, model, content string) {
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "%s %s\n", colorCyan("▸"), colorDim(model))
+	fmt.Fprintln(w, colorDim("─────────────────────────────────────────────────────────────"))
+	fmt.Fprintln(w, content)
+	fmt.Fprintln(w)
 }
 
-// printPrettyConversation prints a conversation exchange
-func printPrettyConversation(model Model, userMsg, assistantMsg string) {
-	fmt.Println()
-	fmt.Printf("%s %s\n", colorGreen("You:"), userMsg)
-	fmt.Println()
-	fmt.Printf("%s %s\n", colorBlue(string(model)+":"), "")
-	fmt.Println(assistantMsg)
-	fmt.Println()
+// writePrettyConversation writes a conversation exchange
+func writePrettyConversation(w io.Writer, model, userMsg, assistantMsg string) {
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "%s %s\n", colorGreen("You:"), userMsg)
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "%s %s\n", colorBlue(model+":"), "")
+	fmt.Fprintln(w, assistantMsg)
+	fmt.Fprintln(w)
 }
@@ -69,6 +69,11 @@ type AudioProvider interface {
 	SpeechToText(ctx context.Context, req *STTRequest) (*STTResponse, error)
 }
 
+// ModelLister is an interface for providers that can list their available models.
+type ModelLister interface {
+	ListModels(ctx context.Context) ([]ModelSpec, error)
+}
+
 // ═══════════════════════════════════════════════════════════════════════════
 // Provider Types
 // ═══════════════════════════════════════════════════════════════════════════
@@ -83,6 +88,18 @@ const (
 	ProviderGoogle     ProviderType = "google"
 	ProviderOllama     ProviderType = "ollama"
 	ProviderAzure      ProviderType = "azure"
+	ProviderBedrock    ProviderType = "bedrock"
+)
+
+// OpenAI-compatible hosts (see OpenAICompatibleProvider presets).
+const (
+	ProviderGroq     ProviderType = "groq"
+	ProviderTogether ProviderType = "together"
+	ProviderDeepSeek ProviderType = "deepseek"
+	ProviderMistral  ProviderType = "mistral"
+	ProviderVLLM     ProviderType = "vllm"
+	ProviderLlamaCpp ProviderType = "llamacpp"
+	ProviderLMStudio ProviderType = "lmstudio"
 )
 
 // ═══════════════════════════════════════════════════════════════════════════
@@ -95,6 +112,21 @@ type ProviderConfig struct {
 	BaseURL string            // Custom API endpoint (optional)
 	Headers map[string]string // Custom headers to include in requests
 	Timeout time.Duration     // Request timeout
+
+	MediaFetcher *MediaFetcher // Fetcher for inlining remote media (nil = DefaultMediaFetcher)
+
+	Region         string                 // Cloud region (e.g. "us-east-1" for Bedrock)
+	AWSCredentials AWSCredentialsProvider // AWS signing credentials (nil = env, then shared config)
+
+	Project     string      // Cloud project ID (enables Vertex AI mode for Google)
+	TokenSource TokenSource // OAuth2 bearer tokens (nil = provider default)
+
+	APIVersion  string           // API version query parameter (Azure "api-version")
+	Deployments map[Model]string // Model → Azure deployment name
+
+	pool       *keyPoolConfig // set by WithAPIKeys / WithConfigs
+	settings   *settings      // set by WithDebug, WithCache, WithHooks, ...
+	middleware []Middleware   // set by WithMiddleware
 }
 
 // ═══════════════════════════════════════════════════════════════════════════
@@ -108,6 +140,7 @@ type ProviderRequest struct {
 	Messages     []Message
 	Temperature  *float64
 	Thinking     ThinkingLevel
+	MaxTokens    int           // Response length cap (0 = provider default)
 	Tools        []Tool        // Function calling tools
 	BuiltinTools []BuiltinTool // Responses API built-in tools (web_search, file_search, etc.)
 	JSONMode     bool
@@ -119,6 +152,7 @@ type ProviderRequest struct {
 type ProviderResponse struct {
 	Content          string
 	ToolCalls        []ToolCall
+	Model            string // model that served the request, as reported by the provider
 	PromptTokens     int
 	CompletionTokens int
 	TotalTokens      int
@@ -126,6 +160,9 @@ type ProviderResponse struct {
 
 	// Responses API output (populated when using built-in tools)
 	ResponsesOutput *ResponsesOutput
+
+	// RateLimit is the remaining quota reported in the response headers, if any.
+	RateLimit *RateLimitInfo
 }
 
 // ═══════════════════════════════════════════════════════════════════════════
@@ -135,9 +172,14 @@ type ProviderResponse struct {
 // ProviderError wraps an error returned by a provider, adding context.
 type ProviderError struct {
 	Provider string
-	Code     string
+	Code     string // HTTP status or provider error code
 	Message  string
 	Err      error
+
+	StatusCode int            // HTTP status, if the error came from a response
+	Kind       error          // ErrRateLimited, ErrAuth, ...; classified from Code and Message when nil
+	RetryAfter time.Duration  // server-requested delay before retrying, if any
+	RateLimit  *RateLimitInfo // rate-limit headers of the failed response, if any
 }
 
 // Error implements the error interface.
@@ -153,6 +195,13 @@ func (e *ProviderError) Unwrap() error {
 	return e.Err
 }
 
+// Is reports whether target is the error kind of e, so
+// errors.Is(err, ErrRateLimited) and friends work.
+func (e *ProviderError) Is(target error) bool {
+	kind := e.kind()
+	return kind != nil && kind == target
+}
+
 // ═══════════════════════════════════════════════════════════════════════════
 // Model Mapping
 // ═══════════════════════════════════════════════════════════════════════════
@@ -331,26 +380,32 @@ var modelMappings = map[ProviderType]map[Model]string{
 		ModelGemini2Flash:     "gemini-2.0-flash",
 		ModelGemini2FlashLite: "gemini-2.0-flash-lite",
 	},
+	ProviderBedrock: {
+		// Base model IDs; BedrockProvider adds the regional inference profile
+		// prefix ("us.", "eu.", "apac.") for Anthropic and Meta models.
+		ModelClaudeSonnet:   "anthropic.claude-sonnet-4-5-20250929-v1:0",
+		ModelClaudeHaiku:    "anthropic.claude-haiku-4-5-20251001-v1:0",
+		ModelClaudeOpus:     "anthropic.claude-opus-4-5-20251101-v1:0",
+		ModelClaudeOpus41:   "anthropic.claude-opus-4-1-20250805-v1:0",
+		ModelClaudeOpus4:    "anthropic.claude-opus-4-20250514-v1:0",
+		ModelClaudeSonnet4:  "anthropic.claude-sonnet-4-20250514-v1:0",
+		ModelClaudeSonnet37: "anthropic.claude-3-7-sonnet-20250219-v1:0",
+		ModelClaudeHaiku35:  "anthropic.claude-3-5-haiku-20241022-v1:0",
+		ModelLlama4:         "meta.llama4-maverick-17b-instruct-v1:0",
+		ModelMistralLarge:   "mistral.mistral-large-2407-v1:0",
+		ModelGPTOSS120B:     "openai.gpt-oss-120b-1:0",
+		ModelGPTOSS20B:      "openai.gpt-oss-20b-1:0",
+	},
 	// Azure OpenAI uses OpenAI model IDs, but typically routes by deployment in the URL.
-	// We keep Azure's model mapping identical to OpenAI for convenience/consistency.
+	// Its own map holds Azure-only overrides; lookups fall back to OpenAI's.
 	ProviderAzure: {},
 	// Ollama: uses raw model names, no mapping needed
 }
 
-func init() {
-	// Keep Azure mappings aligned with OpenAI mappings (avoid duplicate lists).
-	// This also implicitly supports any future OpenAI model constants.
-	if m, ok := modelMappings[ProviderOpenAI]; ok {
-		modelMappings[ProviderAzure] = m
-	}
-}
-
 // resolveModel converts our Model to provider-specific model ID
 func resolveModel(providerType ProviderType, model Model) string {
-	if mapping, ok := modelMappings[providerType]; ok {
-		if resolved, ok := mapping[model]; ok {
-			return resolved
-		}
+	if resolved, ok := lookupMapping(providerType, model); ok {
+		return resolved
 	}
 
 	raw := string(model)
@@ -379,6 +434,16 @@ func resolveModel(providerType ProviderType, model Model) string {
 		if strings.HasPrefix(raw, "google/") {
 			return strings.TrimPrefix(raw, "google/")
 		}
+	case ProviderMistral:
+		// Mistral's API expects "mistral-large-latest", not "mistralai/mistral-large-latest".
+		if strings.HasPrefix(raw, "mistralai/") {
+			return strings.TrimPrefix(raw, "mistralai/")
+		}
+	case ProviderDeepSeek:
+		// DeepSeek's API expects "deepseek-chat", not "deepseek/deepseek-chat".
+		if strings.HasPrefix(raw, "deepseek/") {
+			return strings.TrimPrefix(raw, "deepseek/")
+		}
 	}
 
 	// Fallback: use raw model string (for Ollama, custom models)
@@ -480,8 +545,8 @@ func looksLikeOpenAIModelID(id string) bool {
 // ═══════════════════════════════════════════════════════════════════════════
 
 // checkCapability logs a warning if using unsupported feature
-func checkCapability(provider Provider, feature string, supported bool) {
-	if !supported && Debug {
+func checkCapability(ctx context.Context, provider Provider, feature string, supported bool) {
+	if !supported && debugOn(ctx) {
 		fmt.Printf("%s Warning: %s does not support %s\n",
 			colorYellow("⚠"), provider.Name(), feature)
 	}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"strings"
 )
@@ -23,6 +24,7 @@ const anthropicAPIVersion = "2023-06-01"
 type AnthropicProvider struct {
 	config     ProviderConfig
 	httpClient *http.Client
+	vertex     *vertexAI // set when serving Claude through Vertex AI (see GoogleProvider)
 }
 
 // NewAnthropicProvider creates an AnthropicProvider.
@@ -63,29 +65,25 @@ func (p *AnthropicProvider) Capabilities() ProviderCapabilities {
 
 // Send executes a non-streaming request.
 func (p *AnthropicProvider) Send(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
-	if p.config.APIKey == "" {
+	if p.vertex == nil && p.config.APIKey == "" {
 		return nil, &ProviderError{
 			Provider: p.Name(),
 			Message:  "ANTHROPIC_API_KEY not set",
 		}
 	}
 
-	anthropicReq := p.buildRequest(req)
-
-	body, err := json.Marshal(anthropicReq)
+	anthropicReq, err := p.buildRequest(req)
 	if err != nil {
-		return nil, &ProviderError{Provider: p.Name(), Message: "failed to marshal request", Err: err}
+		return nil, &ProviderError{Provider: p.Name(), Message: "failed to build request", Err: err}
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.config.BaseURL+"/messages", bytes.NewReader(body))
+	httpReq, err := p.newRequest(ctx, anthropicReq)
 	if err != nil {
-		return nil, &ProviderError{Provider: p.Name(), Message: "failed to create request", Err: err}
+		return nil, err
 	}
 
-	p.setHeaders(httpReq)
-
-	if Debug {
-		fmt.Printf("%s [%s] POST %s\n", colorDim("→"), p.Name(), "/messages")
+	if debugOn(ctx) {
+		fmt.Printf("%s [%s] POST %s\n", colorDim("→"), p.Name(), p.endpointName(anthropicReq))
 	}
 
 	resp, err := p.httpClient.Do(httpReq)
@@ -98,8 +96,16 @@ func (p *AnthropicProvider) Send(ctx context.Context, req *ProviderRequest) (*Pr
 	if err != nil {
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to read response", Err: err}
 	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, newHTTPError(p.Name(), resp, respBody)
+	}
 
-	return p.parseResponse(respBody)
+	result, err := p.parseResponse(respBody)
+	if err != nil {
+		return nil, err
+	}
+	result.RateLimit = parseRateLimitHeaders(resp.Header)
+	return result, nil
 }
 
 // ═══════════════════════════════════════════════════════════════════════════
@@ -108,30 +114,26 @@ func (p *AnthropicProvider) Send(ctx context.Context, req *ProviderRequest) (*Pr
 
 // SendStream executes a streaming request and invokes callback for each chunk.
 func (p *AnthropicProvider) SendStream(ctx context.Context, req *ProviderRequest, callback StreamCallback) (*ProviderResponse, error) {
-	if p.config.APIKey == "" {
+	if p.vertex == nil && p.config.APIKey == "" {
 		return nil, &ProviderError{
 			Provider: p.Name(),
 			Message:  "ANTHROPIC_API_KEY not set",
 		}
 	}
 
-	anthropicReq := p.buildRequest(req)
-	anthropicReq.Stream = true
-
-	body, err := json.Marshal(anthropicReq)
+	anthropicReq, err := p.buildRequest(req)
 	if err != nil {
-		return nil, &ProviderError{Provider: p.Name(), Message: "failed to marshal request", Err: err}
+		return nil, &ProviderError{Provider: p.Name(), Message: "failed to build request", Err: err}
 	}
+	anthropicReq.Stream = true
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.config.BaseURL+"/messages", bytes.NewReader(body))
+	httpReq, err := p.newRequest(ctx, anthropicReq)
 	if err != nil {
-		return nil, &ProviderError{Provider: p.Name(), Message: "failed to create request", Err: err}
+		return nil, err
 	}
 
-	p.setHeaders(httpReq)
-
-	if Debug {
-		fmt.Printf("%s [%s] POST %s (stream)\n", colorDim("→"), p.Name(), "/messages")
+	if debugOn(ctx) {
+		fmt.Printf("%s [%s] POST %s (stream)\n", colorDim("→"), p.Name(), p.endpointName(anthropicReq))
 	}
 
 	resp, err := p.httpClient.Do(httpReq)
@@ -142,14 +144,11 @@ func (p *AnthropicProvider) SendStream(ctx context.Context, req *ProviderRequest
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, &ProviderError{
-			Provider: p.Name(),
-			Code:     fmt.Sprintf("%d", resp.StatusCode),
-			Message:  string(body),
-		}
+		return nil, newHTTPError(p.Name(), resp, body)
 	}
 
 	var fullContent strings.Builder
+	var model string
 	reader := bufio.NewReader(resp.Body)
 
 	for {
@@ -170,7 +169,10 @@ func (p *AnthropicProvider) SendStream(ctx context.Context, req *ProviderRequest
 
 		// Anthropic stream events
 		var event struct {
-			Type  string `json:"type"`
+			Type    string `json:"type"`
+			Message struct {
+				Model string `json:"model"`
+			} `json:"message"`
 			Delta struct {
 				Type string `json:"type"`
 				Text string `json:"text"`
@@ -181,6 +183,11 @@ func (p *AnthropicProvider) SendStream(ctx context.Context, req *ProviderRequest
 			continue
 		}
 
+		// message_start carries the model that serves the request
+		if event.Type == "message_start" {
+			model = event.Message.Model
+		}
+
 		// Handle content_block_delta events
 		if event.Type == "content_block_delta" && event.Delta.Type == "text_delta" {
 			fullContent.WriteString(event.Delta.Text)
@@ -191,14 +198,22 @@ func (p *AnthropicProvider) SendStream(ctx context.Context, req *ProviderRequest
 		if event.Type == "message_stop" {
 			break
 		}
+
+		// Errors after the 200, e.g. overloaded_error
+		if event.Type == "error" {
+			code, message := parseErrorBody(data)
+			return nil, &ProviderError{Provider: p.Name(), Code: code, Message: message}
+		}
 	}
 
 	completionTokens := len(fullContent.String()) / 4
 
 	return &ProviderResponse{
 		Content:          fullContent.String(),
+		Model:            model,
 		CompletionTokens: completionTokens,
 		TotalTokens:      completionTokens,
+		RateLimit:        parseRateLimitHeaders(resp.Header),
 	}, nil
 }
 
@@ -206,9 +221,13 @@ func (p *AnthropicProvider) SendStream(ctx context.Context, req *ProviderRequest
 // Internal helpers
 // ═══════════════════════════════════════════════════════════════════════════
 
+// anthropicDefaultMaxTokens is sent as max_tokens (required by the API) when none is set.
+const anthropicDefaultMaxTokens = 8192
+
 // anthropicRequest is Anthropic's API format
 type anthropicRequest struct {
-	Model       string             `json:"model"`
+	Model       string             `json:"model,omitempty"` // omitted on Vertex AI (the model is in the URL)
+	Version     string             `json:"anthropic_version,omitempty"`
 	MaxTokens   int                `json:"max_tokens"`
 	System      string             `json:"system,omitempty"`
 	Messages    []anthropicMessage `json:"messages"`
@@ -231,8 +250,8 @@ type anthropicContent struct {
 }
 
 type mediaSource struct {
-	Type      string `json:"type"`       // "base64" or "url"
-	MediaType string `json:"media_type"` // e.g., "image/png", "application/pdf"
+	Type      string `json:"type"`                 // "base64" or "url"
+	MediaType string `json:"media_type,omitempty"` // e.g., "image/png", "application/pdf" (base64 only)
 	Data      string `json:"data,omitempty"`
 	URL       string `json:"url,omitempty"`
 }
@@ -248,7 +267,7 @@ type anthropicThinking struct {
 	BudgetTokens int    `json:"budget_tokens,omitempty"`
 }
 
-func (p *AnthropicProvider) buildRequest(req *ProviderRequest) *anthropicRequest {
+func (p *AnthropicProvider) buildRequest(req *ProviderRequest) (*anthropicRequest, error) {
 	// Convert our messages to Anthropic format
 	var system string
 	var messages []anthropicMessage
@@ -285,22 +304,24 @@ func (p *AnthropicProvider) buildRequest(req *ProviderRequest) *anthropicRequest
 						Text: part.Text,
 					})
 				} else if part.Type == "image_url" && part.ImageURL != nil {
-					// Parse data URI
 					url := part.ImageURL.URL
-					if strings.HasPrefix(url, "data:") {
-						// Extract media type and base64 data
-						dataParts := strings.SplitN(url, ",", 2)
-						if len(dataParts) == 2 {
-							mediaType := strings.TrimPrefix(strings.Split(dataParts[0], ";")[0], "data:")
-							parts = append(parts, anthropicContent{
-								Type: "image",
-								Source: &mediaSource{
-									Type:      "base64",
-									MediaType: mediaType,
-									Data:      dataParts[1],
-								},
-							})
-						}
+					if mediaType, data, ok := splitDataURI(url); ok {
+						parts = append(parts, anthropicContent{
+							Type: "image",
+							Source: &mediaSource{
+								Type:      "base64",
+								MediaType: mediaType,
+								Data:      data,
+							},
+						})
+					} else if isHTTPURL(url) {
+						// Claude fetches remote images itself
+						parts = append(parts, anthropicContent{
+							Type:   "image",
+							Source: &mediaSource{Type: "url", URL: url},
+						})
+					} else {
+						return nil, fmt.Errorf("unsupported image URL: %s", url)
 					}
 				} else if part.Type == "document" && part.Document != nil {
 					// Handle PDF/document content
@@ -316,14 +337,13 @@ func (p *AnthropicProvider) buildRequest(req *ProviderRequest) *anthropicRequest
 							},
 						})
 					} else if doc.URL != "" {
-						// URL-based document
+						// URL-based document (Claude fetches it)
+						if !isHTTPURL(doc.URL) {
+							return nil, fmt.Errorf("unsupported document URL: %s", doc.URL)
+						}
 						parts = append(parts, anthropicContent{
-							Type: "document",
-							Source: &mediaSource{
-								Type:      "url",
-								MediaType: doc.MimeType,
-								URL:       doc.URL,
-							},
+							Type:   "document",
+							Source: &mediaSource{Type: "url", URL: doc.URL},
 						})
 					}
 				}
@@ -341,11 +361,14 @@ func (p *AnthropicProvider) buildRequest(req *ProviderRequest) *anthropicRequest
 
 	anthropicReq := &anthropicRequest{
 		Model:     resolveModel(ProviderAnthropic, Model(req.Model)),
-		MaxTokens: 8192, // Default max tokens
+		MaxTokens: anthropicDefaultMaxTokens,
 		System:    system,
 		Messages:  messages,
 	}
 
+	if req.MaxTokens > 0 {
+		anthropicReq.MaxTokens = req.MaxTokens
+	}
 	if req.Temperature != nil {
 		anthropicReq.Temperature = req.Temperature
 	}
@@ -378,7 +401,53 @@ func (p *AnthropicProvider) buildRequest(req *ProviderRequest) *anthropicRequest
 		}
 	}
 
-	return anthropicReq
+	return anthropicReq, nil
+}
+
+// newRequest marshals areq and builds the authorized HTTP request. On
+// Vertex AI the model moves into the rawPredict URL and the API version
+// into the body.
+func (p *AnthropicProvider) newRequest(ctx context.Context, areq *anthropicRequest) (*http.Request, error) {
+	endpoint := p.config.BaseURL + "/messages"
+	if p.vertex != nil {
+		method := "rawPredict"
+		if areq.Stream {
+			method = "streamRawPredict"
+		}
+		endpoint = p.vertex.modelURL("anthropic", vertexClaudeModelID(areq.Model), method)
+		areq.Model = ""
+		areq.Version = vertexAnthropicVersion
+	}
+
+	body, err := json.Marshal(areq)
+	if err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "failed to marshal request", Err: err}
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "failed to create request", Err: err}
+	}
+
+	if p.vertex != nil {
+		if err := p.vertex.authorize(ctx, httpReq); err != nil {
+			return nil, &ProviderError{Provider: p.Name(), Message: "failed to get access token", Err: err}
+		}
+	} else {
+		p.setHeaders(httpReq)
+	}
+	return httpReq, nil
+}
+
+// endpointName is the request path shown in debug output.
+func (p *AnthropicProvider) endpointName(areq *anthropicRequest) string {
+	if p.vertex == nil {
+		return "/messages"
+	}
+	if areq.Stream {
+		return ":streamRawPredict"
+	}
+	return ":rawPredict"
 }
 
 func (p *AnthropicProvider) setHeaders(req *http.Request) {
@@ -396,6 +465,7 @@ func (p *AnthropicProvider) parseResponse(body []byte) (*ProviderResponse, error
 		ID      string `json:"id"`
 		Type    string `json:"type"`
 		Role    string `json:"role"`
+		Model   string `json:"model"`
 		Content []struct {
 			Type  string         `json:"type"`
 			Text  string         `json:"text,omitempty"`
@@ -457,9 +527,58 @@ func (p *AnthropicProvider) parseResponse(body []byte) (*ProviderResponse, error
 	return &ProviderResponse{
 		Content:          content.String(),
 		ToolCalls:        toolCalls,
+		Model:            result.Model,
 		PromptTokens:     result.Usage.InputTokens,
 		CompletionTokens: result.Usage.OutputTokens,
 		TotalTokens:      result.Usage.InputTokens + result.Usage.OutputTokens,
 		FinishReason:     result.StopReason,
 	}, nil
 }
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Model Listing
+// ═══════════════════════════════════════════════════════════════════════════
+
+// ListModels lists the Claude models available to the API key, following pagination.
+func (p *AnthropicProvider) ListModels(ctx context.Context) ([]ModelSpec, error) {
+	index := catalogIndex(ProviderAnthropic)
+	var specs []ModelSpec
+
+	afterID := ""
+	for {
+		endpoint := p.config.BaseURL + "/models?limit=1000"
+		if afterID != "" {
+			endpoint += "&after_id=" + url.QueryEscape(afterID)
+		}
+
+		var page struct {
+			Data []struct {
+				ID          string `json:"id"`
+				DisplayName string `json:"display_name"`
+			} `json:"data"`
+			HasMore bool   `json:"has_more"`
+			LastID  string `json:"last_id"`
+		}
+		if err := getJSON(ctx, p.httpClient, p.Name(), endpoint, p.setHeaders, &page); err != nil {
+			return nil, err
+		}
+
+		for _, m := range page.Data {
+			spec := discoveredSpec(index, ProviderAnthropic, "anthropic/", m.ID)
+			spec.Name = m.DisplayName
+			spec.Provider = "Anthropic"
+			// The API doesn't report capabilities. Models the catalog doesn't
+			// know yet are assumed to match current Claude models.
+			if _, known := index[m.ID]; !known {
+				spec.InputModalities = inTextImageDoc
+				spec.Tools = true
+			}
+			specs = append(specs, spec)
+		}
+
+		if !page.HasMore || page.LastID == "" {
+			return specs, nil
+		}
+		afterID = page.LastID
+	}
+}
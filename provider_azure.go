@@ -0,0 +1,214 @@
+package ai
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Azure OpenAI Provider
+// ═══════════════════════════════════════════════════════════════════════════
+
+const (
+	azureDefaultAPIVersion   = "2024-10-21"
+	azureResponsesAPIVersion = "2025-04-01-preview" // the Responses API needs a preview version
+	azurePlaceholderURL      = "https://YOUR-RESOURCE.openai.azure.com/openai/deployments/YOUR-DEPLOYMENT"
+)
+
+// AzureCognitiveServicesScope is the Entra ID scope for Azure OpenAI.
+const AzureCognitiveServicesScope = "https://cognitiveservices.azure.com/.default"
+
+// AzureProvider is a specialized OpenAI provider for Azure deployments.
+// Requests go to {endpoint}/openai/deployments/{deployment}/... with an
+// api-version query and either an api-key header or an Entra ID bearer token.
+type AzureProvider struct {
+	*OpenAIProvider
+	deploymentURL string
+}
+
+// NewAzureProvider creates a new Azure OpenAI provider.
+//
+// BaseURL is the resource endpoint (https://NAME.openai.azure.com), or, for
+// compatibility, a full deployment URL (.../openai/deployments/NAME) used for
+// every unmapped model. It defaults to AZURE_OPENAI_ENDPOINT.
+//
+// Each model is served by the deployment in config.Deployments, falling back
+// to the deployment in BaseURL, then to the model's native ID.
+//
+// Auth uses the api-key header (AZURE_OPENAI_API_KEY or OPENAI_API_KEY). Without
+// a key, config.TokenSource provides Entra ID tokens; AZURE_TENANT_ID,
+// AZURE_CLIENT_ID and AZURE_CLIENT_SECRET enable client-credentials auth.
+func NewAzureProvider(config ProviderConfig) *AzureProvider {
+	if config.BaseURL == "" {
+		config.BaseURL = os.Getenv("AZURE_OPENAI_ENDPOINT")
+	}
+	if config.BaseURL == "" {
+		// User must provide endpoint
+		config.BaseURL = azurePlaceholderURL
+	}
+	if config.APIKey == "" {
+		config.APIKey = getEnvWithFallback("AZURE_OPENAI_API_KEY", "OPENAI_API_KEY")
+	}
+	if config.APIVersion == "" {
+		config.APIVersion = getEnvWithFallback("AZURE_OPENAI_API_VERSION", "OPENAI_API_VERSION")
+	}
+	if config.TokenSource == nil && config.APIKey == "" {
+		tenant, client, secret := os.Getenv("AZURE_TENANT_ID"), os.Getenv("AZURE_CLIENT_ID"), os.Getenv("AZURE_CLIENT_SECRET")
+		if tenant != "" && client != "" && secret != "" {
+			config.TokenSource = AzureClientCredentials(tenant, client, secret)
+		}
+	}
+
+	target := newAzureTarget(config)
+	openai := NewOpenAIProvider(config)
+	openai.azure = target
+
+	var deploymentURL string
+	if target.defaultDeployment != "" {
+		deploymentURL = config.BaseURL
+	}
+	return &AzureProvider{OpenAIProvider: openai, deploymentURL: deploymentURL}
+}
+
+// Name returns the provider identifier ("azure").
+func (p *AzureProvider) Name() string {
+	return "azure"
+}
+
+// ListModels is not supported: Azure serves the deployments configured on the
+// resource rather than a model list.
+func (p *AzureProvider) ListModels(ctx context.Context) ([]ModelSpec, error) {
+	return nil, &ProviderError{Provider: p.Name(), Message: "listing models is not supported; models are Azure deployments"}
+}
+
+// WithAPIVersion sets the API version (Azure's api-version query parameter)
+// for every endpoint. By default the Responses API uses a preview version.
+func WithAPIVersion(version string) ClientOption {
+	return func(c *ProviderConfig) {
+		c.APIVersion = version
+	}
+}
+
+// WithAzureDeployments maps models to Azure deployment names, so Fallback and
+// per-request models switch deployments. Embedding and audio models are keyed
+// the same way, e.g. Model(EmbedTextSmall3).
+func WithAzureDeployments(deployments map[Model]string) ClientOption {
+	return func(c *ProviderConfig) {
+		if c.Deployments == nil {
+			c.Deployments = map[Model]string{}
+		}
+		for model, deployment := range deployments {
+			c.Deployments[model] = deployment
+		}
+	}
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Entra ID
+// ═══════════════════════════════════════════════════════════════════════════
+
+// AzureClientCredentials returns a cached Entra ID token source using the
+// OAuth2 client-credentials flow for an app registration. No scopes means
+// AzureCognitiveServicesScope. AZURE_AUTHORITY_HOST overrides the login host.
+func AzureClientCredentials(tenantID, clientID, clientSecret string, scopes ...string) TokenSource {
+	if len(scopes) == 0 {
+		scopes = []string{AzureCognitiveServicesScope}
+	}
+	return NewCachedTokenSource(func(ctx context.Context) (string, time.Time, error) {
+		authority := strings.TrimSuffix(os.Getenv("AZURE_AUTHORITY_HOST"), "/")
+		if authority == "" {
+			authority = "https://login.microsoftonline.com"
+		}
+		return requestOAuthToken(ctx, authority+"/"+url.PathEscape(tenantID)+"/oauth2/v2.0/token", url.Values{
+			"grant_type":    {"client_credentials"},
+			"client_id":     {clientID},
+			"client_secret": {clientSecret},
+			"scope":         {strings.Join(scopes, " ")},
+		})
+	})
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Deployment Routing
+// ═══════════════════════════════════════════════════════════════════════════
+
+// azureTarget maps models to deployments on one Azure OpenAI resource.
+type azureTarget struct {
+	endpoint          string // https://NAME.openai.azure.com
+	defaultDeployment string // from a legacy deployment BaseURL
+	apiVersion        string // empty selects the default for each API
+	deployments       map[Model]string
+	tokens            TokenSource
+}
+
+func newAzureTarget(config ProviderConfig) *azureTarget {
+	endpoint := strings.TrimSuffix(config.BaseURL, "/")
+	var deployment string
+	if i := strings.Index(endpoint, "/openai/deployments/"); i >= 0 {
+		deployment, _, _ = strings.Cut(endpoint[i+len("/openai/deployments/"):], "/")
+		endpoint = endpoint[:i]
+	}
+	endpoint = strings.TrimSuffix(endpoint, "/openai")
+
+	return &azureTarget{
+		endpoint:          endpoint,
+		defaultDeployment: deployment,
+		apiVersion:        config.APIVersion,
+		deployments:       config.Deployments,
+		tokens:            config.TokenSource,
+	}
+}
+
+// deployment returns the deployment serving model.
+func (t *azureTarget) deployment(model Model) string {
+	if d, ok := t.deployments[model]; ok {
+		return d
+	}
+	if t.defaultDeployment != "" {
+		return t.defaultDeployment
+	}
+	return resolveModel(ProviderAzure, model)
+}
+
+// url returns the deployment endpoint for an OpenAI API path. The Responses
+// API is resource-scoped and takes the deployment as the model instead.
+func (t *azureTarget) url(path string, model Model) string {
+	if path == "/responses" {
+		return t.endpoint + "/openai/responses" + t.query(azureResponsesAPIVersion)
+	}
+	return t.endpoint + "/openai/deployments/" + url.PathEscape(t.deployment(model)) + path + t.query(azureDefaultAPIVersion)
+}
+
+// query returns the api-version query, using fallback unless a version is configured.
+func (t *azureTarget) query(fallback string) string {
+	version := t.apiVersion
+	if version == "" {
+		version = fallback
+	}
+	return "?api-version=" + url.QueryEscape(version)
+}
+
+func (t *azureTarget) checkKey(config ProviderConfig) error {
+	if config.APIKey == "" && t.tokens == nil {
+		return &ProviderError{Provider: "azure", Message: "AZURE_OPENAI_API_KEY not set (or use WithTokenSource for Entra ID)"}
+	}
+	return nil
+}
+
+// authorize sets the api-key header, or an Entra ID bearer token when no key is set.
+func (t *azureTarget) authorize(ctx context.Context, req *http.Request, apiKey string) error {
+	if apiKey != "" {
+		req.Header.Set("api-key", apiKey)
+		return nil
+	}
+	token, err := t.tokens.Token(ctx)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
@@ -0,0 +1,137 @@
+package ai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAzure_DeploymentsHeadersAndAPIVersion(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("api-key") != "az-key" || r.Header.Get("Authorization") != "" {
+			t.Fatalf("expected api-key auth, got headers %v", r.Header)
+		}
+		if got := r.URL.Query().Get("api-version"); got != "2025-01-01-preview" {
+			t.Fatalf("unexpected api-version %q", got)
+		}
+		paths = append(paths, r.URL.Path)
+		if r.URL.Path == "/openai/deployments/embed-prod/embeddings" {
+			_, _ = w.Write([]byte(`{"data":[{"embedding":[0.1,0.2],"index":0}],"usage":{"total_tokens":2}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"content":"ok"},"finish_reason":"stop"}]}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(ProviderAzure, WithBaseURL(srv.URL), WithAPIKey("az-key"), WithAPIVersion("2025-01-01-preview"),
+		WithAzureDeployments(map[Model]string{
+			ModelGPT4o:             "gpt4o-eastus",
+			Model(EmbedTextSmall3): "embed-prod",
+		}))
+	ctx := context.Background()
+	msgs := []Message{{Role: "user", Content: "hi"}}
+
+	if _, err := c.provider.Send(ctx, &ProviderRequest{Model: string(ModelGPT4o), Messages: msgs}); err != nil {
+		t.Fatal(err)
+	}
+	// Unmapped models use a deployment named after the native model ID.
+	if _, err := c.provider.Send(ctx, &ProviderRequest{Model: string(ModelGPT4oMini), Messages: msgs}); err != nil {
+		t.Fatal(err)
+	}
+	emb, err := c.provider.(Embedder).Embed(ctx, &EmbeddingRequest{Model: string(EmbedTextSmall3), Input: []string{"x"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if emb.Dimensions != 2 {
+		t.Fatalf("unexpected embedding %+v", emb)
+	}
+
+	want := []string{
+		"/openai/deployments/gpt4o-eastus/chat/completions",
+		"/openai/deployments/" + resolveModel(ProviderAzure, ModelGPT4oMini) + "/chat/completions",
+		"/openai/deployments/embed-prod/embeddings",
+	}
+	if len(paths) != len(want) {
+		t.Fatalf("unexpected paths %v", paths)
+	}
+	for i := range want {
+		if paths[i] != want[i] {
+			t.Fatalf("path %d = %q, want %q", i, paths[i], want[i])
+		}
+	}
+}
+
+func TestAzure_LegacyDeploymentURLAndEntraID(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+	t.Setenv("AZURE_OPENAI_API_KEY", "")
+	t.Setenv("OPENAI_API_KEY", "")
+
+	tokenRequests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/tenant-1/oauth2/v2.0/token" {
+			tokenRequests++
+			_ = r.ParseForm()
+			if r.Form.Get("grant_type") != "client_credentials" || r.Form.Get("scope") != AzureCognitiveServicesScope {
+				t.Fatalf("unexpected token form %v", r.Form)
+			}
+			_, _ = w.Write([]byte(`{"access_token":"entra-token","expires_in":3600}`))
+			return
+		}
+		if r.URL.Path != "/openai/deployments/legacy/chat/completions" {
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer entra-token" || r.Header.Get("api-key") != "" {
+			t.Fatalf("expected Entra ID bearer auth, got headers %v", r.Header)
+		}
+		if r.URL.Query().Get("api-version") != azureDefaultAPIVersion {
+			t.Fatalf("expected default api-version, got %q", r.URL.RawQuery)
+		}
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"content":"ok"},"finish_reason":"stop"}]}`))
+	}))
+	defer srv.Close()
+
+	t.Setenv("AZURE_AUTHORITY_HOST", srv.URL)
+	c := NewClient(ProviderAzure, WithBaseURL(srv.URL+"/openai/deployments/legacy"),
+		WithTokenSource(AzureClientCredentials("tenant-1", "client", "secret")))
+
+	for i := 0; i < 2; i++ {
+		resp, err := c.provider.Send(context.Background(), &ProviderRequest{
+			Model:    string(ModelGPT4o),
+			Messages: []Message{{Role: "user", Content: "hi"}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.Content != "ok" {
+			t.Fatalf("unexpected content %q", resp.Content)
+		}
+	}
+	if tokenRequests != 1 {
+		t.Fatalf("expected cached Entra ID token, got %d token requests", tokenRequests)
+	}
+}
+
+func TestAzure_ResponsesAPIVersion(t *testing.T) {
+	t.Setenv("AZURE_OPENAI_API_VERSION", "")
+	t.Setenv("OPENAI_API_VERSION", "")
+
+	p := NewAzureProvider(ProviderConfig{BaseURL: "https://res.openai.azure.com", APIKey: "k"})
+	if got := p.azure.url("/responses", ModelGPT4o); got != "https://res.openai.azure.com/openai/responses?api-version="+azureResponsesAPIVersion {
+		t.Fatalf("unexpected responses URL %s", got)
+	}
+	if got := p.azure.url("/chat/completions", ModelGPT4o); !strings.HasSuffix(got, "?api-version="+azureDefaultAPIVersion) {
+		t.Fatalf("unexpected chat URL %s", got)
+	}
+
+	p = NewAzureProvider(ProviderConfig{BaseURL: "https://res.openai.azure.com", APIKey: "k", APIVersion: "2025-01-01-preview"})
+	if got := p.azure.url("/responses", ModelGPT4o); !strings.HasSuffix(got, "?api-version=2025-01-01-preview") {
+		t.Fatalf("expected the configured version, got %s", got)
+	}
+}
@@ -0,0 +1,620 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// AWS Bedrock Provider
+// ═══════════════════════════════════════════════════════════════════════════
+
+// BedrockProvider implements Provider for Amazon Bedrock's Converse API.
+// Requests are signed with SigV4, or sent with a Bedrock API key when one is set.
+type BedrockProvider struct {
+	config     ProviderConfig
+	region     string
+	creds      AWSCredentialsProvider
+	httpClient *http.Client
+}
+
+// NewBedrockProvider creates a BedrockProvider.
+// The region comes from config.Region, AWS_REGION, AWS_DEFAULT_REGION or the
+// shared config file (default "us-east-1"). Credentials come from
+// config.AWSCredentials, else the environment, then ~/.aws/credentials.
+// If the APIKey is empty, AWS_BEARER_TOKEN_BEDROCK is used when set.
+func NewBedrockProvider(config ProviderConfig) *BedrockProvider {
+	region := config.Region
+	if region == "" {
+		region = awsRegion()
+	}
+	if region == "" {
+		region = "us-east-1"
+	}
+	if config.BaseURL == "" {
+		config.BaseURL = fmt.Sprintf("https://bedrock-runtime.%s.amazonaws.com", region)
+	}
+	if config.APIKey == "" {
+		config.APIKey = os.Getenv("AWS_BEARER_TOKEN_BEDROCK")
+	}
+	creds := config.AWSCredentials
+	if creds == nil {
+		creds = DefaultAWSCredentials()
+	}
+	client := http.DefaultClient
+	if config.Timeout > 0 {
+		client = &http.Client{Timeout: config.Timeout}
+	}
+	return &BedrockProvider{config: config, region: region, creds: creds, httpClient: client}
+}
+
+// Name returns the provider identifier ("bedrock").
+func (p *BedrockProvider) Name() string {
+	return "bedrock"
+}
+
+// Capabilities reports the features supported by this provider implementation.
+func (p *BedrockProvider) Capabilities() ProviderCapabilities {
+	return ProviderCapabilities{
+		Tools:     true,
+		Vision:    true,
+		Streaming: true,
+		Thinking:  true, // Claude extended thinking
+		PDF:       true,
+	}
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Send
+// ═══════════════════════════════════════════════════════════════════════════
+
+// Send executes a non-streaming request via Converse.
+func (p *BedrockProvider) Send(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+	resp, err := p.post(ctx, req, "converse")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "failed to read response", Err: err}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, p.httpError(resp, respBody)
+	}
+
+	return p.parseResponse(respBody)
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// SendStream
+// ═══════════════════════════════════════════════════════════════════════════
+
+// SendStream executes a streaming request via ConverseStream and invokes
+// callback for each text delta.
+func (p *BedrockProvider) SendStream(ctx context.Context, req *ProviderRequest, callback StreamCallback) (*ProviderResponse, error) {
+	resp, err := p.post(ctx, req, "converse-stream")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, p.httpError(resp, body)
+	}
+
+	var fullContent strings.Builder
+	result := &ProviderResponse{}
+	toolCalls := map[int]*ToolCall{}
+	var toolOrder []int
+
+	for {
+		msg, err := readEventStreamMessage(resp.Body)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, &ProviderError{Provider: p.Name(), Message: "stream read error", Err: err}
+		}
+
+		if msg.Headers[":message-type"] != "event" {
+			code := msg.Headers[":exception-type"]
+			if code == "" {
+				code = msg.Headers[":error-code"]
+			}
+			return nil, &ProviderError{Provider: p.Name(), Code: code, Message: bedrockErrorMessage(msg.Payload)}
+		}
+
+		var event struct {
+			ContentBlockIndex int `json:"contentBlockIndex"`
+			Start             struct {
+				ToolUse *struct {
+					ToolUseID string `json:"toolUseId"`
+					Name      string `json:"name"`
+				} `json:"toolUse"`
+			} `json:"start"`
+			Delta struct {
+				Text    string `json:"text"`
+				ToolUse *struct {
+					Input string `json:"input"`
+				} `json:"toolUse"`
+			} `json:"delta"`
+			StopReason string        `json:"stopReason"`
+			Usage      *bedrockUsage `json:"usage"`
+		}
+		if err := json.Unmarshal(msg.Payload, &event); err != nil {
+			continue
+		}
+
+		switch msg.Headers[":event-type"] {
+		case "contentBlockStart":
+			if tu := event.Start.ToolUse; tu != nil {
+				call := &ToolCall{ID: tu.ToolUseID, Type: "function"}
+				call.Function.Name = tu.Name
+				toolCalls[event.ContentBlockIndex] = call
+				toolOrder = append(toolOrder, event.ContentBlockIndex)
+			}
+		case "contentBlockDelta":
+			if event.Delta.Text != "" {
+				fullContent.WriteString(event.Delta.Text)
+				callback(event.Delta.Text)
+			}
+			if tu := event.Delta.ToolUse; tu != nil {
+				if call := toolCalls[event.ContentBlockIndex]; call != nil {
+					call.Function.Arguments += tu.Input
+				}
+			}
+		case "messageStop":
+			result.FinishReason = event.StopReason
+		case "metadata":
+			if u := event.Usage; u != nil {
+				result.PromptTokens = u.InputTokens
+				result.CompletionTokens = u.OutputTokens
+				result.TotalTokens = u.TotalTokens
+			}
+		}
+	}
+
+	result.Content = fullContent.String()
+	for _, i := range toolOrder {
+		result.ToolCalls = append(result.ToolCalls, *toolCalls[i])
+	}
+	return result, nil
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Internal helpers
+// ═══════════════════════════════════════════════════════════════════════════
+
+type bedrockRequest struct {
+	Messages        []bedrockMessage   `json:"messages"`
+	System          []bedrockContent   `json:"system,omitempty"`
+	InferenceConfig *bedrockInference  `json:"inferenceConfig,omitempty"`
+	ToolConfig      *bedrockToolConfig `json:"toolConfig,omitempty"`
+	// Model-specific fields, e.g. Claude's "thinking"
+	AdditionalModelRequestFields map[string]any `json:"additionalModelRequestFields,omitempty"`
+}
+
+type bedrockMessage struct {
+	Role    string           `json:"role"`
+	Content []bedrockContent `json:"content"`
+}
+
+type bedrockContent struct {
+	Text       string             `json:"text,omitempty"`
+	Image      *bedrockImage      `json:"image,omitempty"`
+	Document   *bedrockDocument   `json:"document,omitempty"`
+	ToolUse    *bedrockToolUse    `json:"toolUse,omitempty"`
+	ToolResult *bedrockToolResult `json:"toolResult,omitempty"`
+}
+
+type bedrockSource struct {
+	Bytes string `json:"bytes"` // base64
+}
+
+type bedrockImage struct {
+	Format string        `json:"format"`
+	Source bedrockSource `json:"source"`
+}
+
+type bedrockDocument struct {
+	Format string        `json:"format"`
+	Name   string        `json:"name"`
+	Source bedrockSource `json:"source"`
+}
+
+type bedrockToolUse struct {
+	ToolUseID string          `json:"toolUseId"`
+	Name      string          `json:"name"`
+	Input     json.RawMessage `json:"input"`
+}
+
+type bedrockToolResult struct {
+	ToolUseID string           `json:"toolUseId"`
+	Content   []bedrockContent `json:"content"`
+}
+
+type bedrockInference struct {
+	MaxTokens   int      `json:"maxTokens,omitempty"`
+	Temperature *float64 `json:"temperature,omitempty"`
+}
+
+type bedrockToolConfig struct {
+	Tools []bedrockTool `json:"tools"`
+}
+
+type bedrockTool struct {
+	ToolSpec struct {
+		Name        string `json:"name"`
+		Description string `json:"description,omitempty"`
+		InputSchema struct {
+			JSON map[string]any `json:"json"`
+		} `json:"inputSchema"`
+	} `json:"toolSpec"`
+}
+
+type bedrockUsage struct {
+	InputTokens  int `json:"inputTokens"`
+	OutputTokens int `json:"outputTokens"`
+	TotalTokens  int `json:"totalTokens"`
+}
+
+// modelID resolves the Bedrock model ID. Catalog Claude and Llama models use
+// the cross-region inference profile for the provider's geography
+// (e.g. "us.anthropic.claude-sonnet-4-5-20250929-v1:0"), which on-demand
+// throughput requires; explicit IDs and ARNs are sent unchanged.
+func (p *BedrockProvider) modelID(model string) string {
+	id, ok := lookupMapping(ProviderBedrock, Model(model))
+	if !ok {
+		return model
+	}
+	if strings.HasPrefix(id, "anthropic.") || strings.HasPrefix(id, "meta.") {
+		switch {
+		case strings.HasPrefix(p.region, "us-gov-"):
+			return "us-gov." + id
+		case strings.HasPrefix(p.region, "us-"), strings.HasPrefix(p.region, "ca-"):
+			return "us." + id
+		case strings.HasPrefix(p.region, "eu-"):
+			return "eu." + id
+		case strings.HasPrefix(p.region, "ap-"):
+			return "apac." + id
+		}
+	}
+	return id
+}
+
+func (p *BedrockProvider) buildRequest(ctx context.Context, req *ProviderRequest, modelID string) (*bedrockRequest, error) {
+	bReq := &bedrockRequest{}
+
+	for _, msg := range req.Messages {
+		if msg.Role == "system" {
+			if text := contentText(msg.Content); text != "" {
+				bReq.System = append(bReq.System, bedrockContent{Text: text})
+			}
+			continue
+		}
+
+		role := "user"
+		var content []bedrockContent
+		switch msg.Role {
+		case "assistant":
+			role = "assistant"
+			if text := contentText(msg.Content); text != "" {
+				content = append(content, bedrockContent{Text: text})
+			}
+			for _, tc := range msg.ToolCalls {
+				input := json.RawMessage(tc.Function.Arguments)
+				if !json.Valid(input) {
+					input = json.RawMessage("{}")
+				}
+				content = append(content, bedrockContent{ToolUse: &bedrockToolUse{
+					ToolUseID: tc.ID,
+					Name:      tc.Function.Name,
+					Input:     input,
+				}})
+			}
+		case "tool":
+			content = append(content, bedrockContent{ToolResult: &bedrockToolResult{
+				ToolUseID: msg.ToolCallID,
+				Content:   []bedrockContent{{Text: contentText(msg.Content)}},
+			}})
+		default:
+			parts, err := p.userContent(ctx, msg.Content, len(bReq.Messages))
+			if err != nil {
+				return nil, err
+			}
+			content = parts
+		}
+		if len(content) == 0 {
+			continue
+		}
+
+		// Converse requires alternating roles: merge consecutive turns
+		// (e.g. several tool results) into one message.
+		if n := len(bReq.Messages); n > 0 && bReq.Messages[n-1].Role == role {
+			bReq.Messages[n-1].Content = append(bReq.Messages[n-1].Content, content...)
+			continue
+		}
+		bReq.Messages = append(bReq.Messages, bedrockMessage{Role: role, Content: content})
+	}
+
+	inference := &bedrockInference{MaxTokens: req.MaxTokens, Temperature: req.Temperature}
+
+	// Claude extended thinking; max tokens must exceed the budget.
+	if req.Thinking != "" && strings.Contains(modelID, "anthropic.") {
+		budget := 1024
+		switch req.Thinking {
+		case ThinkingMedium:
+			budget = 4096
+		case ThinkingHigh:
+			budget = 16384
+		}
+		if inference.MaxTokens <= budget {
+			inference.MaxTokens = budget + anthropicDefaultMaxTokens
+		}
+		inference.Temperature = nil // not allowed with thinking
+		bReq.AdditionalModelRequestFields = map[string]any{
+			"thinking": map[string]any{"type": "enabled", "budget_tokens": budget},
+		}
+	}
+	if inference.MaxTokens > 0 || inference.Temperature != nil {
+		bReq.InferenceConfig = inference
+	}
+
+	if len(req.Tools) > 0 {
+		bReq.ToolConfig = &bedrockToolConfig{}
+		for _, tool := range req.Tools {
+			var bt bedrockTool
+			bt.ToolSpec.Name = tool.Function.Name
+			bt.ToolSpec.Description = tool.Function.Description
+			bt.ToolSpec.InputSchema.JSON = tool.Function.Parameters
+			bReq.ToolConfig.Tools = append(bReq.ToolConfig.Tools, bt)
+		}
+	}
+
+	return bReq, nil
+}
+
+// userContent converts user message content, inlining images and documents as bytes.
+func (p *BedrockProvider) userContent(ctx context.Context, content any, turn int) ([]bedrockContent, error) {
+	parts, ok := content.([]ContentPart)
+	if !ok {
+		if text := contentText(content); text != "" {
+			return []bedrockContent{{Text: text}}, nil
+		}
+		return nil, nil
+	}
+
+	var out []bedrockContent
+	for i, part := range parts {
+		switch {
+		case part.Type == "text" && part.Text != "":
+			out = append(out, bedrockContent{Text: part.Text})
+		case part.Type == "image_url" && part.ImageURL != nil:
+			mimeType, data, err := p.inline(ctx, part.ImageURL.URL, "")
+			if err != nil {
+				return nil, err
+			}
+			format := strings.TrimPrefix(mimeType, "image/")
+			if format == "jpg" {
+				format = "jpeg"
+			}
+			out = append(out, bedrockContent{Image: &bedrockImage{Format: format, Source: bedrockSource{Bytes: data}}})
+		case part.Type == "document" && part.Document != nil:
+			doc := part.Document
+			mimeType, data := doc.MimeType, doc.Data
+			if data == "" {
+				var err error
+				if mimeType, data, err = p.inline(ctx, doc.URL, doc.MimeType); err != nil {
+					return nil, err
+				}
+			}
+			format, ok := bedrockDocumentFormats[mimeType]
+			if !ok {
+				return nil, fmt.Errorf("unsupported document type for Bedrock: %s", mimeType)
+			}
+			out = append(out, bedrockContent{Document: &bedrockDocument{
+				Format: format,
+				Name:   bedrockDocumentName(doc.Name, turn, i),
+				Source: bedrockSource{Bytes: data},
+			}})
+		}
+	}
+	return out, nil
+}
+
+// inline returns the MIME type and base64 data of a data URI or remote URL.
+func (p *BedrockProvider) inline(ctx context.Context, ref, mimeType string) (string, string, error) {
+	if mt, data, ok := splitDataURI(ref); ok {
+		return mt, data, nil
+	}
+	data, fetchedType, err := mediaFetcher(p.config).FetchBase64(ctx, ref)
+	if err != nil {
+		return "", "", err
+	}
+	if mimeType == "" {
+		mimeType = fetchedType
+	}
+	return mimeType, data, nil
+}
+
+var bedrockDocumentFormats = map[string]string{
+	"application/pdf":          "pdf",
+	"text/csv":                 "csv",
+	"text/html":                "html",
+	"text/plain":               "txt",
+	"text/markdown":            "md",
+	"application/msword":       "doc",
+	"application/vnd.ms-excel": "xls",
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document": "docx",
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet":       "xlsx",
+}
+
+// bedrockDocumentName returns a unique name using only the characters Converse
+// accepts (letters, digits, spaces, hyphens, parentheses, brackets).
+func bedrockDocumentName(name string, turn, part int) string {
+	name = strings.TrimSuffix(name, ".pdf")
+	clean := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9',
+			r == ' ', r == '-', r == '(', r == ')', r == '[', r == ']':
+			return r
+		}
+		return '-'
+	}, name)
+	if clean == "" {
+		clean = "document"
+	}
+	return fmt.Sprintf("%s-%d-%d", clean, turn, part)
+}
+
+// contentText flattens message content to plain text, dropping attachments.
+func contentText(content any) string {
+	switch c := content.(type) {
+	case nil:
+		return ""
+	case string:
+		return c
+	case []ContentPart:
+		var sb strings.Builder
+		for _, part := range c {
+			if part.Type == "text" {
+				sb.WriteString(part.Text)
+			}
+		}
+		return sb.String()
+	default:
+		return fmt.Sprint(c)
+	}
+}
+
+func (p *BedrockProvider) post(ctx context.Context, req *ProviderRequest, action string) (*http.Response, error) {
+	modelID := p.modelID(req.Model)
+	bReq, err := p.buildRequest(ctx, req, modelID)
+	if err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "failed to build request", Err: err}
+	}
+
+	body, err := json.Marshal(bReq)
+	if err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "failed to marshal request", Err: err}
+	}
+
+	path := "/model/" + awsEscape(modelID) + "/" + action
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.config.BaseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "failed to create request", Err: err}
+	}
+
+	if err := p.setHeaders(ctx, httpReq, body); err != nil {
+		return nil, err
+	}
+
+	if debugOn(ctx) {
+		fmt.Printf("%s [%s] POST %s\n", colorDim("→"), p.Name(), path)
+	}
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "request failed", Err: err}
+	}
+	return resp, nil
+}
+
+// setHeaders authenticates with the Bedrock API key if set, otherwise signs with SigV4.
+func (p *BedrockProvider) setHeaders(ctx context.Context, req *http.Request, body []byte) error {
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range p.config.Headers {
+		req.Header.Set(k, v)
+	}
+
+	if p.config.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.config.APIKey)
+		return nil
+	}
+
+	creds, err := p.creds.Retrieve(ctx)
+	if err != nil {
+		return &ProviderError{Provider: p.Name(), Message: "AWS credentials not available", Err: err}
+	}
+	signAWSRequest(req, body, creds, p.region, "bedrock", time.Now())
+	return nil
+}
+
+func (p *BedrockProvider) httpError(resp *http.Response, body []byte) error {
+	e := newHTTPError(p.Name(), resp, body)
+	e.Message = bedrockErrorMessage(body)
+	if errType := resp.Header.Get("X-Amzn-ErrorType"); errType != "" {
+		// e.g. "ThrottlingException:http://internal.amazon.com/coral/..."
+		errType, _, _ = strings.Cut(errType, ":")
+		e.Kind = classifyError(resp.StatusCode, errType, e.Message)
+		e.Message = errType + ": " + e.Message
+	}
+	return e
+}
+
+func bedrockErrorMessage(body []byte) string {
+	var e struct {
+		Message      string `json:"message"`
+		MessageUpper string `json:"Message"`
+	}
+	if json.Unmarshal(body, &e) == nil {
+		if e.Message != "" {
+			return e.Message
+		}
+		if e.MessageUpper != "" {
+			return e.MessageUpper
+		}
+	}
+	return string(body)
+}
+
+func (p *BedrockProvider) parseResponse(body []byte) (*ProviderResponse, error) {
+	var result struct {
+		Output struct {
+			Message struct {
+				Content []struct {
+					Text    string          `json:"text"`
+					ToolUse *bedrockToolUse `json:"toolUse"`
+				} `json:"content"`
+			} `json:"message"`
+		} `json:"output"`
+		StopReason string       `json:"stopReason"`
+		Usage      bedrockUsage `json:"usage"`
+	}
+
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, &ProviderError{
+			Provider: p.Name(),
+			Message:  fmt.Sprintf("parse error: %v\nBody: %s", err, string(body)),
+		}
+	}
+
+	resp := &ProviderResponse{
+		PromptTokens:     result.Usage.InputTokens,
+		CompletionTokens: result.Usage.OutputTokens,
+		TotalTokens:      result.Usage.TotalTokens,
+		FinishReason:     result.StopReason,
+	}
+	var text strings.Builder
+	for _, block := range result.Output.Message.Content {
+		text.WriteString(block.Text)
+		if tu := block.ToolUse; tu != nil {
+			call := ToolCall{ID: tu.ToolUseID, Type: "function"}
+			call.Function.Name = tu.Name
+			call.Function.Arguments = string(tu.Input)
+			resp.ToolCalls = append(resp.ToolCalls, call)
+		}
+	}
+	resp.Content = text.String()
+	return resp, nil
+}
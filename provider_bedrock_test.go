@@ -0,0 +1,266 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignAWSRequest_Vanilla(t *testing.T) {
+	// "get-vanilla" from the AWS SigV4 test suite.
+	req, _ := http.NewRequest("GET", "https://example.amazonaws.com/", nil)
+	now, _ := time.Parse(awsTimeFormat, "20150830T123600Z")
+	signAWSRequest(req, nil, AWSCredentials{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+	}, "us-east-1", "service", now)
+
+	want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+		"SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Fatalf("unexpected signature:\n got %s\nwant %s", got, want)
+	}
+}
+
+// verifyAWSSignature re-signs the received request and compares signatures.
+func verifyAWSSignature(t *testing.T, r *http.Request, body []byte, creds AWSCredentials, region string) {
+	t.Helper()
+	now, err := time.Parse(awsTimeFormat, r.Header.Get("X-Amz-Date"))
+	if err != nil {
+		t.Errorf("missing X-Amz-Date: %v", err)
+		return
+	}
+	clone := r.Clone(context.Background())
+	clone.Header.Del("Authorization")
+	signAWSRequest(clone, body, creds, region, "bedrock", now)
+	if got, want := r.Header.Get("Authorization"), clone.Header.Get("Authorization"); got != want {
+		t.Errorf("signature mismatch:\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestBedrockProvider_Send(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	creds := AWSCredentials{AccessKeyID: "AKID", SecretAccessKey: "secret", SessionToken: "session"}
+	var gotPath string
+	var gotBody bedrockRequest
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.EscapedPath()
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &gotBody)
+		verifyAWSSignature(t, r, body, creds, "eu-west-1")
+		if r.Header.Get("X-Amz-Security-Token") != "session" {
+			t.Errorf("missing session token")
+		}
+
+		_, _ = w.Write([]byte(`{
+			"output": {"message": {"role": "assistant", "content": [
+				{"text": "Checking."},
+				{"toolUse": {"toolUseId": "t2", "name": "weather", "input": {"city": "Oslo"}}}
+			]}},
+			"stopReason": "tool_use",
+			"usage": {"inputTokens": 12, "outputTokens": 8, "totalTokens": 20}
+		}`))
+	}))
+	defer srv.Close()
+
+	client := Bedrock("eu-west-1", WithBaseURL(srv.URL), WithAWSCredentials(StaticAWSCredentials(creds.AccessKeyID, creds.SecretAccessKey, creds.SessionToken)))
+	call := ToolCall{ID: "t1", Type: "function"}
+	call.Function.Name = "weather"
+	call.Function.Arguments = `{"city":"Bergen"}`
+
+	resp, err := client.provider.Send(context.Background(), &ProviderRequest{
+		Model: string(ModelClaudeSonnet),
+		Messages: []Message{
+			{Role: "system", Content: "Be brief."},
+			{Role: "user", Content: []ContentPart{
+				{Type: "text", Text: "Weather here?"},
+				{Type: "image_url", ImageURL: &ImageURL{URL: "data:image/jpg;base64,aGVsbG8="}},
+				{Type: "document", Document: &DocumentRef{Data: "JVBERi0=", MimeType: "application/pdf", Name: "trip plan.pdf"}},
+			}},
+			{Role: "assistant", ToolCalls: []ToolCall{call}},
+			{Role: "tool", ToolCallID: "t1", Content: "rain"},
+			{Role: "tool", ToolCallID: "t1", Content: "still rain"},
+		},
+		Tools: []Tool{{Type: "function", Function: ToolFunction{Name: "weather", Parameters: Params().String("city", "City", true).Build()}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if gotPath != "/model/eu.anthropic.claude-sonnet-4-5-20250929-v1%3A0/converse" {
+		t.Fatalf("unexpected path %s", gotPath)
+	}
+	if len(gotBody.System) != 1 || gotBody.System[0].Text != "Be brief." {
+		t.Fatalf("unexpected system: %+v", gotBody.System)
+	}
+	if len(gotBody.Messages) != 3 || len(gotBody.Messages[2].Content) != 2 || gotBody.Messages[2].Content[1].ToolResult == nil {
+		t.Fatalf("expected tool results merged into one user turn: %+v", gotBody.Messages)
+	}
+	user := gotBody.Messages[0].Content
+	if user[1].Image == nil || user[1].Image.Format != "jpeg" || user[2].Document == nil || user[2].Document.Name != "trip plan-0-2" {
+		t.Fatalf("unexpected media blocks: %+v", user)
+	}
+	if gotBody.ToolConfig == nil || gotBody.ToolConfig.Tools[0].ToolSpec.Name != "weather" {
+		t.Fatalf("expected tool config, got %+v", gotBody.ToolConfig)
+	}
+
+	if resp.Content != "Checking." || resp.FinishReason != "tool_use" || resp.TotalTokens != 20 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	if len(resp.ToolCalls) != 1 || resp.ToolCalls[0].ID != "t2" || resp.ToolCalls[0].Function.Arguments != `{"city": "Oslo"}` {
+		t.Fatalf("unexpected tool calls: %+v", resp.ToolCalls)
+	}
+}
+
+// encodeEventStreamMessage frames a payload the way Bedrock does.
+func encodeEventStreamMessage(headers map[string]string, payload []byte) []byte {
+	var hdr bytes.Buffer
+	for name, value := range headers {
+		hdr.WriteByte(byte(len(name)))
+		hdr.WriteString(name)
+		hdr.WriteByte(7)
+		_ = binary.Write(&hdr, binary.BigEndian, uint16(len(value)))
+		hdr.WriteString(value)
+	}
+
+	total := uint32(eventStreamPreludeLen + hdr.Len() + len(payload) + 4)
+	var msg bytes.Buffer
+	_ = binary.Write(&msg, binary.BigEndian, total)
+	_ = binary.Write(&msg, binary.BigEndian, uint32(hdr.Len()))
+	_ = binary.Write(&msg, binary.BigEndian, crc32.ChecksumIEEE(msg.Bytes()))
+	msg.Write(hdr.Bytes())
+	msg.Write(payload)
+	_ = binary.Write(&msg, binary.BigEndian, crc32.ChecksumIEEE(msg.Bytes()))
+	return msg.Bytes()
+}
+
+func bedrockEvent(eventType, payload string) []byte {
+	return encodeEventStreamMessage(map[string]string{
+		":message-type": "event",
+		":event-type":   eventType,
+		":content-type": "application/json",
+	}, []byte(payload))
+}
+
+func TestBedrockProvider_SendStream(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/converse-stream") {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer br-key" {
+			t.Errorf("expected Bedrock API key auth, got %q", r.Header.Get("Authorization"))
+		}
+		w.Header().Set("Content-Type", "application/vnd.amazon.eventstream")
+		for _, frame := range [][]byte{
+			bedrockEvent("messageStart", `{"role":"assistant"}`),
+			bedrockEvent("contentBlockDelta", `{"contentBlockIndex":0,"delta":{"text":"Hel"}}`),
+			bedrockEvent("contentBlockDelta", `{"contentBlockIndex":0,"delta":{"text":"lo"}}`),
+			bedrockEvent("contentBlockStart", `{"contentBlockIndex":1,"start":{"toolUse":{"toolUseId":"t1","name":"f"}}}`),
+			bedrockEvent("contentBlockDelta", `{"contentBlockIndex":1,"delta":{"toolUse":{"input":"{\"x\":"}}}`),
+			bedrockEvent("contentBlockDelta", `{"contentBlockIndex":1,"delta":{"toolUse":{"input":"1}"}}}`),
+			bedrockEvent("messageStop", `{"stopReason":"tool_use"}`),
+			bedrockEvent("metadata", `{"usage":{"inputTokens":5,"outputTokens":4,"totalTokens":9},"metrics":{"latencyMs":10}}`),
+		} {
+			_, _ = w.Write(frame)
+		}
+	}))
+	defer srv.Close()
+
+	p := NewBedrockProvider(ProviderConfig{APIKey: "br-key", BaseURL: srv.URL, Region: "us-east-1"})
+	var streamed strings.Builder
+	resp, err := p.SendStream(context.Background(), &ProviderRequest{
+		Model:    "meta.llama3-70b-instruct-v1:0",
+		Messages: []Message{{Role: "user", Content: "hi"}},
+	}, func(s string) { streamed.WriteString(s) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if streamed.String() != "Hello" || resp.Content != "Hello" {
+		t.Fatalf("unexpected content %q", streamed.String())
+	}
+	if len(resp.ToolCalls) != 1 || resp.ToolCalls[0].Function.Arguments != `{"x":1}` {
+		t.Fatalf("unexpected tool calls: %+v", resp.ToolCalls)
+	}
+	if resp.TotalTokens != 9 || resp.FinishReason != "tool_use" {
+		t.Fatalf("unexpected usage: %+v", resp)
+	}
+}
+
+func TestBedrockProvider_StreamException(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(bedrockEvent("contentBlockDelta", `{"contentBlockIndex":0,"delta":{"text":"a"}}`))
+		_, _ = w.Write(encodeEventStreamMessage(map[string]string{
+			":message-type":   "exception",
+			":exception-type": "throttlingException",
+		}, []byte(`{"message":"Too many requests"}`)))
+	}))
+	defer srv.Close()
+
+	p := NewBedrockProvider(ProviderConfig{APIKey: "k", BaseURL: srv.URL})
+	_, err := p.SendStream(context.Background(), &ProviderRequest{Model: "x", Messages: []Message{{Role: "user", Content: "hi"}}}, func(string) {})
+	if err == nil || !strings.Contains(err.Error(), "Too many requests") {
+		t.Fatalf("expected stream exception, got %v", err)
+	}
+
+	corrupt := bedrockEvent("messageStop", `{}`)
+	corrupt[len(corrupt)-1] ^= 0xff
+	if _, err := readEventStreamMessage(bytes.NewReader(corrupt)); err == nil {
+		t.Fatal("expected checksum error")
+	}
+}
+
+func TestEventStream_RejectsOverflowingHeadersLength(t *testing.T) {
+	// 12+4+headersLen wraps to 8 in uint32 arithmetic.
+	frame := make([]byte, 32)
+	binary.BigEndian.PutUint32(frame[0:4], 32)
+	binary.BigEndian.PutUint32(frame[4:8], 0xFFFFFFF8)
+	binary.BigEndian.PutUint32(frame[8:12], crc32.ChecksumIEEE(frame[0:8]))
+	binary.BigEndian.PutUint32(frame[28:], crc32.ChecksumIEEE(frame[:28]))
+
+	if _, err := readEventStreamMessage(bytes.NewReader(frame)); err == nil || !strings.Contains(err.Error(), "invalid frame length") {
+		t.Fatalf("expected invalid frame length, got %v", err)
+	}
+}
+
+func TestAWSSharedConfig(t *testing.T) {
+	dir := t.TempDir()
+	credsFile := filepath.Join(dir, "credentials")
+	configFile := filepath.Join(dir, "config")
+	_ = os.WriteFile(credsFile, []byte("[default]\naws_access_key_id = A\naws_secret_access_key = B\n\n[prod]\naws_access_key_id = PROD\naws_secret_access_key = S\n"), 0o600)
+	_ = os.WriteFile(configFile, []byte("[profile prod]\nregion = ap-southeast-2\n"), 0o600)
+
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", credsFile)
+	t.Setenv("AWS_CONFIG_FILE", configFile)
+	t.Setenv("AWS_PROFILE", "prod")
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_DEFAULT_REGION", "")
+
+	creds, err := DefaultAWSCredentials().Retrieve(context.Background())
+	if err != nil || creds.AccessKeyID != "PROD" {
+		t.Fatalf("expected prod profile credentials, got %+v (%v)", creds, err)
+	}
+	p := NewBedrockProvider(ProviderConfig{})
+	if p.region != "ap-southeast-2" || p.modelID(string(ModelClaudeHaiku)) != "apac.anthropic.claude-haiku-4-5-20251001-v1:0" {
+		t.Fatalf("unexpected region/model: %s %s", p.region, p.modelID(string(ModelClaudeHaiku)))
+	}
+}
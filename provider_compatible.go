@@ -0,0 +1,647 @@
+package ai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// OpenAI-Compatible Provider
+// ═══════════════════════════════════════════════════════════════════════════
+
+// CompatibleConfig describes a host that speaks the Chat Completions wire format.
+type CompatibleConfig struct {
+	Name    string // Provider identifier, e.g. "groq"; also the env prefix (GROQ_API_KEY)
+	BaseURL string // Default endpoint, overridden by ProviderConfig.BaseURL
+
+	AuthHeader  string // Header carrying the key; "" = "Authorization: Bearer <key>"
+	KeyOptional bool   // Local servers that accept unauthenticated requests
+
+	// Models maps catalog IDs to host model IDs. Unmapped models go through
+	// RegisterModel mappings, then are sent as-is.
+	Models map[Model]string
+
+	Capabilities ProviderCapabilities
+
+	// StreamUsage asks for token usage in the final stream chunk (stream_options.include_usage).
+	StreamUsage bool
+}
+
+// OpenAICompatibleProvider implements Provider for any Chat Completions-compatible host.
+type OpenAICompatibleProvider struct {
+	config     ProviderConfig
+	compat     CompatibleConfig
+	httpClient *http.Client
+}
+
+// NewOpenAICompatibleProvider creates a provider for a Chat Completions-compatible host.
+// If the APIKey is empty, it is read from <NAME>_API_KEY; an empty BaseURL is
+// read from <NAME>_BASE_URL, then the CompatibleConfig.
+//
+//	p := ai.NewOpenAICompatibleProvider(ai.ProviderConfig{}, ai.PresetGroq)
+func NewOpenAICompatibleProvider(config ProviderConfig, compat CompatibleConfig) *OpenAICompatibleProvider {
+	if config.BaseURL == "" {
+		config.BaseURL = os.Getenv(providerEnvPrefix(ProviderType(compat.Name)) + "_BASE_URL")
+	}
+	if config.BaseURL == "" {
+		config.BaseURL = compat.BaseURL
+	}
+	config.BaseURL = strings.TrimSuffix(config.BaseURL, "/")
+	if config.APIKey == "" {
+		config.APIKey = os.Getenv(providerEnvPrefix(ProviderType(compat.Name)) + "_API_KEY")
+	}
+	client := http.DefaultClient
+	if config.Timeout > 0 {
+		client = &http.Client{Timeout: config.Timeout}
+	}
+	return &OpenAICompatibleProvider{config: config, compat: compat, httpClient: client}
+}
+
+// Name returns the provider identifier from the CompatibleConfig.
+func (p *OpenAICompatibleProvider) Name() string {
+	return p.compat.Name
+}
+
+// Capabilities reports the features configured for this host.
+func (p *OpenAICompatibleProvider) Capabilities() ProviderCapabilities {
+	return p.compat.Capabilities
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Presets
+// ═══════════════════════════════════════════════════════════════════════════
+
+var (
+	// PresetGroq targets GroqCloud.
+	PresetGroq = CompatibleConfig{
+		Name:    string(ProviderGroq),
+		BaseURL: "https://api.groq.com/openai/v1",
+		Models: map[Model]string{
+			ModelLlama4: "meta-llama/llama-4-maverick-17b-128e-instruct",
+		},
+		Capabilities: ProviderCapabilities{Tools: true, Vision: true, Streaming: true, JSON: true, Thinking: true},
+		StreamUsage:  true,
+	}
+
+	// PresetTogether targets Together AI.
+	PresetTogether = CompatibleConfig{
+		Name:    string(ProviderTogether),
+		BaseURL: "https://api.together.xyz/v1",
+		Models: map[Model]string{
+			ModelLlama4:    "meta-llama/Llama-4-Maverick-17B-128E-Instruct-FP8",
+			ModelQwen3Next: "Qwen/Qwen3-Next-80B-A3B-Instruct",
+		},
+		Capabilities: ProviderCapabilities{Tools: true, Vision: true, Streaming: true, JSON: true},
+		StreamUsage:  true,
+	}
+
+	// PresetDeepSeek targets the DeepSeek platform ("deepseek-chat", "deepseek-reasoner").
+	PresetDeepSeek = CompatibleConfig{
+		Name:         string(ProviderDeepSeek),
+		BaseURL:      "https://api.deepseek.com/v1",
+		Capabilities: ProviderCapabilities{Tools: true, Streaming: true, JSON: true},
+		StreamUsage:  true,
+	}
+
+	// PresetMistral targets La Plateforme. Usage is always sent in the final chunk.
+	PresetMistral = CompatibleConfig{
+		Name:    string(ProviderMistral),
+		BaseURL: "https://api.mistral.ai/v1",
+		Models: map[Model]string{
+			ModelMistralLarge: "mistral-large-latest",
+		},
+		Capabilities: ProviderCapabilities{Tools: true, Vision: true, Streaming: true, JSON: true},
+	}
+
+	// PresetVLLM targets a self-hosted vLLM server (vllm serve).
+	PresetVLLM = CompatibleConfig{
+		Name:         string(ProviderVLLM),
+		BaseURL:      "http://localhost:8000/v1",
+		KeyOptional:  true,
+		Capabilities: ProviderCapabilities{Tools: true, Vision: true, Streaming: true, JSON: true},
+		StreamUsage:  true,
+	}
+
+	// PresetLlamaCpp targets llama.cpp's llama-server (tools need --jinja).
+	PresetLlamaCpp = CompatibleConfig{
+		Name:         string(ProviderLlamaCpp),
+		BaseURL:      "http://localhost:8080/v1",
+		KeyOptional:  true,
+		Capabilities: ProviderCapabilities{Tools: true, Vision: true, Streaming: true, JSON: true},
+		StreamUsage:  true,
+	}
+
+	// PresetLMStudio targets LM Studio's local server. It only supports
+	// json_schema response formats, so JSON mode is off.
+	PresetLMStudio = CompatibleConfig{
+		Name:         string(ProviderLMStudio),
+		BaseURL:      "http://localhost:1234/v1",
+		KeyOptional:  true,
+		Capabilities: ProviderCapabilities{Tools: true, Vision: true, Streaming: true},
+		StreamUsage:  true,
+	}
+)
+
+func init() {
+	for _, preset := range []CompatibleConfig{
+		PresetGroq, PresetTogether, PresetDeepSeek, PresetMistral,
+		PresetVLLM, PresetLlamaCpp, PresetLMStudio,
+	} {
+		preset := preset
+		registerBuiltin(ProviderType(preset.Name), func(c ProviderConfig) Provider {
+			return NewOpenAICompatibleProvider(c, preset)
+		})
+	}
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Send
+// ═══════════════════════════════════════════════════════════════════════════
+
+// Send executes a non-streaming request.
+func (p *OpenAICompatibleProvider) Send(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+	if err := p.checkKey(); err != nil {
+		return nil, err
+	}
+
+	resp, err := p.post(ctx, p.buildRequest(req, false))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "failed to read response", Err: err}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, newHTTPError(p.Name(), resp, respBody)
+	}
+
+	result, err := p.parseResponse(req, respBody)
+	if err != nil {
+		return nil, err
+	}
+	result.RateLimit = parseRateLimitHeaders(resp.Header)
+	return result, nil
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// SendStream
+// ═══════════════════════════════════════════════════════════════════════════
+
+// SendStream executes a streaming request and invokes callback for each chunk.
+// Tool call fragments are reassembled, with or without delta indexes.
+func (p *OpenAICompatibleProvider) SendStream(ctx context.Context, req *ProviderRequest, callback StreamCallback) (*ProviderResponse, error) {
+	if err := p.checkKey(); err != nil {
+		return nil, err
+	}
+
+	resp, err := p.post(ctx, p.buildRequest(req, true))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, newHTTPError(p.Name(), resp, body)
+	}
+
+	var fullContent strings.Builder
+	var calls compatToolCalls
+	var usage *compatUsage
+	var finishReason, model string
+	reader := bufio.NewReader(resp.Body)
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, &ProviderError{Provider: p.Name(), Message: "stream read error", Err: err}
+		}
+		done := err == io.EOF
+
+		line = bytes.TrimSpace(line)
+		// Some servers omit the space after "data:".
+		if data, ok := bytes.CutPrefix(line, []byte("data:")); ok {
+			data = bytes.TrimSpace(data)
+			if string(data) == "[DONE]" {
+				break
+			}
+
+			var chunk struct {
+				Model   string `json:"model"`
+				Choices []struct {
+					Delta struct {
+						Content   compatText       `json:"content"`
+						ToolCalls []compatToolCall `json:"tool_calls"`
+					} `json:"delta"`
+					FinishReason string `json:"finish_reason"`
+				} `json:"choices"`
+				Usage *compatUsage      `json:"usage"`
+				Error *compatErrorField `json:"error"`
+			}
+			if err := json.Unmarshal(data, &chunk); err != nil {
+				continue
+			}
+			if chunk.Error != nil {
+				return nil, &ProviderError{Provider: p.Name(), Code: chunk.Error.Code, Message: chunk.Error.Message}
+			}
+			if chunk.Usage != nil {
+				usage = chunk.Usage
+			}
+			if chunk.Model != "" {
+				model = chunk.Model
+			}
+			if len(chunk.Choices) > 0 {
+				choice := chunk.Choices[0]
+				if content := string(choice.Delta.Content); content != "" {
+					fullContent.WriteString(content)
+					callback(content)
+				}
+				for _, tc := range choice.Delta.ToolCalls {
+					if err := calls.add(tc); err != nil {
+						return nil, &ProviderError{Provider: p.Name(), Message: err.Error()}
+					}
+				}
+				if choice.FinishReason != "" {
+					finishReason = choice.FinishReason
+				}
+			}
+		}
+
+		if done {
+			break
+		}
+	}
+
+	result := p.response(req, fullContent.String(), calls.list(), finishReason, usage)
+	result.Model = model
+	result.RateLimit = parseRateLimitHeaders(resp.Header)
+	return result, nil
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Model Listing
+// ═══════════════════════════════════════════════════════════════════════════
+
+// ListModels lists the models served by the host, namespaced as "<name>/<id>".
+func (p *OpenAICompatibleProvider) ListModels(ctx context.Context) ([]ModelSpec, error) {
+	var result struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := getJSON(ctx, p.httpClient, p.Name(), p.config.BaseURL+"/models", p.setHeaders, &result); err != nil {
+		return nil, err
+	}
+
+	providerType := ProviderType(p.Name())
+	index := catalogIndex(providerType)
+	for id, native := range p.compat.Models {
+		index[native] = id
+	}
+
+	specs := make([]ModelSpec, 0, len(result.Data))
+	for _, m := range result.Data {
+		specs = append(specs, discoveredSpec(index, providerType, p.Name()+"/", m.ID))
+	}
+	return specs, nil
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Internal helpers
+// ═══════════════════════════════════════════════════════════════════════════
+
+type compatRequest struct {
+	Model           string          `json:"model"`
+	Messages        []Message       `json:"messages"`
+	Stream          bool            `json:"stream,omitempty"`
+	StreamOptions   *streamOptions  `json:"stream_options,omitempty"`
+	Temperature     *float64        `json:"temperature,omitempty"`
+	Tools           []Tool          `json:"tools,omitempty"`
+	ToolChoice      any             `json:"tool_choice,omitempty"`
+	ResponseFormat  *ResponseFormat `json:"response_format,omitempty"`
+	MaxTokens       int             `json:"max_tokens,omitempty"`
+	ReasoningEffort string          `json:"reasoning_effort,omitempty"`
+}
+
+type streamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+func (p *OpenAICompatibleProvider) resolve(model string) string {
+	if id, ok := p.compat.Models[Model(model)]; ok {
+		return id
+	}
+	return resolveModel(ProviderType(p.Name()), Model(model))
+}
+
+func (p *OpenAICompatibleProvider) buildRequest(req *ProviderRequest, stream bool) *compatRequest {
+	caps := p.compat.Capabilities
+	cReq := &compatRequest{
+		Model:       p.resolve(req.Model),
+		Messages:    req.Messages,
+		Temperature: req.Temperature,
+		MaxTokens:   req.MaxTokens,
+		Stream:      stream,
+	}
+	if stream && p.compat.StreamUsage {
+		cReq.StreamOptions = &streamOptions{IncludeUsage: true}
+	}
+	if caps.Thinking && req.Thinking != "" {
+		cReq.ReasoningEffort = string(req.Thinking)
+		if req.Thinking == ThinkingMinimal {
+			cReq.ReasoningEffort = string(ThinkingLow)
+		}
+	}
+	if caps.Tools && len(req.Tools) > 0 {
+		cReq.Tools = req.Tools
+		cReq.ToolChoice = "auto"
+	}
+	if caps.JSON && req.JSONMode {
+		cReq.ResponseFormat = &ResponseFormat{Type: "json_object"}
+	}
+	return cReq
+}
+
+func (p *OpenAICompatibleProvider) checkKey() error {
+	if p.config.APIKey == "" && !p.compat.KeyOptional {
+		return &ProviderError{
+			Provider: p.Name(),
+			Message:  providerEnvPrefix(ProviderType(p.Name())) + "_API_KEY not set",
+		}
+	}
+	return nil
+}
+
+func (p *OpenAICompatibleProvider) post(ctx context.Context, cReq *compatRequest) (*http.Response, error) {
+	body, err := json.Marshal(cReq)
+	if err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "failed to marshal request", Err: err}
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.config.BaseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "failed to create request", Err: err}
+	}
+
+	p.setHeaders(httpReq)
+
+	if debugOn(ctx) {
+		suffix := ""
+		if cReq.Stream {
+			suffix = " (stream)"
+		}
+		fmt.Printf("%s [%s] POST %s%s\n", colorDim("→"), p.Name(), "/chat/completions", suffix)
+	}
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "request failed", Err: err}
+	}
+	return resp, nil
+}
+
+func (p *OpenAICompatibleProvider) setHeaders(req *http.Request) {
+	req.Header.Set("Content-Type", "application/json")
+	if p.config.APIKey != "" {
+		if p.compat.AuthHeader == "" {
+			req.Header.Set("Authorization", "Bearer "+p.config.APIKey)
+		} else {
+			req.Header.Set(p.compat.AuthHeader, p.config.APIKey)
+		}
+	}
+
+	for k, v := range p.config.Headers {
+		req.Header.Set(k, v)
+	}
+}
+
+func (p *OpenAICompatibleProvider) parseResponse(req *ProviderRequest, body []byte) (*ProviderResponse, error) {
+	var result struct {
+		Model   string `json:"model"`
+		Choices []struct {
+			Message struct {
+				Content   compatText       `json:"content"`
+				ToolCalls []compatToolCall `json:"tool_calls"`
+			} `json:"message"`
+			FinishReason string `json:"finish_reason"`
+		} `json:"choices"`
+		Usage *compatUsage      `json:"usage"`
+		Error *compatErrorField `json:"error"`
+	}
+
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, &ProviderError{
+			Provider: p.Name(),
+			Message:  fmt.Sprintf("parse error: %v\nBody: %s", err, string(body)),
+		}
+	}
+	if result.Error != nil {
+		return nil, &ProviderError{Provider: p.Name(), Code: result.Error.Code, Message: result.Error.Message}
+	}
+	if len(result.Choices) == 0 {
+		return nil, &ProviderError{Provider: p.Name(), Message: "no response choices"}
+	}
+
+	choice := result.Choices[0]
+	// A complete message lists each call once, so no reassembly is needed.
+	var calls []ToolCall
+	for _, tc := range choice.Message.ToolCalls {
+		call := ToolCall{ID: tc.ID, Type: tc.Type}
+		if call.Type == "" {
+			call.Type = "function"
+		}
+		call.Function.Name = tc.Function.Name
+		call.Function.Arguments = string(tc.Function.Arguments)
+		calls = append(calls, call)
+	}
+	resp := p.response(req, string(choice.Message.Content), calls, choice.FinishReason, result.Usage)
+	resp.Model = result.Model
+	return resp, nil
+}
+
+// response assembles the ProviderResponse, counting tokens locally when the
+// host reports no usage.
+func (p *OpenAICompatibleProvider) response(req *ProviderRequest, content string, calls []ToolCall, finishReason string, usage *compatUsage) *ProviderResponse {
+	resp := &ProviderResponse{Content: content, ToolCalls: calls, FinishReason: finishReason}
+	if usage != nil && (usage.PromptTokens > 0 || usage.CompletionTokens > 0) {
+		resp.PromptTokens = usage.PromptTokens
+		resp.CompletionTokens = usage.CompletionTokens
+		resp.TotalTokens = usage.TotalTokens
+	} else {
+		model := Model(req.Model)
+		resp.PromptTokens = CountMessageTokens(model, req.Messages)
+		resp.CompletionTokens = CountTokens(model, content)
+	}
+	if resp.TotalTokens == 0 {
+		resp.TotalTokens = resp.PromptTokens + resp.CompletionTokens
+	}
+	return resp
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Dialect-tolerant wire types
+// ─────────────────────────────────────────────────────────────────────────────
+
+type compatUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+type compatErrorField struct {
+	Message string
+	Code    string
+}
+
+// UnmarshalJSON accepts {"message": ..., "code": ...} or a bare string.
+func (e *compatErrorField) UnmarshalJSON(b []byte) error {
+	var s string
+	if json.Unmarshal(b, &s) == nil {
+		e.Message = s
+		return nil
+	}
+	var obj struct {
+		Message string          `json:"message"`
+		Code    json.RawMessage `json:"code"`
+	}
+	if err := json.Unmarshal(b, &obj); err != nil {
+		return err
+	}
+	e.Message = obj.Message
+	e.Code = strings.Trim(string(obj.Code), `"`)
+	return nil
+}
+
+// compatErrorMessage extracts a readable message from an error body
+// ({"error": ...}, {"message": ...} or FastAPI's {"detail": ...}).
+func compatErrorMessage(body []byte) string {
+	var shape struct {
+		Error   *compatErrorField `json:"error"`
+		Message string            `json:"message"`
+		Detail  json.RawMessage   `json:"detail"`
+	}
+	if json.Unmarshal(body, &shape) == nil {
+		switch {
+		case shape.Error != nil && shape.Error.Message != "":
+			return shape.Error.Message
+		case shape.Message != "":
+			return shape.Message
+		case len(shape.Detail) > 0:
+			var s string
+			if json.Unmarshal(shape.Detail, &s) == nil {
+				return s
+			}
+			return string(shape.Detail)
+		}
+	}
+	return string(body)
+}
+
+// compatText accepts content as a string, null, or a list of text parts.
+type compatText string
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (t *compatText) UnmarshalJSON(b []byte) error {
+	var s string
+	if json.Unmarshal(b, &s) == nil {
+		*t = compatText(s)
+		return nil
+	}
+	var parts []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	if json.Unmarshal(b, &parts) == nil {
+		var sb strings.Builder
+		for _, part := range parts {
+			if part.Type == "" || part.Type == "text" {
+				sb.WriteString(part.Text)
+			}
+		}
+		*t = compatText(sb.String())
+	}
+	return nil
+}
+
+// compatArgs accepts tool arguments as a JSON string or a raw JSON object.
+type compatArgs string
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (a *compatArgs) UnmarshalJSON(b []byte) error {
+	var s string
+	if json.Unmarshal(b, &s) == nil {
+		*a = compatArgs(s)
+		return nil
+	}
+	if string(b) != "null" {
+		*a = compatArgs(b)
+	}
+	return nil
+}
+
+type compatToolCall struct {
+	Index    *int   `json:"index"`
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Function struct {
+		Name      string     `json:"name"`
+		Arguments compatArgs `json:"arguments"`
+	} `json:"function"`
+}
+
+// compatToolCalls reassembles tool calls from stream deltas. Deltas without
+// an index continue the last call unless they carry a new ID; out-of-range
+// indexes are rejected.
+type compatToolCalls struct {
+	calls []ToolCall
+}
+
+func (c *compatToolCalls) add(tc compatToolCall) error {
+	i := len(c.calls) - 1
+	switch {
+	case tc.Index != nil:
+		i = *tc.Index
+		// Indexes must continue a call or start the next one.
+		if i < 0 || i > len(c.calls) {
+			return fmt.Errorf("invalid tool call index %d", i)
+		}
+		// Some servers send every call at index 0; a new ID starts a new call.
+		if i < len(c.calls) && tc.ID != "" && c.calls[i].ID != "" && c.calls[i].ID != tc.ID {
+			i = len(c.calls)
+		}
+	case tc.ID != "" && (i < 0 || c.calls[i].ID != tc.ID):
+		i = len(c.calls)
+	case i < 0:
+		i = 0
+	}
+	for len(c.calls) <= i {
+		c.calls = append(c.calls, ToolCall{Type: "function"})
+	}
+
+	call := &c.calls[i]
+	if tc.ID != "" {
+		call.ID = tc.ID
+	}
+	if tc.Type != "" {
+		call.Type = tc.Type
+	}
+	if tc.Function.Name != "" {
+		call.Function.Name = tc.Function.Name
+	}
+	call.Function.Arguments += string(tc.Function.Arguments)
+	return nil
+}
+
+func (c *compatToolCalls) list() []ToolCall {
+	return c.calls
+}
@@ -0,0 +1,191 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOpenAICompatible_PresetViaNewClient(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	var gotAuth string
+	var gotBody map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/chat/completions" {
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+		gotAuth = r.Header.Get("Authorization")
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &gotBody)
+		// No usage block: tokens are counted locally.
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"content":"bonjour","tool_calls":[
+			{"id":"c1","type":"function","function":{"name":"lookup","arguments":{"q":"paris"}}}
+		]},"finish_reason":"tool_calls"}]}`))
+	}))
+	defer srv.Close()
+
+	t.Setenv("MISTRAL_API_KEY", "m-key")
+	t.Setenv("MISTRAL_BASE_URL", srv.URL)
+
+	c := NewClient(ProviderMistral)
+	if _, ok := c.provider.(*OpenAICompatibleProvider); !ok {
+		t.Fatalf("expected *OpenAICompatibleProvider, got %T", c.provider)
+	}
+
+	resp, err := c.provider.Send(context.Background(), &ProviderRequest{
+		Model:    string(ModelMistralLarge),
+		Messages: []Message{{Role: "user", Content: "hi"}},
+		JSONMode: true,
+		Thinking: ThinkingHigh,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotAuth != "Bearer m-key" {
+		t.Fatalf("unexpected auth %q", gotAuth)
+	}
+	if gotBody["model"] != "mistral-large-latest" {
+		t.Fatalf("expected preset model mapping, got %v", gotBody["model"])
+	}
+	if _, ok := gotBody["reasoning_effort"]; ok {
+		t.Fatal("reasoning_effort should not be sent to hosts without thinking support")
+	}
+	if gotBody["response_format"] == nil {
+		t.Fatal("expected response_format for JSON mode")
+	}
+	if resp.Content != "bonjour" || len(resp.ToolCalls) != 1 || resp.ToolCalls[0].Function.Arguments != `{"q":"paris"}` {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	if resp.PromptTokens == 0 || resp.CompletionTokens == 0 || resp.TotalTokens != resp.PromptTokens+resp.CompletionTokens {
+		t.Fatalf("expected estimated usage, got %+v", resp)
+	}
+}
+
+func TestOpenAICompatible_StreamToleratesDialects(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	var gotBody map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			t.Errorf("expected no auth header for a keyless local server")
+		}
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &gotBody)
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, strings.Join([]string{
+			`data:{"choices":[{"delta":{"content":"Hel"}}]}`,
+			`data: {"choices":[{"delta":{"content":[{"type":"text","text":"lo"}]}}]}`,
+			// No index: fragments continue the call with the same ID.
+			`data: {"choices":[{"delta":{"tool_calls":[{"id":"a","type":"function","function":{"name":"f","arguments":"{\"x\""}}]}}]}`,
+			`data: {"choices":[{"delta":{"tool_calls":[{"function":{"arguments":":1}"}}]}}]}`,
+			`data: {"choices":[{"delta":{"tool_calls":[{"id":"b","function":{"name":"g","arguments":"{}"}}]},"finish_reason":"tool_calls"}]}`,
+			`data: {"choices":[],"usage":{"prompt_tokens":7,"completion_tokens":3,"total_tokens":10}}`,
+			`data: [DONE]`,
+		}, "\n\n"))
+	}))
+	defer srv.Close()
+
+	p := NewOpenAICompatibleProvider(ProviderConfig{BaseURL: srv.URL + "/"}, PresetVLLM)
+	var streamed strings.Builder
+	resp, err := p.SendStream(context.Background(), &ProviderRequest{
+		Model:    "Qwen/Qwen3-8B",
+		Messages: []Message{{Role: "user", Content: "hi"}},
+	}, func(s string) { streamed.WriteString(s) })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if opts, _ := gotBody["stream_options"].(map[string]any); opts["include_usage"] != true {
+		t.Fatalf("expected stream_options.include_usage, got %v", gotBody["stream_options"])
+	}
+	if streamed.String() != "Hello" || resp.Content != "Hello" {
+		t.Fatalf("unexpected content %q / %q", streamed.String(), resp.Content)
+	}
+	if len(resp.ToolCalls) != 2 || resp.ToolCalls[0].Function.Arguments != `{"x":1}` || resp.ToolCalls[1].Function.Name != "g" {
+		t.Fatalf("unexpected tool calls: %+v", resp.ToolCalls)
+	}
+	if resp.TotalTokens != 10 || resp.FinishReason != "tool_calls" {
+		t.Fatalf("unexpected usage/finish: %+v", resp)
+	}
+}
+
+func TestOpenAICompatible_Errors(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"detail":"model not found"}`))
+	}))
+	defer srv.Close()
+
+	t.Setenv("GROQ_API_KEY", "")
+	p := NewOpenAICompatibleProvider(ProviderConfig{BaseURL: srv.URL}, PresetGroq)
+	if _, err := p.Send(context.Background(), &ProviderRequest{Model: "x"}); err == nil || !strings.Contains(err.Error(), "GROQ_API_KEY") {
+		t.Fatalf("expected missing key error, got %v", err)
+	}
+
+	p = NewOpenAICompatibleProvider(ProviderConfig{APIKey: "k", BaseURL: srv.URL}, PresetGroq)
+	_, err := p.Send(context.Background(), &ProviderRequest{Model: "x"})
+	var perr *ProviderError
+	if !errors.As(err, &perr) || perr.Code != "400" || perr.Message != "model not found" {
+		t.Fatalf("expected detail message, got %v", err)
+	}
+}
+
+func TestOpenAICompatible_RejectsBadToolCallIndex(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	for _, index := range []int{-1, 5} {
+		var calls compatToolCalls
+		if err := calls.add(compatToolCall{Index: &index, ID: "a"}); err == nil {
+			t.Fatalf("expected index %d to be rejected", index)
+		}
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, `data: {"choices":[{"delta":{"tool_calls":[{"index":1000000000,"id":"a","function":{"name":"f"}}]}}]}`+"\n\n")
+	}))
+	defer srv.Close()
+
+	p := NewOpenAICompatibleProvider(ProviderConfig{BaseURL: srv.URL}, PresetVLLM)
+	_, err := p.SendStream(context.Background(), &ProviderRequest{Model: "m"}, func(string) {})
+	var perr *ProviderError
+	if !errors.As(err, &perr) || !strings.Contains(perr.Message, "tool call index") {
+		t.Fatalf("expected invalid index error, got %v", err)
+	}
+}
+
+func TestOpenAICompatible_CompleteToolCallsStaySeparate(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"tool_calls":[
+			{"function":{"name":"f","arguments":"{\"x\":1}"}},
+			{"function":{"name":"g","arguments":{"y":2}}},
+			{"index":7,"id":"c","function":{"name":"h","arguments":"{}"}}
+		]},"finish_reason":"tool_calls"}]}`))
+	}))
+	defer srv.Close()
+
+	p := NewOpenAICompatibleProvider(ProviderConfig{BaseURL: srv.URL}, PresetVLLM)
+	resp, err := p.Send(context.Background(), &ProviderRequest{Model: "m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.ToolCalls) != 3 || resp.ToolCalls[0].Function.Arguments != `{"x":1}` ||
+		resp.ToolCalls[1].Function.Arguments != `{"y":2}` || resp.ToolCalls[2].ID != "c" || resp.ToolCalls[1].Type != "function" {
+		t.Fatalf("expected three separate calls, got %+v", resp.ToolCalls)
+	}
+}
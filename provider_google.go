@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 )
 
@@ -17,24 +18,45 @@ import (
 
 const googleBaseURL = "https://generativelanguage.googleapis.com/v1beta"
 
-// GoogleProvider implements Provider for Google's Gemini API.
+// GoogleProvider implements Provider for Google's Gemini API, or for
+// Vertex AI when a project is configured.
 type GoogleProvider struct {
 	config     ProviderConfig
 	httpClient *http.Client
+
+	// Vertex AI mode: OAuth2 auth and project/location endpoints.
+	// Claude models are routed to Anthropic-on-Vertex.
+	vertex *vertexAI
+	claude *AnthropicProvider
 }
 
-// NewGoogleProvider creates a GoogleProvider.
+// NewGoogleProvider creates a GoogleProvider. Setting config.Project (or
+// GOOGLE_GENAI_USE_VERTEXAI=true with GOOGLE_CLOUD_PROJECT) switches to
+// Vertex AI, authenticated by config.TokenSource or the service-account key
+// in GOOGLE_APPLICATION_CREDENTIALS.
 func NewGoogleProvider(config ProviderConfig) *GoogleProvider {
+	client := http.DefaultClient
+	if config.Timeout > 0 {
+		client = &http.Client{Timeout: config.Timeout}
+	}
+
+	if project := vertexProject(config); project != "" {
+		vertex := newVertexAI(config, project)
+		config.BaseURL = vertex.baseURL
+		return &GoogleProvider{
+			config:     config,
+			httpClient: client,
+			vertex:     vertex,
+			claude:     &AnthropicProvider{config: config, httpClient: client, vertex: vertex},
+		}
+	}
+
 	if config.BaseURL == "" {
 		config.BaseURL = googleBaseURL
 	}
 	if config.APIKey == "" {
 		config.APIKey = getEnvWithFallback("GOOGLE_API_KEY", "GEMINI_API_KEY")
 	}
-	client := http.DefaultClient
-	if config.Timeout > 0 {
-		client = &http.Client{Timeout: config.Timeout}
-	}
 	return &GoogleProvider{config: config, httpClient: client}
 }
 
@@ -61,14 +83,20 @@ func (p *GoogleProvider) Capabilities() ProviderCapabilities {
 
 // Send executes a non-streaming request.
 func (p *GoogleProvider) Send(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
-	if p.config.APIKey == "" {
+	if p.claude != nil && vendorForModel(Model(req.Model)) == ProviderAnthropic {
+		return p.claude.Send(ctx, req)
+	}
+	if p.vertex == nil && p.config.APIKey == "" {
 		return nil, &ProviderError{
 			Provider: p.Name(),
 			Message:  "GOOGLE_API_KEY or GEMINI_API_KEY not set",
 		}
 	}
 
-	geminiReq := p.buildRequest(req)
+	geminiReq, err := p.buildRequest(ctx, req)
+	if err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "failed to build request", Err: err}
+	}
 	model := resolveModel(ProviderGoogle, Model(req.Model))
 
 	body, err := json.Marshal(geminiReq)
@@ -76,15 +104,16 @@ func (p *GoogleProvider) Send(ctx context.Context, req *ProviderRequest) (*Provi
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to marshal request", Err: err}
 	}
 
-	url := fmt.Sprintf("%s/models/%s:generateContent?key=%s", p.config.BaseURL, model, p.config.APIKey)
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.endpoint(model, false), bytes.NewReader(body))
 	if err != nil {
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to create request", Err: err}
 	}
 
-	p.setHeaders(httpReq)
+	if err := p.authorize(ctx, httpReq); err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "failed to get access token", Err: err}
+	}
 
-	if Debug {
+	if debugOn(ctx) {
 		fmt.Printf("%s [%s] POST /models/%s:generateContent\n", colorDim("→"), p.Name(), model)
 	}
 
@@ -98,6 +127,9 @@ func (p *GoogleProvider) Send(ctx context.Context, req *ProviderRequest) (*Provi
 	if err != nil {
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to read response", Err: err}
 	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, newHTTPError(p.Name(), resp, respBody)
+	}
 
 	return p.parseResponse(respBody)
 }
@@ -108,14 +140,20 @@ func (p *GoogleProvider) Send(ctx context.Context, req *ProviderRequest) (*Provi
 
 // SendStream executes a streaming request and invokes callback for each chunk.
 func (p *GoogleProvider) SendStream(ctx context.Context, req *ProviderRequest, callback StreamCallback) (*ProviderResponse, error) {
-	if p.config.APIKey == "" {
+	if p.claude != nil && vendorForModel(Model(req.Model)) == ProviderAnthropic {
+		return p.claude.SendStream(ctx, req, callback)
+	}
+	if p.vertex == nil && p.config.APIKey == "" {
 		return nil, &ProviderError{
 			Provider: p.Name(),
 			Message:  "GOOGLE_API_KEY or GEMINI_API_KEY not set",
 		}
 	}
 
-	geminiReq := p.buildRequest(req)
+	geminiReq, err := p.buildRequest(ctx, req)
+	if err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "failed to build request", Err: err}
+	}
 	model := resolveModel(ProviderGoogle, Model(req.Model))
 
 	body, err := json.Marshal(geminiReq)
@@ -123,15 +161,16 @@ func (p *GoogleProvider) SendStream(ctx context.Context, req *ProviderRequest, c
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to marshal request", Err: err}
 	}
 
-	url := fmt.Sprintf("%s/models/%s:streamGenerateContent?alt=sse&key=%s", p.config.BaseURL, model, p.config.APIKey)
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.endpoint(model, true), bytes.NewReader(body))
 	if err != nil {
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to create request", Err: err}
 	}
 
-	p.setHeaders(httpReq)
+	if err := p.authorize(ctx, httpReq); err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "failed to get access token", Err: err}
+	}
 
-	if Debug {
+	if debugOn(ctx) {
 		fmt.Printf("%s [%s] POST /models/%s:streamGenerateContent (stream)\n", colorDim("→"), p.Name(), model)
 	}
 
@@ -143,14 +182,11 @@ func (p *GoogleProvider) SendStream(ctx context.Context, req *ProviderRequest, c
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, &ProviderError{
-			Provider: p.Name(),
-			Code:     fmt.Sprintf("%d", resp.StatusCode),
-			Message:  string(body),
-		}
+		return nil, newHTTPError(p.Name(), resp, body)
 	}
 
 	var fullContent strings.Builder
+	var modelVersion string
 	reader := bufio.NewReader(resp.Body)
 
 	for {
@@ -170,7 +206,8 @@ func (p *GoogleProvider) SendStream(ctx context.Context, req *ProviderRequest, c
 		data := bytes.TrimPrefix(line, []byte("data: "))
 
 		var chunk struct {
-			Candidates []struct {
+			ModelVersion string `json:"modelVersion"`
+			Candidates   []struct {
 				Content struct {
 					Parts []struct {
 						Text string `json:"text"`
@@ -182,6 +219,9 @@ func (p *GoogleProvider) SendStream(ctx context.Context, req *ProviderRequest, c
 		if err := json.Unmarshal(data, &chunk); err != nil {
 			continue
 		}
+		if chunk.ModelVersion != "" {
+			modelVersion = chunk.ModelVersion
+		}
 
 		if len(chunk.Candidates) > 0 && len(chunk.Candidates[0].Content.Parts) > 0 {
 			text := chunk.Candidates[0].Content.Parts[0].Text
@@ -194,6 +234,7 @@ func (p *GoogleProvider) SendStream(ctx context.Context, req *ProviderRequest, c
 
 	return &ProviderResponse{
 		Content:          fullContent.String(),
+		Model:            modelVersion,
 		CompletionTokens: completionTokens,
 		TotalTokens:      completionTokens,
 	}, nil
@@ -228,7 +269,7 @@ type geminiInline struct {
 }
 
 type geminiFileData struct {
-	MimeType string `json:"mimeType"`
+	MimeType string `json:"mimeType,omitempty"`
 	FileURI  string `json:"fileUri"`
 }
 
@@ -236,6 +277,7 @@ type geminiGenerateConfig struct {
 	Temperature      *float64              `json:"temperature,omitempty"`
 	ResponseMimeType string                `json:"responseMimeType,omitempty"`
 	ThinkingConfig   *geminiThinkingConfig `json:"thinkingConfig,omitempty"`
+	MaxOutputTokens  int                   `json:"maxOutputTokens,omitempty"`
 }
 
 type geminiThinkingConfig struct {
@@ -255,7 +297,7 @@ type geminiFunctionDecl struct {
 	Parameters  map[string]any `json:"parameters,omitempty"`
 }
 
-func (p *GoogleProvider) buildRequest(req *ProviderRequest) *geminiRequest {
+func (p *GoogleProvider) buildRequest(ctx context.Context, req *ProviderRequest) (*geminiRequest, error) {
 	geminiReq := &geminiRequest{
 		Contents: []geminiContent{},
 	}
@@ -290,20 +332,11 @@ func (p *GoogleProvider) buildRequest(req *ProviderRequest) *geminiRequest {
 					parts = append(parts, geminiPart{Text: part.Text})
 				case "image_url":
 					if part.ImageURL != nil {
-						url := part.ImageURL.URL
-						if strings.HasPrefix(url, "data:") {
-							// Parse data URI
-							dataParts := strings.SplitN(url, ",", 2)
-							if len(dataParts) == 2 {
-								mimeType := strings.TrimPrefix(strings.Split(dataParts[0], ";")[0], "data:")
-								parts = append(parts, geminiPart{
-									InlineData: &geminiInline{
-										MimeType: mimeType,
-										Data:     dataParts[1],
-									},
-								})
-							}
+						gp, err := p.mediaPart(ctx, part.ImageURL.URL, "")
+						if err != nil {
+							return nil, err
 						}
+						parts = append(parts, gp)
 					}
 				case "document":
 					if part.Document != nil {
@@ -317,13 +350,11 @@ func (p *GoogleProvider) buildRequest(req *ProviderRequest) *geminiRequest {
 								},
 							})
 						} else if doc.URL != "" {
-							// File URI (for Google Cloud Storage)
-							parts = append(parts, geminiPart{
-								FileData: &geminiFileData{
-									MimeType: doc.MimeType,
-									FileURI:  doc.URL,
-								},
-							})
+							gp, err := p.mediaPart(ctx, doc.URL, doc.MimeType)
+							if err != nil {
+								return nil, err
+							}
+							parts = append(parts, gp)
 						}
 					}
 				}
@@ -344,6 +375,9 @@ func (p *GoogleProvider) buildRequest(req *ProviderRequest) *geminiRequest {
 	if req.Temperature != nil {
 		geminiReq.GenerationConfig.Temperature = req.Temperature
 	}
+	if req.MaxTokens > 0 {
+		geminiReq.GenerationConfig.MaxOutputTokens = req.MaxTokens
+	}
 
 	if req.JSONMode {
 		geminiReq.GenerationConfig.ResponseMimeType = "application/json"
@@ -369,7 +403,61 @@ func (p *GoogleProvider) buildRequest(req *ProviderRequest) *geminiRequest {
 		geminiReq.Tools = []geminiTool{{FunctionDeclarations: funcs}}
 	}
 
-	return geminiReq
+	return geminiReq, nil
+}
+
+// mediaPart converts an image or document reference into a Gemini part.
+// Data URIs are inlined, URIs Gemini can read itself (Files API, gs://, YouTube)
+// become fileData, and any other http(s) URL is fetched and inlined.
+func (p *GoogleProvider) mediaPart(ctx context.Context, url, mimeType string) (geminiPart, error) {
+	if mt, data, ok := splitDataURI(url); ok {
+		return geminiPart{InlineData: &geminiInline{MimeType: mt, Data: data}}, nil
+	}
+	if isGeminiFileURI(url) {
+		return geminiPart{FileData: &geminiFileData{MimeType: mimeType, FileURI: url}}, nil
+	}
+	data, fetchedType, err := mediaFetcher(p.config).FetchBase64(ctx, url)
+	if err != nil {
+		return geminiPart{}, err
+	}
+	if mimeType == "" {
+		mimeType = fetchedType
+	}
+	return geminiPart{InlineData: &geminiInline{MimeType: mimeType, Data: data}}, nil
+}
+
+// isGeminiFileURI reports whether Gemini can resolve the URI server-side.
+func isGeminiFileURI(url string) bool {
+	return strings.HasPrefix(url, "gs://") ||
+		strings.HasPrefix(url, "https://generativelanguage.googleapis.com/") ||
+		strings.HasPrefix(url, "https://www.youtube.com/") ||
+		strings.HasPrefix(url, "https://youtube.com/") ||
+		strings.HasPrefix(url, "https://youtu.be/")
+}
+
+// endpoint returns the (streaming) generateContent URL for a native model ID.
+func (p *GoogleProvider) endpoint(model string, stream bool) string {
+	method := "generateContent"
+	if stream {
+		method = "streamGenerateContent?alt=sse"
+	}
+	if p.vertex != nil {
+		return p.vertex.modelURL("google", model, method)
+	}
+	sep := "?"
+	if stream {
+		sep = "&"
+	}
+	return fmt.Sprintf("%s/models/%s:%s%skey=%s", p.config.BaseURL, model, method, sep, p.config.APIKey)
+}
+
+// authorize sets the request headers and, in Vertex AI mode, the bearer token.
+func (p *GoogleProvider) authorize(ctx context.Context, req *http.Request) error {
+	if p.vertex != nil {
+		return p.vertex.authorize(ctx, req)
+	}
+	p.setHeaders(req)
+	return nil
 }
 
 func (p *GoogleProvider) setHeaders(req *http.Request) {
@@ -382,7 +470,8 @@ func (p *GoogleProvider) setHeaders(req *http.Request) {
 
 func (p *GoogleProvider) parseResponse(body []byte) (*ProviderResponse, error) {
 	var result struct {
-		Candidates []struct {
+		ModelVersion string `json:"modelVersion"`
+		Candidates   []struct {
 			Content struct {
 				Parts []struct {
 					Text         string `json:"text,omitempty"`
@@ -457,9 +546,81 @@ func (p *GoogleProvider) parseResponse(body []byte) (*ProviderResponse, error) {
 	return &ProviderResponse{
 		Content:          content.String(),
 		ToolCalls:        toolCalls,
+		Model:            result.ModelVersion,
 		PromptTokens:     result.UsageMetadata.PromptTokenCount,
 		CompletionTokens: result.UsageMetadata.CandidatesTokenCount,
 		TotalTokens:      result.UsageMetadata.TotalTokenCount,
 		FinishReason:     candidate.FinishReason,
 	}, nil
 }
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Model Listing
+// ═══════════════════════════════════════════════════════════════════════════
+
+// ListModels lists the models that support generateContent, with their token limits.
+func (p *GoogleProvider) ListModels(ctx context.Context) ([]ModelSpec, error) {
+	if p.vertex != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "model listing is not supported in Vertex AI mode"}
+	}
+	index := catalogIndex(ProviderGoogle)
+	var specs []ModelSpec
+
+	pageToken := ""
+	for {
+		endpoint := fmt.Sprintf("%s/models?pageSize=1000&key=%s", p.config.BaseURL, url.QueryEscape(p.config.APIKey))
+		if pageToken != "" {
+			endpoint += "&pageToken=" + url.QueryEscape(pageToken)
+		}
+
+		var page struct {
+			Models []struct {
+				Name                       string   `json:"name"`
+				DisplayName                string   `json:"displayName"`
+				Description                string   `json:"description"`
+				InputTokenLimit            int      `json:"inputTokenLimit"`
+				OutputTokenLimit           int      `json:"outputTokenLimit"`
+				SupportedGenerationMethods []string `json:"supportedGenerationMethods"`
+				Thinking                   bool     `json:"thinking"`
+			} `json:"models"`
+			NextPageToken string `json:"nextPageToken"`
+		}
+		if err := getJSON(ctx, p.httpClient, p.Name(), endpoint, p.setHeaders, &page); err != nil {
+			return nil, err
+		}
+
+		for _, m := range page.Models {
+			if !containsString(m.SupportedGenerationMethods, "generateContent") {
+				continue
+			}
+			native := strings.TrimPrefix(m.Name, "models/")
+			spec := discoveredSpec(index, ProviderGoogle, "google/", native)
+			spec.Name = m.DisplayName
+			spec.Provider = "Google"
+			spec.Description = m.Description
+			spec.ContextWindow = m.InputTokenLimit
+			spec.MaxOutput = m.OutputTokenLimit
+			spec.Thinking = m.Thinking
+			if strings.HasPrefix(native, "gemini-") {
+				spec.InputModalities = inMultimodal
+				spec.Tools = true
+				spec.JSON = true
+			}
+			specs = append(specs, spec)
+		}
+
+		if page.NextPageToken == "" {
+			return specs, nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -51,3 +51,21 @@ func TestResolveModel_OpenRouter_ClaudeDefaults(t *testing.T) {
 		t.Fatalf("expected %q, got %q", "anthropic/claude-haiku-4.5", got)
 	}
 }
+
+func TestResolveModel_StripsVendorNamespaces(t *testing.T) {
+	tests := []struct {
+		provider ProviderType
+		in       Model
+		want     string
+	}{
+		{ProviderMistral, "mistralai/mistral-large-latest", "mistral-large-latest"},
+		{ProviderMistral, "mistral-small-latest", "mistral-small-latest"},
+		{ProviderDeepSeek, "deepseek/deepseek-chat", "deepseek-chat"},
+		{ProviderDeepSeek, "deepseek-reasoner", "deepseek-reasoner"},
+	}
+	for _, tt := range tests {
+		if got := resolveModel(tt.provider, tt.in); got != tt.want {
+			t.Errorf("resolveModel(%s, %q) = %q, want %q", tt.provider, tt.in, got, tt.want)
+		}
+	}
+}
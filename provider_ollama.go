@@ -58,7 +58,10 @@ func (p *OllamaProvider) Capabilities() ProviderCapabilities {
 
 // Send executes a non-streaming request.
 func (p *OllamaProvider) Send(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
-	ollamaReq := p.buildRequest(req)
+	ollamaReq, err := p.buildRequest(ctx, req)
+	if err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "failed to build request", Err: err}
+	}
 
 	body, err := json.Marshal(ollamaReq)
 	if err != nil {
@@ -72,7 +75,7 @@ func (p *OllamaProvider) Send(ctx context.Context, req *ProviderRequest) (*Provi
 
 	p.setHeaders(httpReq)
 
-	if Debug {
+	if debugOn(ctx) {
 		fmt.Printf("%s [%s] POST %s (model: %s)\n", colorDim("→"), p.Name(), "/api/chat", req.Model)
 	}
 
@@ -90,6 +93,9 @@ func (p *OllamaProvider) Send(ctx context.Context, req *ProviderRequest) (*Provi
 	if err != nil {
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to read response", Err: err}
 	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, newHTTPError(p.Name(), resp, respBody)
+	}
 
 	return p.parseResponse(respBody)
 }
@@ -100,7 +106,10 @@ func (p *OllamaProvider) Send(ctx context.Context, req *ProviderRequest) (*Provi
 
 // SendStream executes a streaming request and invokes callback for each chunk.
 func (p *OllamaProvider) SendStream(ctx context.Context, req *ProviderRequest, callback StreamCallback) (*ProviderResponse, error) {
-	ollamaReq := p.buildRequest(req)
+	ollamaReq, err := p.buildRequest(ctx, req)
+	if err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "failed to build request", Err: err}
+	}
 	ollamaReq.Stream = true
 
 	body, err := json.Marshal(ollamaReq)
@@ -115,7 +124,7 @@ func (p *OllamaProvider) SendStream(ctx context.Context, req *ProviderRequest, c
 
 	p.setHeaders(httpReq)
 
-	if Debug {
+	if debugOn(ctx) {
 		fmt.Printf("%s [%s] POST %s (stream, model: %s)\n", colorDim("→"), p.Name(), "/api/chat", req.Model)
 	}
 
@@ -131,15 +140,12 @@ func (p *OllamaProvider) SendStream(ctx context.Context, req *ProviderRequest, c
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, &ProviderError{
-			Provider: p.Name(),
-			Code:     fmt.Sprintf("%d", resp.StatusCode),
-			Message:  string(body),
-		}
+		return nil, newHTTPError(p.Name(), resp, body)
 	}
 
 	var fullContent strings.Builder
 	var promptTokens, completionTokens int
+	var model string
 
 	reader := bufio.NewReader(resp.Body)
 
@@ -172,6 +178,10 @@ func (p *OllamaProvider) SendStream(ctx context.Context, req *ProviderRequest, c
 			callback(chunk.Message.Content)
 		}
 
+		if chunk.Model != "" {
+			model = chunk.Model
+		}
+
 		if chunk.Done {
 			promptTokens = chunk.PromptEvalCount
 			completionTokens = chunk.EvalCount
@@ -181,6 +191,7 @@ func (p *OllamaProvider) SendStream(ctx context.Context, req *ProviderRequest, c
 
 	return &ProviderResponse{
 		Content:          fullContent.String(),
+		Model:            model,
 		PromptTokens:     promptTokens,
 		CompletionTokens: completionTokens,
 		TotalTokens:      promptTokens + completionTokens,
@@ -208,6 +219,7 @@ type ollamaMessage struct {
 
 type ollamaOptions struct {
 	Temperature float64 `json:"temperature,omitempty"`
+	NumPredict  int     `json:"num_predict,omitempty"`
 }
 
 type ollamaTool struct {
@@ -219,7 +231,7 @@ type ollamaTool struct {
 	} `json:"function"`
 }
 
-func (p *OllamaProvider) buildRequest(req *ProviderRequest) *ollamaRequest {
+func (p *OllamaProvider) buildRequest(ctx context.Context, req *ProviderRequest) (*ollamaRequest, error) {
 	// Convert messages to Ollama format
 	var messages []ollamaMessage
 
@@ -239,14 +251,19 @@ func (p *OllamaProvider) buildRequest(req *ProviderRequest) *ollamaRequest {
 				if part.Type == "text" {
 					textParts = append(textParts, part.Text)
 				} else if part.Type == "image_url" && part.ImageURL != nil {
-					// Extract base64 from data URI
+					// Ollama only accepts raw base64, so remote images are fetched
 					url := part.ImageURL.URL
-					if strings.HasPrefix(url, "data:") {
-						parts := strings.SplitN(url, ",", 2)
-						if len(parts) == 2 {
-							ollamaMsg.Images = append(ollamaMsg.Images, parts[1])
+					if _, data, ok := splitDataURI(url); ok {
+						ollamaMsg.Images = append(ollamaMsg.Images, data)
+					} else {
+						data, _, err := mediaFetcher(p.config).FetchBase64(ctx, url)
+						if err != nil {
+							return nil, err
 						}
+						ollamaMsg.Images = append(ollamaMsg.Images, data)
 					}
+				} else if part.Type == "document" && part.Document != nil {
+					return nil, fmt.Errorf("ollama does not support document input")
 				}
 			}
 			ollamaMsg.Content = strings.Join(textParts, "\n")
@@ -261,9 +278,10 @@ func (p *OllamaProvider) buildRequest(req *ProviderRequest) *ollamaRequest {
 		Stream:   false, // Set in SendStream
 	}
 
-	if req.Temperature != nil {
-		ollamaReq.Options = &ollamaOptions{
-			Temperature: *req.Temperature,
+	if req.Temperature != nil || req.MaxTokens > 0 {
+		ollamaReq.Options = &ollamaOptions{NumPredict: req.MaxTokens}
+		if req.Temperature != nil {
+			ollamaReq.Options.Temperature = *req.Temperature
 		}
 	}
 
@@ -289,7 +307,7 @@ func (p *OllamaProvider) buildRequest(req *ProviderRequest) *ollamaRequest {
 		}
 	}
 
-	return ollamaReq
+	return ollamaReq, nil
 }
 
 func (p *OllamaProvider) setHeaders(req *http.Request) {
@@ -358,8 +376,41 @@ func (p *OllamaProvider) parseResponse(body []byte) (*ProviderResponse, error) {
 	return &ProviderResponse{
 		Content:          result.Message.Content,
 		ToolCalls:        toolCalls,
+		Model:            result.Model,
 		PromptTokens:     result.PromptEvalCount,
 		CompletionTokens: result.EvalCount,
 		TotalTokens:      result.PromptEvalCount + result.EvalCount,
 	}, nil
 }
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Model Listing
+// ═══════════════════════════════════════════════════════════════════════════
+
+// ListModels lists the models pulled into the local Ollama instance.
+func (p *OllamaProvider) ListModels(ctx context.Context) ([]ModelSpec, error) {
+	var result struct {
+		Models []struct {
+			Name    string `json:"name"`
+			Details struct {
+				Family            string `json:"family"`
+				ParameterSize     string `json:"parameter_size"`
+				QuantizationLevel string `json:"quantization_level"`
+			} `json:"details"`
+		} `json:"models"`
+	}
+	if err := getJSON(ctx, p.httpClient, p.Name(), p.config.BaseURL+"/api/tags", p.setHeaders, &result); err != nil {
+		return nil, err
+	}
+
+	specs := make([]ModelSpec, 0, len(result.Models))
+	for _, m := range result.Models {
+		d := m.Details
+		specs = append(specs, ModelSpec{
+			ID:          Model(m.Name),
+			Provider:    "Ollama",
+			Description: strings.TrimSpace(strings.Join([]string{d.Family, d.ParameterSize, d.QuantizationLevel}, " ")),
+		})
+	}
+	return specs, nil
+}
@@ -22,6 +22,7 @@ const openAIBaseURL = "https://api.openai.com/v1"
 type OpenAIProvider struct {
 	config     ProviderConfig
 	httpClient *http.Client
+	azure      *azureTarget // set when serving Azure OpenAI deployments (see AzureProvider)
 }
 
 // NewOpenAIProvider creates an OpenAIProvider.
@@ -39,8 +40,11 @@ func NewOpenAIProvider(config ProviderConfig) *OpenAIProvider {
 	return &OpenAIProvider{config: config, httpClient: client}
 }
 
-// Name returns the provider identifier ("openai").
+// Name returns the provider identifier ("openai", or "azure" for Azure deployments).
 func (p *OpenAIProvider) Name() string {
+	if p.azure != nil {
+		return "azure"
+	}
 	return "openai"
 }
 
@@ -74,11 +78,8 @@ func (p *OpenAIProvider) Capabilities() ProviderCapabilities {
 
 // Send executes a non-streaming request.
 func (p *OpenAIProvider) Send(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
-	if p.config.APIKey == "" {
-		return nil, &ProviderError{
-			Provider: p.Name(),
-			Message:  "OPENAI_API_KEY not set",
-		}
+	if err := p.checkKey(); err != nil {
+		return nil, err
 	}
 
 	// Use Responses API when built-in tools are present
@@ -93,14 +94,16 @@ func (p *OpenAIProvider) Send(ctx context.Context, req *ProviderRequest) (*Provi
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to marshal request", Err: err}
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.config.BaseURL+"/chat/completions", bytes.NewReader(body))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.url("/chat/completions", req.Model), bytes.NewReader(body))
 	if err != nil {
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to create request", Err: err}
 	}
 
-	p.setHeaders(httpReq)
+	if err := p.authorize(ctx, httpReq); err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "failed to get access token", Err: err}
+	}
 
-	if Debug {
+	if debugOn(ctx) {
 		fmt.Printf("%s [%s] POST %s\n", colorDim("→"), p.Name(), "/chat/completions")
 	}
 
@@ -114,8 +117,16 @@ func (p *OpenAIProvider) Send(ctx context.Context, req *ProviderRequest) (*Provi
 	if err != nil {
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to read response", Err: err}
 	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, newHTTPError(p.Name(), resp, respBody)
+	}
 
-	return p.parseResponse(respBody)
+	result, err := p.parseResponse(respBody)
+	if err != nil {
+		return nil, err
+	}
+	result.RateLimit = parseRateLimitHeaders(resp.Header)
+	return result, nil
 }
 
 // ═══════════════════════════════════════════════════════════════════════════
@@ -124,11 +135,8 @@ func (p *OpenAIProvider) Send(ctx context.Context, req *ProviderRequest) (*Provi
 
 // SendStream executes a streaming request and invokes callback for each chunk.
 func (p *OpenAIProvider) SendStream(ctx context.Context, req *ProviderRequest, callback StreamCallback) (*ProviderResponse, error) {
-	if p.config.APIKey == "" {
-		return nil, &ProviderError{
-			Provider: p.Name(),
-			Message:  "OPENAI_API_KEY not set",
-		}
+	if err := p.checkKey(); err != nil {
+		return nil, err
 	}
 
 	oaiReq := p.buildRequest(req)
@@ -139,14 +147,16 @@ func (p *OpenAIProvider) SendStream(ctx context.Context, req *ProviderRequest, c
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to marshal request", Err: err}
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.config.BaseURL+"/chat/completions", bytes.NewReader(body))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.url("/chat/completions", req.Model), bytes.NewReader(body))
 	if err != nil {
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to create request", Err: err}
 	}
 
-	p.setHeaders(httpReq)
+	if err := p.authorize(ctx, httpReq); err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "failed to get access token", Err: err}
+	}
 
-	if Debug {
+	if debugOn(ctx) {
 		fmt.Printf("%s [%s] POST %s (stream)\n", colorDim("→"), p.Name(), "/chat/completions")
 	}
 
@@ -158,14 +168,11 @@ func (p *OpenAIProvider) SendStream(ctx context.Context, req *ProviderRequest, c
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, &ProviderError{
-			Provider: p.Name(),
-			Code:     fmt.Sprintf("%d", resp.StatusCode),
-			Message:  string(body),
-		}
+		return nil, newHTTPError(p.Name(), resp, body)
 	}
 
 	var fullContent strings.Builder
+	var model string
 	reader := bufio.NewReader(resp.Body)
 
 	for {
@@ -188,6 +195,7 @@ func (p *OpenAIProvider) SendStream(ctx context.Context, req *ProviderRequest, c
 		}
 
 		var chunk struct {
+			Model   string `json:"model"`
 			Choices []struct {
 				Delta struct {
 					Content string `json:"content"`
@@ -198,6 +206,9 @@ func (p *OpenAIProvider) SendStream(ctx context.Context, req *ProviderRequest, c
 		if err := json.Unmarshal(data, &chunk); err != nil {
 			continue
 		}
+		if chunk.Model != "" {
+			model = chunk.Model
+		}
 
 		if len(chunk.Choices) > 0 {
 			content := chunk.Choices[0].Delta.Content
@@ -210,8 +221,10 @@ func (p *OpenAIProvider) SendStream(ctx context.Context, req *ProviderRequest, c
 
 	return &ProviderResponse{
 		Content:          fullContent.String(),
+		Model:            model,
 		CompletionTokens: completionTokens,
 		TotalTokens:      completionTokens,
+		RateLimit:        parseRateLimitHeaders(resp.Header),
 	}, nil
 }
 
@@ -227,6 +240,7 @@ type openAIRequest struct {
 	Tools          []Tool          `json:"tools,omitempty"`
 	ToolChoice     any             `json:"tool_choice,omitempty"`
 	ResponseFormat *ResponseFormat `json:"response_format,omitempty"`
+	MaxTokens      int             `json:"max_completion_tokens,omitempty"`
 	// OpenAI uses "reasoning_effort" for o1 models
 	ReasoningEffort string `json:"reasoning_effort,omitempty"`
 }
@@ -240,6 +254,9 @@ func (p *OpenAIProvider) buildRequest(req *ProviderRequest) *openAIRequest {
 	if req.Temperature != nil {
 		oaiReq.Temperature = req.Temperature
 	}
+	if req.MaxTokens > 0 {
+		oaiReq.MaxTokens = req.MaxTokens
+	}
 
 	// OpenAI o1 models use reasoning_effort: low, medium, high
 	if req.Thinking != "" {
@@ -265,6 +282,42 @@ func (p *OpenAIProvider) buildRequest(req *ProviderRequest) *openAIRequest {
 	return oaiReq
 }
 
+// checkKey reports a missing credential before any request is built.
+func (p *OpenAIProvider) checkKey() error {
+	if p.azure != nil {
+		return p.azure.checkKey(p.config)
+	}
+	if p.config.APIKey == "" {
+		return &ProviderError{Provider: p.Name(), Message: "OPENAI_API_KEY not set"}
+	}
+	return nil
+}
+
+// url returns the endpoint for an API path; on Azure it targets the
+// deployment serving model.
+func (p *OpenAIProvider) url(path, model string) string {
+	if p.azure != nil {
+		return p.azure.url(path, Model(model))
+	}
+	return p.config.BaseURL + path
+}
+
+// authorize sets the request headers, including Azure's api-key or Entra ID token.
+func (p *OpenAIProvider) authorize(ctx context.Context, req *http.Request) error {
+	if p.azure == nil {
+		p.setHeaders(req)
+		return nil
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if err := p.azure.authorize(ctx, req, p.config.APIKey); err != nil {
+		return err
+	}
+	for k, v := range p.config.Headers {
+		req.Header.Set(k, v)
+	}
+	return nil
+}
+
 func (p *OpenAIProvider) setHeaders(req *http.Request) {
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+p.config.APIKey)
@@ -277,6 +330,7 @@ func (p *OpenAIProvider) setHeaders(req *http.Request) {
 func (p *OpenAIProvider) parseResponse(body []byte) (*ProviderResponse, error) {
 	var result struct {
 		ID      string `json:"id"`
+		Model   string `json:"model"`
 		Choices []struct {
 			Message struct {
 				Role      string     `json:"role"`
@@ -323,6 +377,7 @@ func (p *OpenAIProvider) parseResponse(body []byte) (*ProviderResponse, error) {
 	return &ProviderResponse{
 		Content:          choice.Message.Content,
 		ToolCalls:        choice.Message.ToolCalls,
+		Model:            result.Model,
 		PromptTokens:     result.Usage.PromptTokens,
 		CompletionTokens: result.Usage.CompletionTokens,
 		TotalTokens:      result.Usage.TotalTokens,
@@ -342,6 +397,7 @@ type responsesRequest struct {
 	Tools        []any         `json:"tools,omitempty"`
 	ToolChoice   string        `json:"tool_choice,omitempty"`
 	Reasoning    *reasoningCfg `json:"reasoning,omitempty"`
+	MaxTokens    int           `json:"max_output_tokens,omitempty"`
 }
 
 type reasoningCfg struct {
@@ -400,12 +456,18 @@ func (p *OpenAIProvider) sendResponses(ctx context.Context, req *ProviderRequest
 		}
 	}
 
+	model := resolveModel(ProviderOpenAI, Model(req.Model))
+	if p.azure != nil {
+		model = p.azure.deployment(Model(req.Model))
+	}
+
 	respReq := responsesRequest{
-		Model:        resolveModel(ProviderOpenAI, Model(req.Model)),
+		Model:        model,
 		Input:        input,
 		Instructions: instructions,
 		Tools:        tools,
 		ToolChoice:   "auto",
+		MaxTokens:    req.MaxTokens,
 	}
 
 	// Set reasoning effort if thinking is configured
@@ -418,14 +480,16 @@ func (p *OpenAIProvider) sendResponses(ctx context.Context, req *ProviderRequest
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to marshal responses request", Err: err}
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.config.BaseURL+"/responses", bytes.NewReader(body))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.url("/responses", req.Model), bytes.NewReader(body))
 	if err != nil {
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to create request", Err: err}
 	}
 
-	p.setHeaders(httpReq)
+	if err := p.authorize(ctx, httpReq); err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "failed to get access token", Err: err}
+	}
 
-	if Debug {
+	if debugOn(ctx) {
 		fmt.Printf("%s [%s] POST %s\n", colorDim("→"), p.Name(), "/responses")
 	}
 
@@ -439,8 +503,16 @@ func (p *OpenAIProvider) sendResponses(ctx context.Context, req *ProviderRequest
 	if err != nil {
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to read response", Err: err}
 	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, newHTTPError(p.Name(), resp, respBody)
+	}
 
-	return p.parseResponsesResponse(respBody)
+	result, err := p.parseResponsesResponse(respBody)
+	if err != nil {
+		return nil, err
+	}
+	result.RateLimit = parseRateLimitHeaders(resp.Header)
+	return result, nil
 }
 
 // buildBuiltinTool converts BuiltinTool to the API format
@@ -542,6 +614,7 @@ func (p *OpenAIProvider) buildBuiltinTool(bt BuiltinTool) map[string]any {
 func (p *OpenAIProvider) parseResponsesResponse(body []byte) (*ProviderResponse, error) {
 	var result struct {
 		ID     string `json:"id"`
+		Model  string `json:"model"`
 		Status string `json:"status"`
 		Output []struct {
 			ID      string `json:"id"`
@@ -730,6 +803,7 @@ func (p *OpenAIProvider) parseResponsesResponse(body []byte) (*ProviderResponse,
 
 	return &ProviderResponse{
 		Content:          textContent,
+		Model:            result.Model,
 		PromptTokens:     result.Usage.InputTokens,
 		CompletionTokens: result.Usage.OutputTokens,
 		TotalTokens:      result.Usage.TotalTokens,
@@ -746,8 +820,8 @@ func (p *OpenAIProvider) parseResponsesResponse(body []byte) (*ProviderResponse,
 // ═══════════════════════════════════════════════════════════════════════════
 
 func (p *OpenAIProvider) Embed(ctx context.Context, req *EmbeddingRequest) (*EmbeddingResponse, error) {
-	if p.config.APIKey == "" {
-		return nil, &ProviderError{Provider: p.Name(), Message: "OPENAI_API_KEY not set"}
+	if err := p.checkKey(); err != nil {
+		return nil, err
 	}
 
 	oaiReq := struct {
@@ -767,12 +841,14 @@ func (p *OpenAIProvider) Embed(ctx context.Context, req *EmbeddingRequest) (*Emb
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to marshal request", Err: err}
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.config.BaseURL+"/embeddings", bytes.NewReader(body))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.url("/embeddings", req.Model), bytes.NewReader(body))
 	if err != nil {
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to create request", Err: err}
 	}
 
-	p.setHeaders(httpReq)
+	if err := p.authorize(ctx, httpReq); err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "failed to get access token", Err: err}
+	}
 
 	resp, err := p.httpClient.Do(httpReq)
 	if err != nil {
@@ -784,6 +860,9 @@ func (p *OpenAIProvider) Embed(ctx context.Context, req *EmbeddingRequest) (*Emb
 	if err != nil {
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to read response", Err: err}
 	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, newHTTPError(p.Name(), resp, respBody)
+	}
 
 	var result struct {
 		Data []struct {
@@ -831,8 +910,8 @@ func (p *OpenAIProvider) Embed(ctx context.Context, req *EmbeddingRequest) (*Emb
 // ═══════════════════════════════════════════════════════════════════════════
 
 func (p *OpenAIProvider) TextToSpeech(ctx context.Context, req *TTSRequest) (*TTSResponse, error) {
-	if p.config.APIKey == "" {
-		return nil, &ProviderError{Provider: p.Name(), Message: "OPENAI_API_KEY not set"}
+	if err := p.checkKey(); err != nil {
+		return nil, err
 	}
 
 	oaiReq := struct {
@@ -854,12 +933,14 @@ func (p *OpenAIProvider) TextToSpeech(ctx context.Context, req *TTSRequest) (*TT
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to marshal request", Err: err}
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.config.BaseURL+"/audio/speech", bytes.NewReader(body))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.url("/audio/speech", req.Model), bytes.NewReader(body))
 	if err != nil {
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to create request", Err: err}
 	}
 
-	p.setHeaders(httpReq)
+	if err := p.authorize(ctx, httpReq); err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "failed to get access token", Err: err}
+	}
 
 	resp, err := p.httpClient.Do(httpReq)
 	if err != nil {
@@ -869,11 +950,7 @@ func (p *OpenAIProvider) TextToSpeech(ctx context.Context, req *TTSRequest) (*TT
 
 	if resp.StatusCode != http.StatusOK {
 		errBody, _ := io.ReadAll(resp.Body)
-		return nil, &ProviderError{
-			Provider: p.Name(),
-			Code:     fmt.Sprintf("%d", resp.StatusCode),
-			Message:  string(errBody),
-		}
+		return nil, newHTTPError(p.Name(), resp, errBody)
 	}
 
 	audio, err := io.ReadAll(resp.Body)
@@ -893,8 +970,8 @@ func (p *OpenAIProvider) TextToSpeech(ctx context.Context, req *TTSRequest) (*TT
 // ═══════════════════════════════════════════════════════════════════════════
 
 func (p *OpenAIProvider) SpeechToText(ctx context.Context, req *STTRequest) (*STTResponse, error) {
-	if p.config.APIKey == "" {
-		return nil, &ProviderError{Provider: p.Name(), Message: "OPENAI_API_KEY not set"}
+	if err := p.checkKey(); err != nil {
+		return nil, err
 	}
 
 	// Create multipart form
@@ -933,12 +1010,14 @@ func (p *OpenAIProvider) SpeechToText(ctx context.Context, req *STTRequest) (*ST
 
 	writer.Close()
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.config.BaseURL+"/audio/transcriptions", &buf)
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.url("/audio/transcriptions", req.Model), &buf)
 	if err != nil {
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to create request", Err: err}
 	}
 
-	httpReq.Header.Set("Authorization", "Bearer "+p.config.APIKey)
+	if err := p.authorize(ctx, httpReq); err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "failed to get access token", Err: err}
+	}
 	httpReq.Header.Set("Content-Type", writer.FormDataContentType())
 
 	resp, err := p.httpClient.Do(httpReq)
@@ -953,11 +1032,7 @@ func (p *OpenAIProvider) SpeechToText(ctx context.Context, req *STTRequest) (*ST
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, &ProviderError{
-			Provider: p.Name(),
-			Code:     fmt.Sprintf("%d", resp.StatusCode),
-			Message:  string(respBody),
-		}
+		return nil, newHTTPError(p.Name(), resp, respBody)
 	}
 
 	// Parse response
@@ -993,3 +1068,33 @@ func (p *OpenAIProvider) SpeechToText(ctx context.Context, req *STTRequest) (*ST
 
 	return sttResp, nil
 }
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Model Listing
+// ═══════════════════════════════════════════════════════════════════════════
+
+// ListModels lists the chat, audio and image models available to the API key.
+// Embedding and moderation models are skipped.
+func (p *OpenAIProvider) ListModels(ctx context.Context) ([]ModelSpec, error) {
+	var result struct {
+		Data []struct {
+			ID      string `json:"id"`
+			OwnedBy string `json:"owned_by"`
+		} `json:"data"`
+	}
+	if err := getJSON(ctx, p.httpClient, p.Name(), p.config.BaseURL+"/models", p.setHeaders, &result); err != nil {
+		return nil, err
+	}
+
+	index := catalogIndex(ProviderOpenAI)
+	specs := make([]ModelSpec, 0, len(result.Data))
+	for _, m := range result.Data {
+		if strings.Contains(m.ID, "embedding") || strings.Contains(m.ID, "moderation") {
+			continue
+		}
+		spec := discoveredSpec(index, ProviderOpenAI, "openai/", m.ID)
+		spec.Provider = "OpenAI"
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
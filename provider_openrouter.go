@@ -7,8 +7,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 )
 
@@ -83,7 +85,7 @@ func (p *OpenRouterProvider) Send(ctx context.Context, req *ProviderRequest) (*P
 
 	p.setHeaders(httpReq)
 
-	if Debug {
+	if debugOn(ctx) {
 		fmt.Printf("%s [%s] POST %s\n", colorDim("→"), p.Name(), "/chat/completions")
 	}
 
@@ -97,8 +99,16 @@ func (p *OpenRouterProvider) Send(ctx context.Context, req *ProviderRequest) (*P
 	if err != nil {
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to read response", Err: err}
 	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, newHTTPError(p.Name(), resp, respBody)
+	}
 
-	return p.parseResponse(respBody)
+	result, err := p.parseResponse(respBody)
+	if err != nil {
+		return nil, err
+	}
+	result.RateLimit = parseRateLimitHeaders(resp.Header)
+	return result, nil
 }
 
 // ═══════════════════════════════════════════════════════════════════════════
@@ -130,7 +140,7 @@ func (p *OpenRouterProvider) SendStream(ctx context.Context, req *ProviderReques
 
 	p.setHeaders(httpReq)
 
-	if Debug {
+	if debugOn(ctx) {
 		fmt.Printf("%s [%s] POST %s (stream)\n", colorDim("→"), p.Name(), "/chat/completions")
 	}
 
@@ -142,14 +152,11 @@ func (p *OpenRouterProvider) SendStream(ctx context.Context, req *ProviderReques
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, &ProviderError{
-			Provider: p.Name(),
-			Code:     fmt.Sprintf("%d", resp.StatusCode),
-			Message:  string(body),
-		}
+		return nil, newHTTPError(p.Name(), resp, body)
 	}
 
 	var fullContent strings.Builder
+	var model string
 	reader := bufio.NewReader(resp.Body)
 
 	for {
@@ -172,6 +179,7 @@ func (p *OpenRouterProvider) SendStream(ctx context.Context, req *ProviderReques
 		}
 
 		var chunk struct {
+			Model   string `json:"model"`
 			Choices []struct {
 				Delta struct {
 					Content string `json:"content"`
@@ -182,6 +190,9 @@ func (p *OpenRouterProvider) SendStream(ctx context.Context, req *ProviderReques
 		if err := json.Unmarshal(data, &chunk); err != nil {
 			continue
 		}
+		if chunk.Model != "" {
+			model = chunk.Model
+		}
 
 		if len(chunk.Choices) > 0 {
 			content := chunk.Choices[0].Delta.Content
@@ -195,8 +206,10 @@ func (p *OpenRouterProvider) SendStream(ctx context.Context, req *ProviderReques
 
 	return &ProviderResponse{
 		Content:          fullContent.String(),
+		Model:            model,
 		CompletionTokens: completionTokens,
 		TotalTokens:      completionTokens,
+		RateLimit:        parseRateLimitHeaders(resp.Header),
 	}, nil
 }
 
@@ -214,6 +227,7 @@ type openRouterRequest struct {
 	Tools          []Tool          `json:"tools,omitempty"`
 	ToolChoice     any             `json:"tool_choice,omitempty"`
 	ResponseFormat *ResponseFormat `json:"response_format,omitempty"`
+	MaxTokens      int             `json:"max_tokens,omitempty"`
 }
 
 func (p *OpenRouterProvider) buildRequest(req *ProviderRequest) *openRouterRequest {
@@ -225,6 +239,9 @@ func (p *OpenRouterProvider) buildRequest(req *ProviderRequest) *openRouterReque
 	if req.Temperature != nil {
 		orReq.Temperature = req.Temperature
 	}
+	if req.MaxTokens > 0 {
+		orReq.MaxTokens = req.MaxTokens
+	}
 	if req.Thinking != "" {
 		orReq.Reasoning = req.Thinking
 	}
@@ -253,6 +270,7 @@ func (p *OpenRouterProvider) setHeaders(req *http.Request) {
 func (p *OpenRouterProvider) parseResponse(body []byte) (*ProviderResponse, error) {
 	var result struct {
 		ID      string `json:"id"`
+		Model   string `json:"model"`
 		Choices []struct {
 			Message struct {
 				Role      string     `json:"role"`
@@ -298,9 +316,92 @@ func (p *OpenRouterProvider) parseResponse(body []byte) (*ProviderResponse, erro
 	return &ProviderResponse{
 		Content:          choice.Message.Content,
 		ToolCalls:        choice.Message.ToolCalls,
+		Model:            result.Model,
 		PromptTokens:     result.Usage.PromptTokens,
 		CompletionTokens: result.Usage.CompletionTokens,
 		TotalTokens:      result.Usage.TotalTokens,
 		FinishReason:     choice.FinishReason,
 	}, nil
 }
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Model Listing
+// ═══════════════════════════════════════════════════════════════════════════
+
+// ListModels lists every OpenRouter model with its context length, modalities
+// and current pricing (converted from USD per token to USD per 1M tokens).
+func (p *OpenRouterProvider) ListModels(ctx context.Context) ([]ModelSpec, error) {
+	var result struct {
+		Data []struct {
+			ID            string `json:"id"`
+			Name          string `json:"name"`
+			Description   string `json:"description"`
+			ContextLength int    `json:"context_length"`
+			Pricing       struct {
+				Prompt     string `json:"prompt"`
+				Completion string `json:"completion"`
+			} `json:"pricing"`
+			Architecture struct {
+				InputModalities  []string `json:"input_modalities"`
+				OutputModalities []string `json:"output_modalities"`
+			} `json:"architecture"`
+			TopProvider struct {
+				MaxCompletionTokens int `json:"max_completion_tokens"`
+			} `json:"top_provider"`
+			SupportedParameters []string `json:"supported_parameters"`
+		} `json:"data"`
+	}
+	if err := getJSON(ctx, p.httpClient, p.Name(), p.config.BaseURL+"/models", p.setHeaders, &result); err != nil {
+		return nil, err
+	}
+
+	specs := make([]ModelSpec, 0, len(result.Data))
+	for _, m := range result.Data {
+		spec := ModelSpec{
+			ID:               Model(m.ID),
+			Name:             m.Name,
+			Provider:         openRouterVendor(m.Name),
+			Description:      m.Description,
+			ContextWindow:    m.ContextLength,
+			MaxOutput:        m.TopProvider.MaxCompletionTokens,
+			InputModalities:  openRouterModalities(m.Architecture.InputModalities),
+			OutputModalities: openRouterModalities(m.Architecture.OutputModalities),
+			Tools:            containsString(m.SupportedParameters, "tools"),
+			JSON:             containsString(m.SupportedParameters, "response_format"),
+			Thinking:         containsString(m.SupportedParameters, "reasoning"),
+		}
+		in, errIn := strconv.ParseFloat(m.Pricing.Prompt, 64)
+		out, errOut := strconv.ParseFloat(m.Pricing.Completion, 64)
+		// Routers like "openrouter/auto" report -1 (variable pricing).
+		if errIn == nil && errOut == nil && in >= 0 && out >= 0 {
+			spec.Pricing = &ModelPricing{InputPerMillion: perMillion(in), OutputPerMillion: perMillion(out)}
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+// openRouterVendor extracts the vendor from display names like "OpenAI: GPT-4o".
+func openRouterVendor(name string) string {
+	if vendor, _, ok := strings.Cut(name, ": "); ok {
+		return vendor
+	}
+	return ""
+}
+
+// openRouterModalities maps OpenRouter modality names onto ours ("file" is PDF input).
+func openRouterModalities(names []string) []Modality {
+	mods := make([]Modality, 0, len(names))
+	for _, n := range names {
+		if n == "file" {
+			n = string(ModalityDocument)
+		}
+		mods = append(mods, Modality(n))
+	}
+	return mods
+}
+
+// perMillion converts a per-token price to per 1M tokens, rounded to drop float noise.
+func perMillion(perToken float64) float64 {
+	return math.Round(perToken*1e12) / 1e6
+}
@@ -0,0 +1,244 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Router Provider
+// ═══════════════════════════════════════════════════════════════════════════
+
+// ProviderRouter routes each request to a provider chosen by model (see RouterProvider).
+const ProviderRouter ProviderType = "router"
+
+// RouteRule sends matching models to Provider. Match takes precedence over
+// Prefix when both are set.
+type RouteRule struct {
+	Prefix   string           // e.g. "acme/" matches "acme/model-x"
+	Match    func(Model) bool // custom predicate
+	Provider Provider
+}
+
+func (r RouteRule) matches(model Model) bool {
+	if r.Match != nil {
+		return r.Match(model)
+	}
+	return r.Prefix != "" && strings.HasPrefix(string(model), r.Prefix)
+}
+
+// routerNamespaces are the model namespaces served by a direct provider when
+// one of its API keys is set.
+var routerNamespaces = []struct {
+	namespace string
+	provider  ProviderType
+	keys      []string
+}{
+	{"openai", ProviderOpenAI, []string{"OPENAI_API_KEY"}},
+	{"anthropic", ProviderAnthropic, []string{"ANTHROPIC_API_KEY"}},
+	{"google", ProviderGoogle, []string{"GOOGLE_API_KEY", "GEMINI_API_KEY"}},
+	{"mistralai", ProviderMistral, []string{"MISTRAL_API_KEY"}},
+	{"deepseek", ProviderDeepSeek, []string{"DEEPSEEK_API_KEY"}},
+}
+
+// RouterProvider dispatches each request to one of several providers by
+// model, so fallbacks, Compare and BatchModels can span vendors from a
+// single client.
+type RouterProvider struct {
+	rules      []RouteRule
+	namespaces map[string]Provider // model namespace → direct provider
+	fallback   Provider
+}
+
+// NewRouterProvider creates a RouterProvider. Rules are checked first, in
+// order. Otherwise a model's namespace ("anthropic/...", or bare IDs such as
+// "claude-..." and "gpt-...") selects the direct provider when its API key
+// is set, and everything else goes to OpenRouter.
+//
+// Timeout, Headers and MediaFetcher from config apply to every provider the
+// router creates; each reads its own API key from the environment.
+func NewRouterProvider(config ProviderConfig, rules ...RouteRule) *RouterProvider {
+	shared := config
+	shared.APIKey, shared.BaseURL = "", ""
+
+	r := &RouterProvider{
+		rules:      rules,
+		namespaces: map[string]Provider{},
+		fallback:   NewClient(ProviderOpenRouter, withConfig(shared)).provider,
+	}
+	for _, ns := range routerNamespaces {
+		for _, key := range ns.keys {
+			if os.Getenv(key) != "" {
+				r.namespaces[ns.namespace] = NewClient(ns.provider, withConfig(shared)).provider
+				break
+			}
+		}
+	}
+	return r
+}
+
+// withConfig copies a whole config into the client options.
+func withConfig(config ProviderConfig) ClientOption {
+	return func(c *ProviderConfig) {
+		*c = config
+	}
+}
+
+// Name returns the provider identifier ("router").
+func (r *RouterProvider) Name() string {
+	return string(ProviderRouter)
+}
+
+// ProviderFor returns the provider that serves model.
+func (r *RouterProvider) ProviderFor(model Model) Provider {
+	for _, rule := range r.rules {
+		if rule.Provider != nil && rule.matches(model) {
+			return rule.Provider
+		}
+	}
+	if p, ok := r.namespaces[modelNamespace(model)]; ok {
+		return p
+	}
+	return r.fallback
+}
+
+// modelNamespace returns the vendor namespace of a model ID, inferring it
+// for bare Claude, Gemini and OpenAI IDs.
+func modelNamespace(model Model) string {
+	id := string(model)
+	if ns, _, ok := strings.Cut(id, "/"); ok {
+		return ns
+	}
+	switch {
+	case strings.HasPrefix(id, "claude-"):
+		return "anthropic"
+	case strings.HasPrefix(id, "gemini-"):
+		return "google"
+	case looksLikeOpenAIModelID(id):
+		return "openai"
+	}
+	return ""
+}
+
+// providers returns each distinct routed provider once.
+func (r *RouterProvider) providers() []Provider {
+	seen := map[Provider]bool{}
+	var out []Provider
+	add := func(p Provider) {
+		if p != nil && !seen[p] {
+			seen[p] = true
+			out = append(out, p)
+		}
+	}
+	for _, rule := range r.rules {
+		add(rule.Provider)
+	}
+	for _, ns := range routerNamespaces {
+		add(r.namespaces[ns.namespace])
+	}
+	add(r.fallback)
+	return out
+}
+
+// Capabilities reports the union of the routed providers' capabilities.
+// Builders check the provider chosen for each model instead.
+func (r *RouterProvider) Capabilities() ProviderCapabilities {
+	var caps ProviderCapabilities
+	for _, p := range r.providers() {
+		c := p.Capabilities()
+		caps.Tools = caps.Tools || c.Tools
+		caps.Vision = caps.Vision || c.Vision
+		caps.Streaming = caps.Streaming || c.Streaming
+		caps.JSON = caps.JSON || c.JSON
+		caps.Thinking = caps.Thinking || c.Thinking
+		caps.PDF = caps.PDF || c.PDF
+		caps.Embeddings = caps.Embeddings || c.Embeddings
+		caps.TTS = caps.TTS || c.TTS
+		caps.STT = caps.STT || c.STT
+		caps.WebSearch = caps.WebSearch || c.WebSearch
+		caps.FileSearch = caps.FileSearch || c.FileSearch
+		caps.CodeInterpreter = caps.CodeInterpreter || c.CodeInterpreter
+		caps.MCP = caps.MCP || c.MCP
+		caps.ImageGeneration = caps.ImageGeneration || c.ImageGeneration
+		caps.ComputerUse = caps.ComputerUse || c.ComputerUse
+		caps.Shell = caps.Shell || c.Shell
+		caps.ApplyPatch = caps.ApplyPatch || c.ApplyPatch
+	}
+	return caps
+}
+
+// Send routes a non-streaming request.
+func (r *RouterProvider) Send(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+	return r.ProviderFor(Model(req.Model)).Send(ctx, req)
+}
+
+// SendStream routes a streaming request.
+func (r *RouterProvider) SendStream(ctx context.Context, req *ProviderRequest, callback StreamCallback) (*ProviderResponse, error) {
+	return r.ProviderFor(Model(req.Model)).SendStream(ctx, req, callback)
+}
+
+// Embed routes an embedding request by its model.
+func (r *RouterProvider) Embed(ctx context.Context, req *EmbeddingRequest) (*EmbeddingResponse, error) {
+	p := r.ProviderFor(Model(req.Model))
+	embedder, ok := p.(Embedder)
+	if !ok {
+		return nil, fmt.Errorf("provider %s does not support embeddings", p.Name())
+	}
+	return embedder.Embed(ctx, req)
+}
+
+// TextToSpeech routes a TTS request by its model.
+func (r *RouterProvider) TextToSpeech(ctx context.Context, req *TTSRequest) (*TTSResponse, error) {
+	p := r.ProviderFor(Model(req.Model))
+	audio, ok := p.(AudioProvider)
+	if !ok {
+		return nil, fmt.Errorf("provider %s does not support text-to-speech", p.Name())
+	}
+	return audio.TextToSpeech(ctx, req)
+}
+
+// SpeechToText routes an STT request by its model.
+func (r *RouterProvider) SpeechToText(ctx context.Context, req *STTRequest) (*STTResponse, error) {
+	p := r.ProviderFor(Model(req.Model))
+	audio, ok := p.(AudioProvider)
+	if !ok {
+		return nil, fmt.Errorf("provider %s does not support speech-to-text", p.Name())
+	}
+	return audio.SpeechToText(ctx, req)
+}
+
+// ListModels combines the model lists of every routed provider. Providers
+// that fail are skipped unless all of them do.
+func (r *RouterProvider) ListModels(ctx context.Context) ([]ModelSpec, error) {
+	var specs []ModelSpec
+	var lastErr error
+	listed := false
+	for _, p := range r.providers() {
+		lister, ok := p.(ModelLister)
+		if !ok {
+			continue
+		}
+		s, err := lister.ListModels(ctx)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		listed = true
+		specs = append(specs, s...)
+	}
+	if !listed && lastErr != nil {
+		return nil, lastErr
+	}
+	return specs, nil
+}
+
+// providerFor returns the provider that will serve model on this client,
+// looking through a RouterProvider and wrapped in the client's middleware.
+func (c *Client) providerFor(model Model) Provider {
+	if r, ok := c.provider.(*RouterProvider); ok {
+		return c.withMiddleware(r.ProviderFor(model))
+	}
+	return c.withMiddleware(c.provider)
+}
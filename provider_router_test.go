@@ -0,0 +1,84 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRouter_PrefersDirectProviderAndFallsBackAcrossVendors(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	var anthropicCalls, openRouterCalls int
+	anthropic := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		anthropicCalls++
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"type":"error","error":{"type":"api_error","message":"overloaded"}}`))
+	}))
+	defer anthropic.Close()
+	openRouter := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		openRouterCalls++
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"content":"from openrouter"},"finish_reason":"stop"}]}`))
+	}))
+	defer openRouter.Close()
+
+	t.Setenv("ANTHROPIC_API_KEY", "a-key")
+	t.Setenv("OPENAI_API_KEY", "")
+	t.Setenv("OPENROUTER_API_KEY", "or-key")
+	withProviderBaseURL(t, ProviderAnthropic, anthropic.URL)
+	withProviderBaseURL(t, ProviderOpenRouter, openRouter.URL)
+
+	c := Router()
+	r := c.provider.(*RouterProvider)
+	if got := r.ProviderFor(ModelClaudeSonnet).Name(); got != "anthropic" {
+		t.Fatalf("expected direct anthropic route, got %s", got)
+	}
+	if got := r.ProviderFor("claude-sonnet-4-5").Name(); got != "anthropic" {
+		t.Fatalf("expected bare Claude ID to route to anthropic, got %s", got)
+	}
+	if got := r.ProviderFor(ModelGPT5).Name(); got != "openrouter" {
+		t.Fatalf("expected OpenRouter without OPENAI_API_KEY, got %s", got)
+	}
+
+	meta := c.Claude().Fallback(ModelGPT5).User("hi").SendWithMeta()
+	if meta.Error != nil {
+		t.Fatal(meta.Error)
+	}
+	if meta.Content != "from openrouter" || meta.Model != ModelGPT5 {
+		t.Fatalf("unexpected result %+v", meta)
+	}
+	if anthropicCalls != 1 || openRouterCalls != 1 {
+		t.Fatalf("expected one call each, got anthropic=%d openrouter=%d", anthropicCalls, openRouterCalls)
+	}
+}
+
+func TestRouter_RulesAndDefaultProvider(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	acme := &stubProvider{name: "acme", sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+		return &ProviderResponse{Content: "acme:" + req.Model}, nil
+	}}
+	local := &stubProvider{name: "local", sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+		return nil, errors.New("local down")
+	}}
+	r := NewRouterProvider(ProviderConfig{},
+		RouteRule{Prefix: "acme/", Provider: acme},
+		RouteRule{Match: func(m Model) bool { return m == "llama3:8b" }, Provider: local},
+	)
+	setDefaultClientForTest(t, r, ProviderRouter)
+
+	results := BatchModels("hi", "acme/one", "llama3:8b").Do()
+	if results[0].Error != nil || results[0].Content != "acme:acme/one" {
+		t.Fatalf("unexpected acme result %+v", results[0])
+	}
+	if results[1].Error == nil {
+		t.Fatal("expected the local rule to be used")
+	}
+	if acme.sendCalls != 1 || local.sendCalls != 1 {
+		t.Fatalf("unexpected calls acme=%d local=%d", acme.sendCalls, local.sendCalls)
+	}
+}
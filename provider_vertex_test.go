@@ -0,0 +1,181 @@
+package ai
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestServiceAccountTokenSource_JWTExchangeAndCaching(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, _ := x509.MarshalPKCS8PrivateKey(key)
+	pemKey := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+
+	exchanges := 0
+	expiresIn := 3600
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		exchanges++
+		_ = r.ParseForm()
+		if r.Form.Get("grant_type") != "urn:ietf:params:oauth:grant-type:jwt-bearer" {
+			t.Fatalf("unexpected grant_type %q", r.Form.Get("grant_type"))
+		}
+
+		parts := strings.Split(r.Form.Get("assertion"), ".")
+		if len(parts) != 3 {
+			t.Fatalf("malformed assertion")
+		}
+		sig, _ := base64.RawURLEncoding.DecodeString(parts[2])
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], sig); err != nil {
+			t.Fatalf("bad signature: %v", err)
+		}
+		var claims map[string]any
+		payload, _ := base64.RawURLEncoding.DecodeString(parts[1])
+		_ = json.Unmarshal(payload, &claims)
+		if claims["iss"] != "sa@proj.iam.gserviceaccount.com" || claims["aud"] != srv.URL || claims["scope"] != GoogleCloudScope {
+			t.Fatalf("unexpected claims %v", claims)
+		}
+
+		fmt.Fprintf(w, `{"access_token":"tok-%d","expires_in":%d,"token_type":"Bearer"}`, exchanges, expiresIn)
+	}))
+	defer srv.Close()
+
+	keyJSON, _ := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"project_id":   "proj",
+		"private_key":  pemKey,
+		"client_email": "sa@proj.iam.gserviceaccount.com",
+		"token_uri":    srv.URL,
+	})
+	ts, err := ServiceAccountTokenSource(keyJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		tok, err := ts.Token(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tok != "tok-1" {
+			t.Fatalf("expected cached token, got %q", tok)
+		}
+	}
+	if exchanges != 1 {
+		t.Fatalf("expected 1 exchange, got %d", exchanges)
+	}
+
+	// A token inside the refresh window is replaced on next use.
+	ts, _ = ServiceAccountTokenSource(keyJSON)
+	expiresIn = 30
+	first, _ := ts.Token(ctx)
+	second, _ := ts.Token(ctx)
+	if first == second {
+		t.Fatalf("expected refresh of near-expiry token, got %q twice", first)
+	}
+}
+
+func TestVertex_RoutesGeminiAndClaude(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	var bodies = map[string]map[string]any{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer vertex-token" {
+			t.Fatalf("unexpected auth %q", got)
+		}
+		if r.URL.Query().Get("key") != "" {
+			t.Fatal("API key must not be sent to Vertex AI")
+		}
+		body, _ := io.ReadAll(r.Body)
+		var m map[string]any
+		_ = json.Unmarshal(body, &m)
+		bodies[r.URL.Path] = m
+
+		base := "/projects/proj/locations/us-east5/publishers/"
+		switch r.URL.Path {
+		case base + "google/models/gemini-2.5-flash:generateContent":
+			_, _ = w.Write([]byte(`{"candidates":[{"content":{"parts":[{"text":"from gemini"}]},"finishReason":"STOP"}],
+				"usageMetadata":{"promptTokenCount":3,"candidatesTokenCount":2,"totalTokenCount":5}}`))
+		case base + "anthropic/models/claude-sonnet-4-5@20250929:rawPredict":
+			_, _ = w.Write([]byte(`{"content":[{"type":"text","text":"from claude"}],"stop_reason":"end_turn",
+				"usage":{"input_tokens":4,"output_tokens":2}}`))
+		case base + "anthropic/models/claude-sonnet-4-5@20250929:streamRawPredict":
+			_, _ = w.Write([]byte("event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"delta\":{\"type\":\"text_delta\",\"text\":\"streamed\"}}\n\n" +
+				"data: {\"type\":\"message_stop\"}\n\n"))
+		default:
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(ProviderGoogle,
+		WithProject("proj"), WithRegion("us-east5"), WithBaseURL(srv.URL),
+		WithTokenSource(StaticTokenSource("vertex-token")))
+	ctx := context.Background()
+	msgs := []Message{{Role: "user", Content: "hi"}}
+
+	resp, err := c.provider.Send(ctx, &ProviderRequest{Model: string(ModelGemini25Flash), Messages: msgs})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Content != "from gemini" {
+		t.Fatalf("unexpected gemini content %q", resp.Content)
+	}
+
+	resp, err = c.provider.Send(ctx, &ProviderRequest{Model: string(ModelClaudeSonnet), Messages: msgs})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Content != "from claude" || resp.TotalTokens != 6 {
+		t.Fatalf("unexpected claude response %+v", resp)
+	}
+	claudeBody := bodies["/projects/proj/locations/us-east5/publishers/anthropic/models/claude-sonnet-4-5@20250929:rawPredict"]
+	if claudeBody["anthropic_version"] != vertexAnthropicVersion {
+		t.Fatalf("expected anthropic_version in body, got %v", claudeBody)
+	}
+	if _, ok := claudeBody["model"]; ok {
+		t.Fatal("model must not be sent in the Vertex AI body")
+	}
+
+	var streamed strings.Builder
+	resp, err = c.provider.SendStream(ctx, &ProviderRequest{Model: string(ModelClaudeSonnet), Messages: msgs},
+		func(chunk string) { streamed.WriteString(chunk) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if streamed.String() != "streamed" || resp.Content != "streamed" {
+		t.Fatalf("unexpected stream %q / %q", streamed.String(), resp.Content)
+	}
+}
+
+func TestVertexClaudeModelID(t *testing.T) {
+	cases := map[string]string{
+		"claude-sonnet-4-5-20250929": "claude-sonnet-4-5@20250929",
+		"claude-3-5-haiku-20241022":  "claude-3-5-haiku@20241022",
+		"claude-opus-4-1@20250805":   "claude-opus-4-1@20250805",
+		"claude-sonnet-4-5":          "claude-sonnet-4-5",
+	}
+	for in, want := range cases {
+		if got := vertexClaudeModelID(in); got != want {
+			t.Errorf("vertexClaudeModelID(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
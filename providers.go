@@ -1,6 +1,7 @@
 package ai
 
 import (
+	"os"
 	"sync"
 )
 
@@ -81,10 +82,37 @@ func OllamaAt(url string) *Client {
 	return NewClient(ProviderOllama, WithBaseURL(url))
 }
 
-// Azure returns a client for Azure OpenAI.
+// Azure returns a client for an Azure OpenAI resource endpoint (or a legacy
+// deployment URL). Use WithAzureDeployments to map models to deployments.
 // Example: ai.Azure("https://mycompany.openai.azure.com").GPT4o().Ask("...")
-func Azure(endpoint string) *Client {
-	return NewClient(ProviderAzure, WithBaseURL(endpoint))
+func Azure(endpoint string, opts ...ClientOption) *Client {
+	return NewClient(ProviderAzure, append([]ClientOption{WithBaseURL(endpoint)}, opts...)...)
+}
+
+// Bedrock returns a client for Amazon Bedrock in the given region
+// ("" = region from the environment or shared config).
+// Example: ai.Bedrock("us-west-2").Claude().Ask("...")
+func Bedrock(region string, opts ...ClientOption) *Client {
+	return NewClient(ProviderBedrock, append([]ClientOption{WithRegion(region)}, opts...)...)
+}
+
+// Vertex returns a client for Google Vertex AI in the given project and location
+// ("" = GOOGLE_CLOUD_PROJECT / GOOGLE_CLOUD_LOCATION, then "us-central1").
+// Gemini and Claude models are both served; auth uses the service-account key
+// in GOOGLE_APPLICATION_CREDENTIALS unless WithTokenSource is given.
+// Example: ai.Vertex("my-project", "us-east5").Claude().Ask("...")
+func Vertex(project, location string, opts ...ClientOption) *Client {
+	if project == "" {
+		project = os.Getenv("GOOGLE_CLOUD_PROJECT")
+	}
+	return NewClient(ProviderGoogle, append([]ClientOption{WithProject(project), WithRegion(location)}, opts...)...)
+}
+
+// Router returns a client that routes each model to its vendor's API when that
+// vendor's key is set, and to OpenRouter otherwise. Rules take precedence.
+// Example: ai.Router().Claude().Fallback(ai.ModelGPT5).Ask("...")
+func Router(rules ...RouteRule) *Client {
+	return NewClientWithProvider(NewRouterProvider(ProviderConfig{}, rules...))
 }
 
 // OpenRouter returns a client for OpenRouter (explicit).
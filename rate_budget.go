@@ -0,0 +1,341 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Per-Model Rate Limits
+// ═══════════════════════════════════════════════════════════════════════════
+
+// Limits optionally applies request and token budgets per provider and model.
+// Set it to a RateLimits (for example, NewRateLimits().Set(ProviderOpenAI, "", RateBudget{RPM: 500})),
+// or give a single client its own with WithLimits.
+var Limits *RateLimits
+
+// Priority orders requests waiting for the same budget. Higher priorities
+// are served first; equal priorities are served in arrival order.
+type Priority int
+
+const (
+	PriorityBatch       Priority = -1 // background and batch jobs (default for Batch)
+	PriorityNormal      Priority = 0  // default
+	PriorityInteractive Priority = 1  // user-facing requests
+)
+
+type priorityKey struct{}
+
+// withDefaultPriority sets the priority of requests in ctx whose builder
+// leaves it at PriorityNormal.
+func withDefaultPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+// requestPriority returns the builder's priority, or the default from ctx.
+func (b *Builder) requestPriority(ctx context.Context) Priority {
+	if b.priority == PriorityNormal {
+		if p, ok := ctx.Value(priorityKey{}).(Priority); ok {
+			return p
+		}
+	}
+	return b.priority
+}
+
+// Priority sets the request's priority when waiting for rate limits.
+func (b *Builder) Priority(p Priority) *Builder {
+	b.priority = p
+	return b
+}
+
+// RateBudget is a per-minute budget. Zero fields are unlimited.
+type RateBudget struct {
+	RPM int // requests per minute
+	TPM int // tokens per minute (prompt estimate, reconciled with actual usage)
+}
+
+// RateLimits holds budgets keyed by provider and model.
+type RateLimits struct {
+	mu      sync.Mutex
+	buckets map[rateKey]*budgetBucket
+}
+
+type rateKey struct {
+	provider string
+	model    Model // "" applies to every model of the provider
+}
+
+// NewRateLimits creates an empty RateLimits.
+func NewRateLimits() *RateLimits {
+	return &RateLimits{buckets: map[rateKey]*budgetBucket{}}
+}
+
+// Set sets the budget for a provider's model, or for all of its models when
+// model is "". Both apply to a request that matches both.
+func (l *RateLimits) Set(provider ProviderType, model Model, budget RateBudget) *RateLimits {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.buckets[rateKey{string(provider), model}] = newBudgetBucket(budget)
+	return l
+}
+
+// Wait blocks until provider/model has room for a request of about tokens
+// prompt tokens, or ctx is done. Call Done on the returned Reservation with
+// the actual token usage once the response arrives.
+func (l *RateLimits) Wait(ctx context.Context, provider string, model Model, tokens int, priority Priority) (*Reservation, error) {
+	keys := []rateKey{{provider, ""}}
+	if model != "" {
+		keys = append(keys, rateKey{provider, model})
+	}
+	l.mu.Lock()
+	var buckets []*budgetBucket
+	for _, key := range keys {
+		if b, ok := l.buckets[key]; ok {
+			buckets = append(buckets, b)
+		}
+	}
+	l.mu.Unlock()
+
+	r := &Reservation{tokens: tokens}
+	for _, b := range buckets {
+		if err := b.take(ctx, tokens, priority); err != nil {
+			// Hand back what the buckets already taken were charged.
+			for _, taken := range r.buckets {
+				taken.release(tokens)
+			}
+			return nil, err
+		}
+		r.buckets = append(r.buckets, b)
+	}
+	return r, nil
+}
+
+// Reservation is a request admitted by RateLimits.
+type Reservation struct {
+	tokens  int
+	buckets []*budgetBucket
+	once    sync.Once
+}
+
+// Done reconciles the estimated tokens with the actual usage. Pass 0 when the
+// request failed before using any tokens. Safe to call on a nil Reservation.
+func (r *Reservation) Done(actualTokens int) {
+	if r == nil {
+		return
+	}
+	r.once.Do(func() {
+		for _, b := range r.buckets {
+			b.adjust(r.tokens - actualTokens)
+		}
+	})
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Budget Bucket
+// ═══════════════════════════════════════════════════════════════════════════
+
+// budgetBucket refills requests and tokens continuously over a minute and
+// admits waiters strictly by priority, then arrival.
+type budgetBucket struct {
+	budget RateBudget
+	now    func() time.Time
+
+	mu       sync.Mutex
+	requests float64
+	tokens   float64
+	last     time.Time
+	waiters  []*rateWaiter
+	changed  chan struct{} // closed and replaced whenever the queue or levels change
+}
+
+type rateWaiter struct {
+	priority Priority
+}
+
+func newBudgetBucket(budget RateBudget) *budgetBucket {
+	return &budgetBucket{
+		budget:   budget,
+		now:      time.Now,
+		requests: float64(budget.RPM),
+		tokens:   float64(budget.TPM),
+		last:     time.Now(),
+		changed:  make(chan struct{}),
+	}
+}
+
+func (b *budgetBucket) refillLocked() {
+	now := b.now()
+	minutes := now.Sub(b.last).Minutes()
+	b.last = now
+	if b.budget.RPM > 0 {
+		b.requests += minutes * float64(b.budget.RPM)
+		if b.requests > float64(b.budget.RPM) {
+			b.requests = float64(b.budget.RPM)
+		}
+	}
+	if b.budget.TPM > 0 {
+		b.tokens += minutes * float64(b.budget.TPM)
+		if b.tokens > float64(b.budget.TPM) {
+			b.tokens = float64(b.budget.TPM)
+		}
+	}
+}
+
+// shortfallLocked returns how long until a request of tokens fits. Requests
+// larger than the whole TPM budget wait for a full bucket and go into debt.
+func (b *budgetBucket) shortfallLocked(tokens int) time.Duration {
+	var wait time.Duration
+	if b.budget.RPM > 0 && b.requests < 1 {
+		wait = time.Duration((1 - b.requests) / float64(b.budget.RPM) * float64(time.Minute))
+	}
+	if b.budget.TPM > 0 {
+		need := float64(tokens)
+		if need > float64(b.budget.TPM) {
+			need = float64(b.budget.TPM)
+		}
+		if b.tokens < need {
+			if d := time.Duration((need - b.tokens) / float64(b.budget.TPM) * float64(time.Minute)); d > wait {
+				wait = d
+			}
+		}
+	}
+	return wait
+}
+
+func (b *budgetBucket) notifyLocked() {
+	close(b.changed)
+	b.changed = make(chan struct{})
+}
+
+func (b *budgetBucket) removeLocked(w *rateWaiter) {
+	for i, x := range b.waiters {
+		if x == w {
+			b.waiters = append(b.waiters[:i], b.waiters[i+1:]...)
+			break
+		}
+	}
+	b.notifyLocked()
+}
+
+// take waits for the front of the queue and enough budget, then debits it.
+func (b *budgetBucket) take(ctx context.Context, tokens int, priority Priority) error {
+	b.mu.Lock()
+	w := &rateWaiter{priority: priority}
+	b.waiters = append(b.waiters, w)
+	sort.SliceStable(b.waiters, func(i, j int) bool {
+		return b.waiters[i].priority > b.waiters[j].priority
+	})
+	b.notifyLocked() // a new head may pre-empt the current one
+
+	for {
+		b.refillLocked()
+		wait := time.Duration(-1) // not at the front: wait for a change
+		if b.waiters[0] == w {
+			if wait = b.shortfallLocked(tokens); wait == 0 {
+				if b.budget.RPM > 0 {
+					b.requests--
+				}
+				if b.budget.TPM > 0 {
+					b.tokens -= float64(tokens)
+				}
+				b.removeLocked(w)
+				b.mu.Unlock()
+				return nil
+			}
+			if debugOn(ctx) {
+				fmt.Printf("%s Rate budget: waiting %v\n", colorYellow("⏳"), wait.Round(time.Millisecond))
+			}
+		}
+		changed := b.changed
+		b.mu.Unlock()
+
+		var timeout <-chan time.Time
+		var timer *time.Timer
+		if wait > 0 {
+			timer = time.NewTimer(wait)
+			timeout = timer.C
+		}
+		select {
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			b.mu.Lock()
+			b.removeLocked(w)
+			b.mu.Unlock()
+			return ctx.Err()
+		case <-changed:
+		case <-timeout:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		b.mu.Lock()
+	}
+}
+
+// adjust returns unused tokens (delta > 0) or debits extra usage (delta < 0).
+func (b *budgetBucket) adjust(delta int) {
+	if b.budget.TPM == 0 || delta == 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refillLocked()
+	b.tokens += float64(delta)
+	if b.tokens > float64(b.budget.TPM) {
+		b.tokens = float64(b.budget.TPM)
+	}
+	b.notifyLocked()
+}
+
+// release refunds the request slot and tokens of an admission whose
+// request was never sent.
+func (b *budgetBucket) release(tokens int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refillLocked()
+	if b.budget.RPM > 0 {
+		b.requests++
+		if b.requests > float64(b.budget.RPM) {
+			b.requests = float64(b.budget.RPM)
+		}
+	}
+	if b.budget.TPM > 0 {
+		b.tokens += float64(tokens)
+		if b.tokens > float64(b.budget.TPM) {
+			b.tokens = float64(b.budget.TPM)
+		}
+	}
+	b.notifyLocked()
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Builder Integration
+// ═══════════════════════════════════════════════════════════════════════════
+
+// limitedSend runs one request once the request's limiter and Limits admit
+// it, and feeds the provider's rate-limit headers to ServerLimits. A client
+// can replace either with WithLimits and WithServerLimits.
+func (b *Builder) limitedSend(ctx context.Context, provider Provider, model Model, msgs []Message, send func() (*ProviderResponse, error)) (*ProviderResponse, error) {
+	if err := waitForRateLimit(ctx); err != nil {
+		return nil, err
+	}
+	var reservation *Reservation
+	if limits := settingsFrom(ctx).limits; limits != nil {
+		var err error
+		if reservation, err = limits.Wait(ctx, provider.Name(), model, b.countTokensFor(model, msgs), b.requestPriority(ctx)); err != nil {
+			return nil, err
+		}
+	}
+	resp, err := withServerLimits(ctx, provider, string(model), send)
+	if err != nil {
+		reservation.Done(0)
+	} else if resp.TotalTokens > 0 {
+		reservation.Done(resp.TotalTokens) // otherwise the prompt estimate stands
+	}
+	return resp, err
+}
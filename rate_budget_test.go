@@ -0,0 +1,193 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeBucketClock freezes a bucket's clock so refills only happen on advance.
+func fakeBucketClock(b *budgetBucket) (advance func(time.Duration)) {
+	var mu sync.Mutex
+	now := time.Now()
+	b.mu.Lock()
+	b.now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+	b.last = now
+	b.mu.Unlock()
+	return func(d time.Duration) {
+		mu.Lock()
+		now = now.Add(d)
+		mu.Unlock()
+		b.mu.Lock()
+		b.notifyLocked()
+		b.mu.Unlock()
+	}
+}
+
+func waitForWaiters(t *testing.T, b *budgetBucket, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		b.mu.Lock()
+		got := len(b.waiters)
+		b.mu.Unlock()
+		if got == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("expected %d waiters", n)
+}
+
+func TestRateLimits_InteractivePreemptsBatch(t *testing.T) {
+	limits := NewRateLimits().Set(ProviderOpenAI, "", RateBudget{RPM: 1})
+	bucket := limits.buckets[rateKey{"openai", ""}]
+	advance := fakeBucketClock(bucket)
+	ctx := context.Background()
+
+	if _, err := limits.Wait(ctx, "openai", ModelGPT4o, 0, PriorityNormal); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan string, 2)
+	batchCtx, cancelBatch := context.WithCancel(ctx)
+	var batchErr error
+	go func() {
+		_, batchErr = limits.Wait(batchCtx, "openai", ModelGPT4o, 0, PriorityBatch)
+		done <- "batch"
+	}()
+	waitForWaiters(t, bucket, 1)
+	go func() {
+		if _, err := limits.Wait(ctx, "openai", ModelGPT4oMini, 0, PriorityInteractive); err == nil {
+			done <- "interactive"
+		}
+	}()
+	waitForWaiters(t, bucket, 2)
+
+	// One request refills; the later interactive request gets it.
+	advance(time.Minute)
+	select {
+	case got := <-done:
+		if got != "interactive" {
+			t.Fatalf("expected interactive first, got %s", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no request was admitted")
+	}
+	select {
+	case <-done:
+		t.Fatal("batch request should still be waiting")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	cancelBatch()
+	<-done
+	if !errors.Is(batchErr, context.Canceled) {
+		t.Fatalf("expected cancelled wait, got %v", batchErr)
+	}
+	waitForWaiters(t, bucket, 0)
+}
+
+func TestRateLimits_CancelledWaitRefundsTakenBuckets(t *testing.T) {
+	limits := NewRateLimits().
+		Set(ProviderOpenAI, "", RateBudget{RPM: 2, TPM: 1000}).
+		Set(ProviderOpenAI, ModelGPT4o, RateBudget{RPM: 1})
+	provider := limits.buckets[rateKey{"openai", ""}]
+	fakeBucketClock(provider)
+	fakeBucketClock(limits.buckets[rateKey{"openai", ModelGPT4o}])
+
+	if _, err := limits.Wait(context.Background(), "openai", ModelGPT4o, 100, PriorityNormal); err != nil {
+		t.Fatal(err)
+	}
+	// The provider bucket admits this one, then the model bucket gives up.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := limits.Wait(ctx, "openai", ModelGPT4o, 100, PriorityNormal); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+
+	provider.mu.Lock()
+	requests, tokens := provider.requests, provider.tokens
+	provider.mu.Unlock()
+	if requests != 1 || tokens != 900 {
+		t.Fatalf("expected the cancelled wait to be refunded, got %v requests and %v tokens", requests, tokens)
+	}
+}
+
+func TestRateLimits_TokenBudgetReconciledByBuilder(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+	oldLimits := Limits
+	defer func() { Limits = oldLimits }()
+
+	Limits = NewRateLimits().Set(ProviderOpenAI, ModelGPT4o, RateBudget{TPM: 1000})
+	bucket := Limits.buckets[rateKey{"openai", ModelGPT4o}]
+	fakeBucketClock(bucket)
+
+	p := &stubProvider{name: "openai", sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+		return &ProviderResponse{Content: "ok", TotalTokens: 300}, nil
+	}}
+	setDefaultClientForTest(t, p, ProviderOpenAI)
+
+	if meta := New(ModelGPT4o).User("hi").SendWithMeta(); meta.Error != nil {
+		t.Fatal(meta.Error)
+	}
+	bucket.mu.Lock()
+	left := bucket.tokens
+	bucket.mu.Unlock()
+	if left != 700 {
+		t.Fatalf("expected the estimate to be reconciled to 300 used tokens, %v left", left)
+	}
+
+	// Models without a budget are not limited.
+	if meta := New(ModelGPT4oMini).User("hi").SendWithMeta(); meta.Error != nil {
+		t.Fatal(meta.Error)
+	}
+
+	// A request that does not fit gives up when its context ends.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := Limits.Wait(ctx, "openai", ModelGPT4o, 900, PriorityInteractive); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestTokenBucket_WaitContext(t *testing.T) {
+	tb := NewLimiter(1, time.Hour)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := tb.WaitContext(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := tb.WaitContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestBatch_PriorityAppliedWhenSending(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	p := &stubProvider{name: "openai"}
+	setDefaultClientForTest(t, p, ProviderOpenAI)
+
+	plain, chosen := New(ModelGPT4o).User("a"), New(ModelGPT4o).User("b").Priority(PriorityInteractive)
+	Batch(plain, chosen).Do()
+	if plain.priority != PriorityNormal || chosen.priority != PriorityInteractive || plain.ctx != nil {
+		t.Fatalf("expected the batch to leave builders alone, got priorities %d and %d", plain.priority, chosen.priority)
+	}
+
+	ctx := withDefaultPriority(context.Background(), PriorityBatch)
+	if got := plain.requestPriority(ctx); got != PriorityBatch {
+		t.Fatalf("expected the batch priority, got %d", got)
+	}
+	if got := chosen.requestPriority(ctx); got != PriorityInteractive {
+		t.Fatalf("expected the builder's own priority, got %d", got)
+	}
+}
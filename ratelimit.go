@@ -1,6 +1,11 @@
 package ai
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
 	"sync"
 	"time"
 )
@@ -19,6 +24,12 @@ type Limiter interface {
 	Allow() bool // Check if request is allowed without blocking
 }
 
+// ContextLimiter is implemented by limiters that can stop waiting when the
+// request's context is done (TokenBucket does). Other limiters block in Wait.
+type ContextLimiter interface {
+	WaitContext(ctx context.Context) error
+}
+
 // ═══════════════════════════════════════════════════════════════════════════
 // Token Bucket Rate Limiter
 // ═══════════════════════════════════════════════════════════════════════════
@@ -68,6 +79,25 @@ func (tb *TokenBucket) Wait() {
 	}
 }
 
+// WaitContext blocks until a request is allowed or ctx is done.
+func (tb *TokenBucket) WaitContext(ctx context.Context) error {
+	for {
+		if tb.Allow() {
+			return nil
+		}
+		tb.mu.Lock()
+		waitTime := time.Duration((1.0 / tb.rate) * float64(time.Second))
+		tb.mu.Unlock()
+		timer := time.NewTimer(waitTime)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
 // Allow checks if a request is allowed (non-blocking).
 func (tb *TokenBucket) Allow() bool {
 	tb.mu.Lock()
@@ -116,13 +146,260 @@ func (cl *ConcurrencyLimiter) Release() {
 	<-cl.sem
 }
 
+// ═══════════════════════════════════════════════════════════════════════════
+// Provider Rate-Limit Headers
+// ═══════════════════════════════════════════════════════════════════════════
+
+// RateLimitInfo is the provider's view of the remaining quota, read from
+// the x-ratelimit-* (OpenAI and compatible APIs) or anthropic-ratelimit-*
+// response headers. A zero limit means the provider did not report it.
+type RateLimitInfo struct {
+	RequestsLimit     int
+	RequestsRemaining int
+	RequestsReset     time.Duration // until the request quota is fully restored
+	TokensLimit       int
+	TokensRemaining   int
+	TokensReset       time.Duration // until the token quota is fully restored
+	RetryAfter        time.Duration // Retry-After, if present
+}
+
+// parseRateLimitHeaders returns nil when h has no rate-limit headers.
+func parseRateLimitHeaders(h http.Header) *RateLimitInfo {
+	info := &RateLimitInfo{RetryAfter: parseRetryAfter(h)}
+
+	// OpenAI: x-ratelimit-remaining-requests, reset as a Go-style duration ("6m0s")
+	info.RequestsLimit = headerInt(h, "X-Ratelimit-Limit-Requests")
+	info.RequestsRemaining = headerInt(h, "X-Ratelimit-Remaining-Requests")
+	info.RequestsReset = headerDuration(h, "X-Ratelimit-Reset-Requests")
+	info.TokensLimit = headerInt(h, "X-Ratelimit-Limit-Tokens")
+	info.TokensRemaining = headerInt(h, "X-Ratelimit-Remaining-Tokens")
+	info.TokensReset = headerDuration(h, "X-Ratelimit-Reset-Tokens")
+
+	// Anthropic: anthropic-ratelimit-requests-remaining, reset as RFC 3339
+	if h.Get("Anthropic-Ratelimit-Requests-Limit") != "" {
+		info.RequestsLimit = headerInt(h, "Anthropic-Ratelimit-Requests-Limit")
+		info.RequestsRemaining = headerInt(h, "Anthropic-Ratelimit-Requests-Remaining")
+		info.RequestsReset = headerUntil(h, "Anthropic-Ratelimit-Requests-Reset")
+	}
+	for _, prefix := range []string{"Anthropic-Ratelimit-Tokens", "Anthropic-Ratelimit-Input-Tokens"} {
+		if h.Get(prefix+"-Limit") != "" {
+			info.TokensLimit = headerInt(h, prefix+"-Limit")
+			info.TokensRemaining = headerInt(h, prefix+"-Remaining")
+			info.TokensReset = headerUntil(h, prefix+"-Reset")
+			break
+		}
+	}
+
+	if info.RequestsLimit == 0 && info.TokensLimit == 0 && info.RetryAfter == 0 {
+		return nil
+	}
+	return info
+}
+
+// wait returns how long to hold off before the next request: Retry-After,
+// or the reset time of a quota that is used up.
+func (r *RateLimitInfo) wait() time.Duration {
+	d := r.RetryAfter
+	if r.RequestsLimit > 0 && r.RequestsRemaining == 0 && r.RequestsReset > d {
+		d = r.RequestsReset
+	}
+	if r.TokensLimit > 0 && r.TokensRemaining == 0 && r.TokensReset > d {
+		d = r.TokensReset
+	}
+	return d
+}
+
+// pace returns the spacing that spreads a nearly used up quota (under 10%
+// left) over the time until it resets, so callers slow down before a 429.
+func (r *RateLimitInfo) pace() time.Duration {
+	var d time.Duration
+	if r.RequestsLimit > 0 && r.RequestsRemaining > 0 && r.RequestsRemaining*10 < r.RequestsLimit {
+		d = r.RequestsReset / time.Duration(r.RequestsRemaining)
+	}
+	if r.TokensLimit > 0 && r.TokensRemaining > 0 && r.TokensRemaining*10 < r.TokensLimit {
+		if t := r.TokensReset / time.Duration(r.TokensRemaining); t > d {
+			d = t
+		}
+	}
+	return d
+}
+
+func headerInt(h http.Header, key string) int {
+	n, _ := strconv.Atoi(h.Get(key))
+	return n
+}
+
+func headerDuration(h http.Header, key string) time.Duration {
+	d, _ := time.ParseDuration(h.Get(key))
+	return d
+}
+
+func headerUntil(h http.Header, key string) time.Duration {
+	t, err := time.Parse(time.RFC3339, h.Get(key))
+	if err != nil {
+		return 0
+	}
+	if d := time.Until(t); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Server-Advised Limiter
+// ═══════════════════════════════════════════════════════════════════════════
+
+// ServerLimits is shared by all clients unless they set WithServerLimits. It
+// learns each provider's remaining quota per model from response headers and
+// holds requests back when the quota is used up or nearly so. Pooled keys are
+// tracked separately. Set it to nil to ignore rate-limit headers.
+var ServerLimits = NewServerLimiter()
+
+// ServerLimiter delays requests per provider and model based on RateLimitInfo.
+type ServerLimiter struct {
+	mu     sync.Mutex
+	limits map[serverLimitKey]*serverLimit
+	now    func() time.Time
+}
+
+type serverLimitKey struct {
+	provider string // provider name, or pool member label
+	model    string
+}
+
+type serverLimit struct {
+	until    time.Time     // no requests before this
+	next     time.Time     // earliest slot for the next paced request
+	interval time.Duration // pacing between requests
+}
+
+// NewServerLimiter creates an empty ServerLimiter.
+func NewServerLimiter() *ServerLimiter {
+	return &ServerLimiter{limits: map[serverLimitKey]*serverLimit{}, now: time.Now}
+}
+
+// Update records the rate-limit state reported by provider for model.
+func (l *ServerLimiter) Update(provider, model string, info *RateLimitInfo) {
+	if info == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	key := serverLimitKey{provider, model}
+	s, ok := l.limits[key]
+	if !ok {
+		s = &serverLimit{}
+		l.limits[key] = s
+	}
+	now := l.now()
+	if until := now.Add(info.wait()); until.After(s.until) {
+		s.until = until
+	}
+	s.interval = info.pace()
+}
+
+// Delay returns how long the next request to provider for model would wait.
+func (l *ServerLimiter) Delay(provider, model string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	s, ok := l.limits[serverLimitKey{provider, model}]
+	if !ok {
+		return 0
+	}
+	at := s.until
+	if s.next.After(at) {
+		at = s.next
+	}
+	if d := at.Sub(l.now()); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// Wait blocks until a request to provider for model is allowed or ctx is done.
+func (l *ServerLimiter) Wait(ctx context.Context, provider, model string) error {
+	l.mu.Lock()
+	s, ok := l.limits[serverLimitKey{provider, model}]
+	if !ok {
+		l.mu.Unlock()
+		return nil
+	}
+	now := l.now()
+	at := now
+	if s.until.After(at) {
+		at = s.until
+	}
+	if s.next.After(at) {
+		at = s.next
+	}
+	s.next = at.Add(s.interval)
+	l.mu.Unlock()
+
+	d := at.Sub(now)
+	if d <= 0 {
+		return nil
+	}
+	if debugOn(ctx) {
+		fmt.Printf("%s [%s] waiting %v for rate limit reset\n", colorYellow("⏳"), provider, d.Round(time.Millisecond))
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// withServerLimits waits for ServerLimits, runs send and feeds the reported
+// rate-limit headers back into ServerLimits. A PoolProvider applies the
+// limits to each member itself, since every key has its own quota.
+func withServerLimits(ctx context.Context, provider Provider, model string, send func() (*ProviderResponse, error)) (*ProviderResponse, error) {
+	if mp, ok := provider.(*middlewareProvider); ok {
+		provider = mp.Provider
+	}
+	if _, ok := provider.(*PoolProvider); ok {
+		return send()
+	}
+	return serverLimited(ctx, provider.Name(), model, send)
+}
+
+// serverLimited is withServerLimits for a provider or pool member name.
+func serverLimited(ctx context.Context, name, model string, send func() (*ProviderResponse, error)) (*ProviderResponse, error) {
+	limits := settingsFrom(ctx).serverLimits
+	if limits == nil {
+		return send()
+	}
+	if err := limits.Wait(ctx, name, model); err != nil {
+		return nil, err
+	}
+	resp, err := send()
+	if resp != nil {
+		limits.Update(name, model, resp.RateLimit)
+	}
+	var pe *ProviderError
+	if errors.As(err, &pe) {
+		limits.Update(name, model, pe.RateLimit)
+	}
+	return resp, err
+}
+
 // ═══════════════════════════════════════════════════════════════════════════
 // Builder Integration
 // ═══════════════════════════════════════════════════════════════════════════
 
-// waitForRateLimit waits if a global rate limiter is set
-func waitForRateLimit() {
-	if RateLimiter != nil {
-		RateLimiter.Wait()
+// waitForRateLimit waits if the request's rate limiter (RateLimiter unless
+// the client or builder sets one) is set
+func waitForRateLimit(ctx context.Context) error {
+	switch l := settingsFrom(ctx).limiter.(type) {
+	case nil:
+		return nil
+	case ContextLimiter:
+		return l.WaitContext(ctx)
+	default:
+		l.Wait()
+		return nil
 	}
 }
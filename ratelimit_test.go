@@ -0,0 +1,88 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimitHeaders_ExposedOnMetaAndShared(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("x-ratelimit-limit-requests", "500")
+		w.Header().Set("x-ratelimit-remaining-requests", "0")
+		w.Header().Set("x-ratelimit-reset-requests", "1m30s")
+		w.Header().Set("x-ratelimit-limit-tokens", "30000")
+		w.Header().Set("x-ratelimit-remaining-tokens", "29000")
+		w.Header().Set("x-ratelimit-reset-tokens", "2s")
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"content":"ok"},"finish_reason":"stop"}]}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(ProviderOpenAI, WithAPIKey("k"), WithBaseURL(srv.URL))
+	meta := c.New(ModelGPT4o).User("hi").SendWithMeta()
+	if meta.Error != nil {
+		t.Fatal(meta.Error)
+	}
+	want := RateLimitInfo{
+		RequestsLimit: 500, RequestsRemaining: 0, RequestsReset: 90 * time.Second,
+		TokensLimit: 30000, TokensRemaining: 29000, TokensReset: 2 * time.Second,
+	}
+	if meta.RateLimit == nil || *meta.RateLimit != want {
+		t.Fatalf("unexpected rate limit %+v", meta.RateLimit)
+	}
+
+	// The exhausted request quota holds back the next caller until reset.
+	if d := ServerLimits.Delay("openai", string(ModelGPT4o)); d < 89*time.Second || d > 90*time.Second {
+		t.Fatalf("expected ~90s delay, got %v", d)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := ServerLimits.Wait(ctx, "openai", string(ModelGPT4o)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected Wait to block until ctx expires, got %v", err)
+	}
+	if d := ServerLimits.Delay("anthropic", string(ModelGPT4o)); d != 0 {
+		t.Fatalf("limits are per provider, got %v", d)
+	}
+	if d := ServerLimits.Delay("openai", string(ModelGPT5)); d != 0 {
+		t.Fatalf("limits are per model, got %v", d)
+	}
+}
+
+func TestRateLimitHeaders_AnthropicAndRetryAfterFallback(t *testing.T) {
+	h := http.Header{}
+	h.Set("anthropic-ratelimit-requests-limit", "50")
+	h.Set("anthropic-ratelimit-requests-remaining", "2")
+	h.Set("anthropic-ratelimit-requests-reset", time.Now().Add(time.Minute).UTC().Format(time.RFC3339))
+	h.Set("anthropic-ratelimit-input-tokens-limit", "40000")
+	h.Set("anthropic-ratelimit-input-tokens-remaining", "39000")
+	info := parseRateLimitHeaders(h)
+	if info == nil || info.RequestsLimit != 50 || info.RequestsRemaining != 2 || info.TokensLimit != 40000 {
+		t.Fatalf("unexpected info %+v", info)
+	}
+	if info.RequestsReset < 58*time.Second || info.RequestsReset > time.Minute {
+		t.Fatalf("unexpected reset %v", info.RequestsReset)
+	}
+	// 2 of 50 left: spread them over the remaining minute.
+	if p := info.pace(); p < 29*time.Second || p > 30*time.Second {
+		t.Fatalf("expected ~30s pacing, got %v", p)
+	}
+	if parseRateLimitHeaders(http.Header{}) != nil {
+		t.Fatal("expected nil without headers")
+	}
+
+	// A 429 without Retry-After waits for the exhausted quota to reset.
+	h = http.Header{}
+	h.Set("x-ratelimit-limit-tokens", "1000")
+	h.Set("x-ratelimit-remaining-tokens", "0")
+	h.Set("x-ratelimit-reset-tokens", "2.5s")
+	err := newHTTPError("openai", &http.Response{StatusCode: http.StatusTooManyRequests, Header: h}, []byte(`{}`))
+	if err.RetryAfter != 2500*time.Millisecond || !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("unexpected error %+v", err)
+	}
+}
@@ -2,6 +2,7 @@ package ai
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"math/rand"
@@ -24,7 +25,8 @@ type RetryConfig struct {
 	Multiplier    float64       // Exponential backoff multiplier (default: 2.0)
 	Jitter        float64       // Random jitter factor (0.0 - 1.0) to avoid thundering herd (default: 0.1)
 	RetryOnStatus []int         // List of HTTP status codes that trigger a retry
-	RetryOnErrors []string      // List of error substrings that trigger a retry
+	RetryOn       []error       // Error kinds that trigger a retry (e.g. ErrRateLimited)
+	RetryOnErrors []string      // Substrings that trigger a retry for errors with no known kind
 }
 
 // DefaultRetryConfig returns a sensible default configuration for most use cases.
@@ -43,6 +45,7 @@ func DefaultRetryConfig() *RetryConfig {
 			http.StatusServiceUnavailable,  // 503
 			http.StatusGatewayTimeout,      // 504
 		},
+		RetryOn: []error{ErrRateLimited, ErrServerError, ErrTimeout},
 		RetryOnErrors: []string{
 			"connection reset",
 			"connection refused",
@@ -125,42 +128,68 @@ func calculateBackoff(config *RetryConfig, attempt int) time.Duration {
 }
 
 // shouldRetry determines if an error is transient and should trigger a retry.
-// It checks against configured status codes and error messages.
+// Classified provider errors are matched by status code and kind; message
+// substrings are only consulted for errors of unknown kind (e.g. transport
+// failures).
 func shouldRetry(config *RetryConfig, err error) bool {
 	if config == nil || err == nil {
 		return false
 	}
 
-	errStr := strings.ToLower(err.Error())
+	// An open circuit won't close within a retry backoff
+	if errors.Is(err, ErrCircuitOpen) {
+		return false
+	}
 
-	// Check for status code matches
-	for _, status := range config.RetryOnStatus {
-		if strings.Contains(errStr, fmt.Sprintf("[%d]", status)) ||
-			strings.Contains(errStr, fmt.Sprintf("status %d", status)) ||
-			strings.Contains(errStr, strconv.Itoa(status)) {
-			return true
+	var pe *ProviderError
+	if errors.As(err, &pe) {
+		kind := pe.kind()
+		for _, k := range config.RetryOn {
+			if k == kind {
+				return true
+			}
+		}
+		// Permanent failures (auth, quota, invalid request, ...) are not
+		// retried even when their status is listed, e.g. a 429 for quota.
+		if kind != nil && !isTransient(kind) {
+			return false
+		}
+		status := pe.StatusCode
+		if status == 0 {
+			status, _ = strconv.Atoi(pe.Code)
+		}
+		for _, s := range config.RetryOnStatus {
+			if status != 0 && status == s {
+				return true
+			}
+		}
+		if kind != nil {
+			return false
+		}
+	} else {
+		for _, k := range config.RetryOn {
+			if errors.Is(err, k) {
+				return true
+			}
 		}
 	}
 
-	// Check for error substring matches
+	errStr := strings.ToLower(err.Error())
 	for _, substr := range config.RetryOnErrors {
 		if strings.Contains(errStr, strings.ToLower(substr)) {
 			return true
 		}
 	}
+	return false
+}
 
-	// Check for ProviderError with retryable status
-	if pe, ok := err.(*ProviderError); ok {
-		if code, err := strconv.Atoi(pe.Code); err == nil {
-			for _, status := range config.RetryOnStatus {
-				if code == status {
-					return true
-				}
-			}
-		}
+// retryAfter returns the delay a provider asked for, if any.
+func retryAfter(err error) time.Duration {
+	var pe *ProviderError
+	if errors.As(err, &pe) {
+		return pe.RetryAfter
 	}
-
-	return false
+	return 0
 }
 
 // ═══════════════════════════════════════════════════════════════════════════
@@ -198,9 +227,7 @@ func WithRetry[T any](ctx context.Context, config *RetryConfig, fn RetryFunc[T])
 
 		// Check if we should retry
 		if !shouldRetry(config, err) {
-			if Debug {
-				fmt.Printf("%s Not retrying: error not retryable\n", colorRed("✗"))
-			}
+			logRetrySkipped(ctx, err)
 			return zero, err
 		}
 
@@ -209,14 +236,17 @@ func WithRetry[T any](ctx context.Context, config *RetryConfig, fn RetryFunc[T])
 			break
 		}
 
-		// Calculate delay
+		// Calculate delay, waiting at least as long as the provider asked
 		delay := calculateBackoff(config, attempt)
-
-		if Debug {
-			fmt.Printf("%s Retry %d/%d after %v (error: %v)\n",
-				colorYellow("↻"), attempt+1, config.MaxRetries, delay.Round(time.Millisecond), err)
+		if after := retryAfter(err); after > delay {
+			delay = after
+			if delay > config.MaxDelay {
+				delay = config.MaxDelay
+			}
 		}
 
+		logRetry(ctx, attempt+1, config.MaxRetries, delay, err)
+
 		// Wait with context awareness
 		select {
 		case <-ctx.Done():
@@ -225,10 +255,7 @@ func WithRetry[T any](ctx context.Context, config *RetryConfig, fn RetryFunc[T])
 		}
 	}
 
-	if Debug {
-		fmt.Printf("%s All %d retries exhausted after %v\n",
-			colorRed("✗"), config.MaxRetries, time.Since(start).Round(time.Millisecond))
-	}
+	logRetryExhausted(ctx, config.MaxRetries, time.Since(start), lastErr)
 
 	return zero, fmt.Errorf("max retries (%d) exceeded: %w", config.MaxRetries, lastErr)
 }
@@ -296,6 +323,12 @@ func (c *RetryConfig) WithRetryOnStatus(codes ...int) *RetryConfig {
 	return c
 }
 
+// WithRetryOn adds error kinds (ErrRateLimited, ErrTimeout, ...) that should trigger a retry.
+func (c *RetryConfig) WithRetryOn(kinds ...error) *RetryConfig {
+	c.RetryOn = append(c.RetryOn, kinds...)
+	return c
+}
+
 // WithRetryOnError adds error message substrings that should trigger a retry.
 func (c *RetryConfig) WithRetryOnError(errors ...string) *RetryConfig {
 	c.RetryOnErrors = append(c.RetryOnErrors, errors...)
@@ -322,6 +355,7 @@ func AggressiveRetryConfig() *RetryConfig {
 			http.StatusServiceUnavailable,
 			http.StatusGatewayTimeout,
 		},
+		RetryOn: []error{ErrRateLimited, ErrServerError, ErrTimeout},
 		RetryOnErrors: []string{
 			"connection reset",
 			"connection refused",
@@ -347,6 +381,7 @@ func GentleRetryConfig() *RetryConfig {
 			http.StatusTooManyRequests,
 			http.StatusServiceUnavailable,
 		},
+		RetryOn: []error{ErrRateLimited, ErrServerError},
 		RetryOnErrors: []string{
 			"rate limit",
 			"overloaded",
@@ -0,0 +1,253 @@
+package ai
+
+import (
+	"context"
+	"log/slog"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Per-Client Settings
+// ═══════════════════════════════════════════════════════════════════════════
+
+// settings overrides the package-level Debug, Pretty, Cache, RateLimiter,
+// Limits, ServerLimits, Breaker, Tracing, Logger and global hooks for a
+// Client or a single Builder. Unset fields inherit.
+type settings struct {
+	debug        *bool
+	pretty       *bool
+	cache        *bool
+	limiter      Limiter
+	limits       *RateLimits
+	serverLimits *ServerLimiter
+	breaker      *CircuitBreaker
+	hooks        *Hooks
+	tracer       Tracer
+	traceContent *bool
+	logger       *slog.Logger
+	redaction    *Redaction
+}
+
+func (c *ProviderConfig) clientSettings() *settings {
+	if c.settings == nil {
+		c.settings = &settings{}
+	}
+	return c.settings
+}
+
+// WithDebug turns debug output on or off for this client, overriding Debug.
+func WithDebug(on bool) ClientOption {
+	return func(c *ProviderConfig) { c.clientSettings().debug = &on }
+}
+
+// WithPretty turns pretty response output on or off for this client, overriding Pretty.
+func WithPretty(on bool) ClientOption {
+	return func(c *ProviderConfig) { c.clientSettings().pretty = &on }
+}
+
+// WithCache turns response caching on or off for this client, overriding
+// Cache. The client gets its own cache, see Client.ClearCache.
+func WithCache(on bool) ClientOption {
+	return func(c *ProviderConfig) { c.clientSettings().cache = &on }
+}
+
+// WithRateLimiter limits this client's requests with l instead of RateLimiter.
+func WithRateLimiter(l Limiter) ClientOption {
+	return func(c *ProviderConfig) { c.clientSettings().limiter = l }
+}
+
+// WithLimits applies l's per-model budgets to this client instead of Limits.
+func WithLimits(l *RateLimits) ClientOption {
+	return func(c *ProviderConfig) { c.clientSettings().limits = l }
+}
+
+// WithServerLimits tracks this client's rate-limit headers in l instead of ServerLimits.
+func WithServerLimits(l *ServerLimiter) ClientOption {
+	return func(c *ProviderConfig) { c.clientSettings().serverLimits = l }
+}
+
+// WithBreaker trips this client's circuits in cb instead of Breaker.
+func WithBreaker(cb *CircuitBreaker) ClientOption {
+	return func(c *ProviderConfig) { c.clientSettings().breaker = cb }
+}
+
+// WithHooks sends this client's lifecycle events to h instead of the global hooks.
+func WithHooks(h *Hooks) ClientOption {
+	return func(c *ProviderConfig) { c.clientSettings().hooks = h }
+}
+
+// ClearCache empties the client's own cache (or the shared one when the
+// client has no Cache setting).
+func (c *Client) ClearCache() {
+	c.resolve(settings{}).store.clear()
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Builder Overrides
+// ═══════════════════════════════════════════════════════════════════════════
+
+// Pretty turns pretty response output on or off for this request.
+func (b *Builder) Pretty(on bool) *Builder {
+	b.settings.pretty = &on
+	return b
+}
+
+// Cache turns response caching on or off for this request. Requests with
+// tools are never cached.
+func (b *Builder) Cache(on bool) *Builder {
+	b.settings.cache = &on
+	return b
+}
+
+// Hooks sends this request's lifecycle events to h instead of the client's hooks.
+func (b *Builder) Hooks(h *Hooks) *Builder {
+	b.settings.hooks = h
+	return b
+}
+
+// RateLimiter limits this request with l instead of the client's limiter.
+func (b *Builder) RateLimiter(l Limiter) *Builder {
+	b.settings.limiter = l
+	return b
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Resolution
+// ═══════════════════════════════════════════════════════════════════════════
+
+// resolvedSettings is the effective configuration of one request.
+type resolvedSettings struct {
+	debug        bool
+	pretty       bool
+	cache        bool
+	limiter      Limiter
+	limits       *RateLimits
+	serverLimits *ServerLimiter
+	breaker      *CircuitBreaker
+	hooks        *Hooks
+	store        *responseCache
+	tracer       Tracer
+	traceContent bool
+	logger       *slog.Logger
+	redaction    *Redaction
+}
+
+// defaultSettings reads the package-level defaults.
+func defaultSettings() *resolvedSettings {
+	return &resolvedSettings{
+		debug:        Debug,
+		pretty:       Pretty,
+		cache:        Cache,
+		limiter:      RateLimiter,
+		limits:       Limits,
+		serverLimits: ServerLimits,
+		breaker:      Breaker,
+		hooks:        globalHooks,
+		store:        defaultCache,
+		tracer:       Tracing,
+		traceContent: TraceContent,
+		logger:       Logger,
+		redaction:    LogRedaction,
+	}
+}
+
+func (r *resolvedSettings) apply(s settings) {
+	if s.debug != nil {
+		r.debug = *s.debug
+	}
+	if s.pretty != nil {
+		r.pretty = *s.pretty
+	}
+	if s.cache != nil {
+		r.cache = *s.cache
+	}
+	if s.limiter != nil {
+		r.limiter = s.limiter
+	}
+	if s.limits != nil {
+		r.limits = s.limits
+	}
+	if s.serverLimits != nil {
+		r.serverLimits = s.serverLimits
+	}
+	if s.breaker != nil {
+		r.breaker = s.breaker
+	}
+	if s.hooks != nil {
+		r.hooks = s.hooks
+	}
+	if s.tracer != nil {
+		r.tracer = s.tracer
+	}
+	if s.traceContent != nil {
+		r.traceContent = *s.traceContent
+	}
+	if s.logger != nil {
+		r.logger = s.logger
+	}
+	if s.redaction != nil {
+		r.redaction = s.redaction
+	}
+}
+
+// resolve layers request overrides over the client's settings and the globals.
+// A nil client uses only the globals.
+func (c *Client) resolve(override settings) *resolvedSettings {
+	r := defaultSettings()
+	if c != nil {
+		r.apply(c.settings)
+		if c.cache != nil {
+			r.store = c.cache
+		}
+	}
+	r.apply(override)
+	return r
+}
+
+// settingsOf returns the settings of c, or of the default client when c is nil.
+func settingsOf(c *Client) *resolvedSettings {
+	if c == nil {
+		c = getDefaultClient()
+	}
+	return c.resolve(settings{})
+}
+
+// currentSettings returns the builder's effective settings with its client
+// (or the default client).
+func (b *Builder) currentSettings() *resolvedSettings {
+	client := b.client
+	if client == nil {
+		client = getDefaultClient()
+	}
+	return b.resolveSettings(client)
+}
+
+// resolveSettings returns the builder's effective settings.
+func (b *Builder) resolveSettings(client *Client) *resolvedSettings {
+	r := client.resolve(b.settings)
+	if b.debug {
+		r.debug = true
+	}
+	return r
+}
+
+type settingsKey struct{}
+
+// withSettings attaches a request's settings to ctx for the code it calls.
+func withSettings(ctx context.Context, s *resolvedSettings) context.Context {
+	return context.WithValue(ctx, settingsKey{}, s)
+}
+
+// settingsFrom returns the settings attached to ctx, or the globals.
+func settingsFrom(ctx context.Context) *resolvedSettings {
+	if ctx != nil {
+		if s, ok := ctx.Value(settingsKey{}).(*resolvedSettings); ok {
+			return s
+		}
+	}
+	return defaultSettings()
+}
+
+// debugOn reports whether debug output is enabled for the request in ctx.
+func debugOn(ctx context.Context) bool {
+	return settingsFrom(ctx).debug
+}
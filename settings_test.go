@@ -0,0 +1,95 @@
+package ai
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+type countingLimiter struct{ waits int32 }
+
+func (l *countingLimiter) Wait()       { atomic.AddInt32(&l.waits, 1) }
+func (l *countingLimiter) Allow() bool { return true }
+
+func TestClientSettings_IndependentHooksAndLimiters(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+	defer ClearHooks()
+
+	var global, own int32
+	OnBeforeRequest(func(Model, []Message) { atomic.AddInt32(&global, 1) })
+	hooks := NewHooks().OnBeforeRequest(func(Model, []Message) { atomic.AddInt32(&own, 1) })
+	limiter := &countingLimiter{}
+
+	ok := func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+		return &ProviderResponse{Content: "ok"}, nil
+	}
+	a := NewClientWithProvider(&stubProvider{name: "a", sendFn: ok}, WithHooks(hooks), WithRateLimiter(limiter), WithDebug(false))
+	b := NewClientWithProvider(&stubProvider{name: "b", sendFn: ok})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			a.New(ModelGPT4o).Debug().User("hi").SendWithMeta()
+		}()
+		go func() {
+			defer wg.Done()
+			b.New(ModelGPT4o).User("hi").SendWithMeta()
+		}()
+	}
+	wg.Wait()
+
+	if own != 10 || global != 10 {
+		t.Fatalf("expected 10 calls per hook set, got own=%d global=%d", own, global)
+	}
+	if limiter.waits != 10 {
+		t.Fatalf("expected only client a to use its limiter, got %d waits", limiter.waits)
+	}
+	if Debug {
+		t.Fatal("Builder.Debug must not change the global Debug")
+	}
+
+	// A builder override wins over the client's hooks.
+	var override int32
+	a.New(ModelGPT4o).Hooks(NewHooks().OnBeforeRequest(func(Model, []Message) { override++ })).User("hi").SendWithMeta()
+	if override != 1 || own != 10 {
+		t.Fatalf("expected builder hooks only, got override=%d own=%d", override, own)
+	}
+}
+
+func TestClientSettings_Cache(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	cachedP := &stubProvider{name: "cached", sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+		return &ProviderResponse{Content: "fresh"}, nil
+	}}
+	plainP := &stubProvider{name: "plain"}
+	cached := NewClientWithProvider(cachedP, WithCache(true))
+	plain := NewClientWithProvider(plainP)
+
+	for i := 0; i < 2; i++ {
+		cached.New(ModelGPT4o).User("hi").SendWithMeta()
+		plain.New(ModelGPT4o).User("hi").SendWithMeta()
+	}
+	if cachedP.Calls() != 1 || plainP.Calls() != 2 {
+		t.Fatalf("expected 1 cached and 2 plain calls, got %d and %d", cachedP.Calls(), plainP.Calls())
+	}
+	if meta := cached.New(ModelGPT4o).User("hi").SendWithMeta(); !meta.Cached || meta.Content != "fresh" {
+		t.Fatalf("expected a cache hit, got %+v", meta)
+	}
+	if CacheSize() != 0 {
+		t.Fatal("a client's cache must not fill the shared cache")
+	}
+
+	// Per-request opt-out, and clearing only this client's cache.
+	cached.New(ModelGPT4o).Cache(false).User("hi").SendWithMeta()
+	cached.ClearCache()
+	cached.New(ModelGPT4o).User("hi").SendWithMeta()
+	if cachedP.Calls() != 3 {
+		t.Fatalf("expected 3 calls, got %d", cachedP.Calls())
+	}
+}
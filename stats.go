@@ -24,7 +24,39 @@ type Stats struct {
 	TotalLatency     time.Duration
 	Errors           int
 	Retries          int
+	Fallbacks        int // Times a request moved on to its next fallback model
+	CacheHits        int
+	CacheMisses      int
 	ModelUsage       map[Model]int
+	Keys             map[string]KeyStats // Per pool member (see WithAPIKeys), keyed by masked label
+}
+
+// KeyStats holds usage for one API key or config in a key pool.
+type KeyStats struct {
+	Requests    int
+	Errors      int
+	Tokens      int
+	Quarantines int // Times the key was benched after a 401/429
+}
+
+// trackKey records one request served by a pool member.
+func trackKey(label string, tokens int, failed, quarantined bool) {
+	statsLock.Lock()
+	defer statsLock.Unlock()
+
+	if stats.Keys == nil {
+		stats.Keys = make(map[string]KeyStats)
+	}
+	k := stats.Keys[label]
+	k.Requests++
+	k.Tokens += tokens
+	if failed {
+		k.Errors++
+	}
+	if quarantined {
+		k.Quarantines++
+	}
+	stats.Keys[label] = k
 }
 
 // trackRequest records a request for stats
@@ -49,14 +81,22 @@ func trackRequest(meta *ResponseMeta) {
 func GetStats() Stats {
 	statsLock.Lock()
 	defer statsLock.Unlock()
-	return *stats
+	s := *stats
+	if stats.Keys != nil {
+		s.Keys = make(map[string]KeyStats, len(stats.Keys))
+		for label, k := range stats.Keys {
+			s.Keys[label] = k
+		}
+	}
+	return s
 }
 
 // ResetStats clears all statistics.
 func ResetStats() {
 	statsLock.Lock()
-	defer statsLock.Unlock()
 	stats = &Stats{}
+	statsLock.Unlock()
+	metrics.reset()
 }
 
 // PrintStats displays session statistics to stdout.
@@ -76,12 +116,26 @@ func PrintStats() {
 	if s.Retries > 0 {
 		fmt.Printf("  Retries:      %d\n", s.Retries)
 	}
+	if s.Fallbacks > 0 {
+		fmt.Printf("  Fallbacks:    %d\n", s.Fallbacks)
+	}
+	if lookups := s.CacheHits + s.CacheMisses; lookups > 0 {
+		fmt.Printf("  Cache:        %d hits, %d misses (%.0f%%)\n",
+			s.CacheHits, s.CacheMisses, float64(s.CacheHits)/float64(lookups)*100)
+	}
 	if len(s.ModelUsage) > 0 {
 		fmt.Println("  Models Used:")
 		for model, count := range s.ModelUsage {
 			fmt.Printf("    - %s: %d\n", model, count)
 		}
 	}
+	if len(s.Keys) > 0 {
+		fmt.Println("  Keys:")
+		for label, k := range s.Keys {
+			fmt.Printf("    - %s: %d requests, %d errors, %d tokens, %d quarantines\n",
+				label, k.Requests, k.Errors, k.Tokens, k.Quarantines)
+		}
+	}
 	fmt.Println(colorCyan("═══════════════════════════════════════════════════════════════"))
 	fmt.Println()
 }
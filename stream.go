@@ -36,30 +36,40 @@ func (b *Builder) StreamResponse(callback StreamCallback) (string, error) {
 		Messages:    msgs,
 		Temperature: b.temperature,
 		Thinking:    b.thinking,
+		MaxTokens:   b.maxTokens,
 		Tools:       b.tools,
 		JSONMode:    b.jsonMode,
 		Stream:      true,
 	}
 
-	// Get context
-	ctx := b.ctx
-	if ctx == nil {
-		ctx = context.Background()
-	}
+	// Get context with this request's settings
+	set := b.resolveSettings(client)
+	ctx := withSettings(b.getContext(), set)
 
-	if Debug {
-		printDebugRequest(b.model, msgs)
+	provider := client.providerFor(b.model)
+	msgs, err := b.prepareDocuments(ctx, client, provider, b.model, msgs, nil)
+	if err != nil {
+		return "", err
 	}
+	if err := b.checkModelSupport(b.model, msgs); err != nil {
+		return "", err
+	}
+	req.Messages = msgs
+
+	logRequest(ctx, provider.Name(), b.model, msgs)
 
 	// Check streaming capability
-	if !client.provider.Capabilities().Streaming {
-		if Debug {
+	if !provider.Capabilities().Streaming {
+		if set.debug {
 			fmt.Printf("%s Warning: %s does not support streaming, falling back to regular request\n",
-				colorYellow("⚠"), client.provider.Name())
+				colorYellow("⚠"), provider.Name())
 		}
 		// Fallback to non-streaming
-		waitForRateLimit()
-		resp, err := client.provider.Send(ctx, req)
+		resp, err := b.limitedSend(ctx, provider, b.model, msgs, func() (*ProviderResponse, error) {
+			return instrumentedSend(ctx, provider, req, 0, func(ctx context.Context) (*ProviderResponse, error) {
+				return provider.Send(ctx, req)
+			})
+		})
 		if err != nil {
 			return "", err
 		}
@@ -67,18 +77,21 @@ func (b *Builder) StreamResponse(callback StreamCallback) (string, error) {
 		return resp.Content, nil
 	}
 
-	if Pretty {
+	if set.pretty {
 		fmt.Printf("\n%s %s\n", colorCyan("▸"), colorDim(string(b.model)))
 		fmt.Println(colorDim("─────────────────────────────────────────────────────────────"))
 	}
 
-	waitForRateLimit()
-	resp, err := client.provider.SendStream(ctx, req, callback)
+	resp, err := b.limitedSend(ctx, provider, b.model, msgs, func() (*ProviderResponse, error) {
+		return instrumentedSend(ctx, provider, req, 0, func(ctx context.Context) (*ProviderResponse, error) {
+			return provider.SendStream(ctx, req, metrics.timeFirstChunk(provider.Name(), req.Model, callback))
+		})
+	})
 	if err != nil {
 		return "", err
 	}
 
-	if Pretty {
+	if set.pretty {
 		fmt.Println()
 		fmt.Println()
 	}
@@ -112,22 +125,32 @@ func (b *Builder) StreamWithMeta(callback StreamCallback) (*ResponseMeta, error)
 		Messages:    msgs,
 		Temperature: b.temperature,
 		Thinking:    b.thinking,
+		MaxTokens:   b.maxTokens,
 		Tools:       b.tools,
 		JSONMode:    b.jsonMode,
 		Stream:      true,
 	}
 
-	ctx := b.ctx
-	if ctx == nil {
-		ctx = context.Background()
-	}
+	set := b.resolveSettings(client)
+	ctx := withSettings(b.getContext(), set)
 
-	if Debug {
-		printDebugRequest(b.model, msgs)
+	provider := client.providerFor(b.model)
+	msgs, err := b.prepareDocuments(ctx, client, provider, b.model, msgs, nil)
+	if err == nil {
+		err = b.checkModelSupport(b.model, msgs)
+	}
+	if err != nil {
+		return &ResponseMeta{Error: err, Model: b.model, Latency: time.Since(start)}, err
 	}
+	req.Messages = msgs
+
+	logRequest(ctx, provider.Name(), b.model, msgs)
 
-	waitForRateLimit()
-	resp, err := client.provider.SendStream(ctx, req, callback)
+	resp, err := b.limitedSend(ctx, provider, b.model, msgs, func() (*ProviderResponse, error) {
+		return instrumentedSend(ctx, provider, req, 0, func(ctx context.Context) (*ProviderResponse, error) {
+			return provider.SendStream(ctx, req, metrics.timeFirstChunk(provider.Name(), req.Model, callback))
+		})
+	})
 	if err != nil {
 		return &ResponseMeta{Error: err, Model: b.model, Latency: time.Since(start)}, err
 	}
@@ -139,6 +162,7 @@ func (b *Builder) StreamWithMeta(callback StreamCallback) (*ResponseMeta, error)
 		Tokens:           resp.TotalTokens,
 		PromptTokens:     resp.PromptTokens,
 		CompletionTokens: resp.CompletionTokens,
+		RateLimit:        resp.RateLimit,
 	}
 
 	trackRequest(meta)
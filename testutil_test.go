@@ -17,24 +17,38 @@ func withTestGlobals(t *testing.T) func() {
 	oldDebug := Debug
 	oldCache := Cache
 	oldDefaultProvider := DefaultProvider
+	oldServerLimits := ServerLimits
 
 	// Keep tests quiet and deterministic.
 	Pretty = false
 	Debug = false
 	Cache = false
 
-	// Avoid cross-test leakage from cached clients.
+	// Avoid cross-test leakage from cached clients and learned rate limits.
 	ResetClients()
+	ServerLimits = NewServerLimiter()
 
 	return func() {
 		Pretty = oldPretty
 		Debug = oldDebug
 		Cache = oldCache
 		SetDefaultProvider(oldDefaultProvider)
+		ServerLimits = oldServerLimits
 		ResetClients()
 	}
 }
 
+// withProviderBaseURL points a registered provider at url for the rest of the test.
+func withProviderBaseURL(t *testing.T, name ProviderType, url string) {
+	t.Helper()
+	factory, _ := lookupProviderFactory(name)
+	RegisterProvider(name, func(c ProviderConfig) Provider {
+		c.BaseURL = url
+		return factory(c)
+	})
+	t.Cleanup(func() { RegisterProvider(name, factory) })
+}
+
 func setDefaultClientForTest(t *testing.T, provider Provider, providerType ProviderType) {
 	t.Helper()
 	SetDefaultClient(&Client{
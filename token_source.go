@@ -0,0 +1,116 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// OAuth2 Token Sources
+// ═══════════════════════════════════════════════════════════════════════════
+
+// TokenSource supplies bearer tokens for providers that use OAuth2
+// (Vertex AI, Azure Entra ID).
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// TokenSourceFunc adapts a function to TokenSource.
+type TokenSourceFunc func(ctx context.Context) (string, error)
+
+// Token calls f.
+func (f TokenSourceFunc) Token(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// StaticTokenSource returns a source that always yields token.
+func StaticTokenSource(token string) TokenSource {
+	return TokenSourceFunc(func(context.Context) (string, error) {
+		return token, nil
+	})
+}
+
+// tokenRefreshWindow is how long before expiry a cached token is replaced.
+const tokenRefreshWindow = time.Minute
+
+// cachedTokenSource reuses a fetched token until shortly before it expires.
+type cachedTokenSource struct {
+	fetch func(ctx context.Context) (string, time.Time, error)
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// NewCachedTokenSource wraps fetch, which returns a token and its expiry, so
+// that a new token is only minted when the cached one is about to expire.
+// A zero expiry means the token never expires.
+func NewCachedTokenSource(fetch func(ctx context.Context) (token string, expiry time.Time, err error)) TokenSource {
+	return &cachedTokenSource{fetch: fetch}
+}
+
+// Token returns the cached token or fetches a fresh one.
+func (s *cachedTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && (s.expiry.IsZero() || time.Now().Add(tokenRefreshWindow).Before(s.expiry)) {
+		return s.token, nil
+	}
+	token, expiry, err := s.fetch(ctx)
+	if err != nil {
+		return "", err
+	}
+	s.token, s.expiry = token, expiry
+	return token, nil
+}
+
+// requestOAuthToken posts an OAuth2 token request and returns the access
+// token with its expiry.
+func requestOAuthToken(ctx context.Context, tokenURL string, form url.Values) (string, time.Time, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("token request failed: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", time.Time{}, fmt.Errorf("token request failed (%d): %s", resp.StatusCode, body)
+	}
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", time.Time{}, fmt.Errorf("invalid token response: %w", err)
+	}
+	if result.AccessToken == "" {
+		return "", time.Time{}, errors.New("token response has no access_token")
+	}
+	return result.AccessToken, time.Now().Add(time.Duration(result.ExpiresIn) * time.Second), nil
+}
+
+// WithTokenSource sets the OAuth2 token source used for bearer auth.
+func WithTokenSource(ts TokenSource) ClientOption {
+	return func(c *ProviderConfig) {
+		c.TokenSource = ts
+	}
+}
@@ -0,0 +1,643 @@
+package ai
+
+import (
+	"bufio"
+	"compress/gzip"
+	"container/heap"
+	"embed"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// BPE Encodings
+// ═══════════════════════════════════════════════════════════════════════════
+
+// Encoding is a byte-level BPE vocabulary in tiktoken's rank-file format
+// (one "<base64 token> <rank>" pair per line), e.g. o200k_base or cl100k_base.
+type Encoding struct {
+	Name    string
+	ranks   map[string]int
+	decoder map[int]string
+	split   func(string) []string
+}
+
+var (
+	encodingsMu sync.RWMutex
+	encodings   = map[string]*Encoding{}
+)
+
+// bundled holds OpenAI's o200k_base and cl100k_base rank files (MIT, from
+// tiktoken), gzipped. They are parsed on first use.
+//
+//go:embed encodings/*.tiktoken.gz
+var bundled embed.FS
+
+type bundledEncoding struct {
+	once sync.Once
+	enc  *Encoding
+}
+
+var bundledEncodings = map[string]*bundledEncoding{
+	"o200k_base":  {},
+	"cl100k_base": {},
+}
+
+// loadBundled returns a bundled encoding, or nil if there is none by that name.
+func loadBundled(name string) *Encoding {
+	b := bundledEncodings[name]
+	if b == nil {
+		return nil
+	}
+	b.once.Do(func() {
+		f, err := bundled.Open("encodings/" + name + ".tiktoken.gz")
+		if err != nil {
+			return
+		}
+		defer f.Close()
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			return
+		}
+		b.enc, _ = LoadEncoding(name, zr)
+	})
+	return b.enc
+}
+
+// LoadEncoding parses a tiktoken rank file. Names starting with "o200k" use
+// o200k's pre-tokenizer, others cl100k's.
+func LoadEncoding(name string, r io.Reader) (*Encoding, error) {
+	enc := &Encoding{Name: name, ranks: map[string]int{}, decoder: map[int]string{}, split: splitPieces}
+	if strings.HasPrefix(name, "o200k") {
+		enc.split = splitO200k
+	}
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; sc.Scan(); line++ {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("encoding %s: line %d: expected \"<token> <rank>\"", name, line)
+		}
+		token, err := base64.StdEncoding.DecodeString(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("encoding %s: line %d: %w", name, line, err)
+		}
+		rank, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("encoding %s: line %d: %w", name, line, err)
+		}
+		enc.ranks[string(token)] = rank
+		enc.decoder[rank] = string(token)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("encoding %s: %w", name, err)
+	}
+	return enc, nil
+}
+
+// RegisterEncoding makes an encoding available to the token counter under
+// enc.Name. o200k_base and cl100k_base are bundled; registering one of those
+// names replaces the bundled copy.
+//
+//	f, _ := os.Open("o200k_harmony.tiktoken")
+//	enc, _ := ai.LoadEncoding("o200k_harmony", f)
+//	ai.RegisterEncoding(enc)
+func RegisterEncoding(enc *Encoding) {
+	encodingsMu.Lock()
+	defer encodingsMu.Unlock()
+	encodings[enc.Name] = enc
+}
+
+// GetEncoding returns a registered or bundled encoding, or nil.
+func GetEncoding(name string) *Encoding {
+	encodingsMu.RLock()
+	enc := encodings[name]
+	encodingsMu.RUnlock()
+	if enc != nil {
+		return enc
+	}
+	return loadBundled(name)
+}
+
+// Encode returns the token ranks for text. Bytes missing from the
+// vocabulary encode as -1 (only possible with incomplete rank files).
+func (e *Encoding) Encode(text string) []int {
+	split := e.split
+	if split == nil {
+		split = splitPieces
+	}
+	var out []int
+	for _, piece := range split(text) {
+		out = e.bpe([]byte(piece), out)
+	}
+	return out
+}
+
+// Decode turns token ranks back into text.
+func (e *Encoding) Decode(tokens []int) string {
+	var sb strings.Builder
+	for _, t := range tokens {
+		sb.WriteString(e.decoder[t])
+	}
+	return sb.String()
+}
+
+// Count returns the number of tokens in text.
+func (e *Encoding) Count(text string) int {
+	return len(e.Encode(text))
+}
+
+// bpe merges the lowest-ranked adjacent pair (leftmost on ties) until no
+// pair is in the vocabulary. Candidate merges sit in a heap and parts form a
+// linked list, so long pieces cost O(n log n) rather than O(n²).
+func (e *Encoding) bpe(piece []byte, out []int) []int {
+	if rank, ok := e.ranks[string(piece)]; ok {
+		return append(out, rank)
+	}
+	// Parts are identified by their start offset; next links them in order.
+	n := len(piece)
+	next, prev := make([]int, n), make([]int, n)
+	merged := make([]bool, n)
+	for i := range next {
+		next[i], prev[i] = i+1, i-1
+	}
+	var h bpeHeap
+	candidate := func(left int) {
+		if mid := next[left]; mid < n {
+			if rank, ok := e.ranks[string(piece[left:next[mid]])]; ok {
+				heap.Push(&h, bpeMerge{rank: rank, left: left, end: next[mid]})
+			}
+		}
+	}
+	for i := 0; i < n; i++ {
+		candidate(i)
+	}
+	for h.Len() > 0 {
+		m := heap.Pop(&h).(bpeMerge)
+		// Skip merges made stale by an earlier merge of either part.
+		if merged[m.left] || next[m.left] >= n || next[next[m.left]] != m.end {
+			continue
+		}
+		merged[next[m.left]] = true
+		next[m.left] = m.end
+		if m.end < n {
+			prev[m.end] = m.left
+		}
+		if p := prev[m.left]; p >= 0 {
+			candidate(p)
+		}
+		candidate(m.left)
+	}
+	for i := 0; i < n; i = next[i] {
+		rank, ok := e.ranks[string(piece[i:next[i]])]
+		if !ok {
+			rank = -1
+		}
+		out = append(out, rank)
+	}
+	return out
+}
+
+// bpeMerge is a candidate merge of the part starting at left with the part
+// after it, ending at end.
+type bpeMerge struct {
+	rank, left, end int
+}
+
+// bpeHeap orders merges by rank, then position.
+type bpeHeap []bpeMerge
+
+func (h bpeHeap) Len() int { return len(h) }
+func (h bpeHeap) Less(i, j int) bool {
+	if h[i].rank != h[j].rank {
+		return h[i].rank < h[j].rank
+	}
+	return h[i].left < h[j].left
+}
+func (h bpeHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *bpeHeap) Push(x any)   { *h = append(*h, x.(bpeMerge)) }
+func (h *bpeHeap) Pop() any {
+	old := *h
+	m := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return m
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Pre-tokenization
+// ═══════════════════════════════════════════════════════════════════════════
+
+// splitPieces splits text the way tiktoken's cl100k pattern does:
+// contractions, words with one leading non-letter, 1-3 digit groups,
+// punctuation runs, and whitespace (the last space sticks to the next word).
+func splitPieces(text string) []string {
+	var pieces []string
+	for i := 0; i < len(text); {
+		n := pieceLen(text[i:])
+		pieces = append(pieces, text[i:i+n])
+		i += n
+	}
+	return pieces
+}
+
+func pieceLen(s string) int {
+	r, size := utf8.DecodeRuneInString(s)
+
+	// 's 't 're 've 'm 'll 'd
+	if r == '\'' {
+		lower := strings.ToLower(s[1:minInt(len(s), 3)])
+		for _, c := range []string{"re", "ve", "ll", "s", "t", "m", "d"} {
+			if strings.HasPrefix(lower, c) {
+				return 1 + len(c)
+			}
+		}
+	}
+
+	// Letters, optionally with one leading non-letter/non-digit (" hello", "_id")
+	if unicode.IsLetter(r) {
+		return size + spanRunes(s[size:], unicode.IsLetter)
+	}
+	if r != '\r' && r != '\n' && !unicode.IsNumber(r) && size < len(s) {
+		if next, _ := utf8.DecodeRuneInString(s[size:]); unicode.IsLetter(next) {
+			return size + spanRunes(s[size:], unicode.IsLetter)
+		}
+	}
+
+	// Up to three digits
+	if unicode.IsNumber(r) {
+		n := size
+		for digits := 1; digits < 3 && n < len(s); digits++ {
+			next, nsize := utf8.DecodeRuneInString(s[n:])
+			if !unicode.IsNumber(next) {
+				break
+			}
+			n += nsize
+		}
+		return n
+	}
+
+	// Optional space, punctuation run, trailing newlines
+	start := 0
+	if r == ' ' && size < len(s) {
+		start = size
+	}
+	if p := spanRunes(s[start:], isPunct); p > 0 {
+		n := start + p
+		return n + spanRunes(s[n:], func(r rune) bool { return r == '\r' || r == '\n' })
+	}
+
+	return whitespaceLen(s, size)
+}
+
+// whitespaceLen matches \s*[\r\n]+ | \s+(?!\S) | \s+ at the start of s,
+// or one rune of size bytes if s doesn't start with whitespace.
+func whitespaceLen(s string, size int) int {
+	// Whitespace: up to the last newline, otherwise leave one space for the next word
+	ws := spanRunes(s, unicode.IsSpace)
+	if last := strings.LastIndexAny(s[:ws], "\r\n"); last >= 0 {
+		return last + 1
+	}
+	if ws < len(s) {
+		if _, lastSize := utf8.DecodeLastRuneInString(s[:ws]); ws > lastSize {
+			return ws - lastSize
+		}
+	}
+	if ws == 0 {
+		return size
+	}
+	return ws
+}
+
+// splitO200k splits text the way tiktoken's o200k pattern does. Unlike
+// cl100k, words split before upper-case runs ("camel", "Case"), contractions
+// stay attached to their word, and "/" joins trailing newlines after punctuation.
+func splitO200k(text string) []string {
+	var pieces []string
+	for i := 0; i < len(text); {
+		n := o200kPieceLen(text[i:])
+		pieces = append(pieces, text[i:i+n])
+		i += n
+	}
+	return pieces
+}
+
+func o200kPieceLen(s string) int {
+	r, size := utf8.DecodeRuneInString(s)
+
+	// [^\r\n\p{L}\p{N}]? then upper* lower+ or upper+ lower*, then a contraction
+	prefixes := []int{0}
+	if r != '\r' && r != '\n' && !unicode.IsLetter(r) && !unicode.IsNumber(r) {
+		prefixes = []int{size, 0}
+	}
+	for _, p := range prefixes {
+		if n := o200kWord(s, p, true); n > 0 {
+			return n
+		}
+	}
+	for _, p := range prefixes {
+		if n := o200kWord(s, p, false); n > 0 {
+			return n
+		}
+	}
+
+	// Up to three digits
+	if unicode.IsNumber(r) {
+		n := size
+		for digits := 1; digits < 3 && n < len(s); digits++ {
+			next, nsize := utf8.DecodeRuneInString(s[n:])
+			if !unicode.IsNumber(next) {
+				break
+			}
+			n += nsize
+		}
+		return n
+	}
+
+	// Optional space, punctuation run, trailing newlines and slashes
+	start := 0
+	if r == ' ' && size < len(s) {
+		start = size
+	}
+	if p := spanRunes(s[start:], isPunct); p > 0 {
+		n := start + p
+		return n + spanRunes(s[n:], func(r rune) bool { return r == '\r' || r == '\n' || r == '/' })
+	}
+
+	return whitespaceLen(s, size)
+}
+
+// o200kWord matches upper*lower+ (lowerRequired) or upper+lower* at s[p:]
+// plus an optional contraction, returning the end or 0.
+func o200kWord(s string, p int, lowerRequired bool) int {
+	upperEnds := runeEnds(s, p, isO200kUpper)
+	if lowerRequired {
+		// Backtrack the greedy upper run until at least one lower rune follows
+		for k := len(upperEnds) - 1; k >= 0; k-- {
+			if lower := spanRunes(s[upperEnds[k]:], isO200kLower); lower > 0 {
+				return withContraction(s, upperEnds[k]+lower)
+			}
+		}
+		return 0
+	}
+	if len(upperEnds) < 2 {
+		return 0
+	}
+	end := upperEnds[len(upperEnds)-1]
+	return withContraction(s, end+spanRunes(s[end:], isO200kLower))
+}
+
+// runeEnds returns p and the end offset of each rune in the run of f from p.
+func runeEnds(s string, p int, f func(rune) bool) []int {
+	ends := []int{p}
+	for n := p; n < len(s); {
+		r, size := utf8.DecodeRuneInString(s[n:])
+		if !f(r) {
+			break
+		}
+		n += size
+		ends = append(ends, n)
+	}
+	return ends
+}
+
+// withContraction extends end over a following 's 't 're 've 'm 'll 'd (any case).
+func withContraction(s string, end int) int {
+	if end < len(s) && s[end] == '\'' {
+		lower := strings.ToLower(s[end+1 : minInt(len(s), end+3)])
+		for _, c := range []string{"s", "t", "re", "ve", "m", "ll", "d"} {
+			if strings.HasPrefix(lower, c) {
+				return end + 1 + len(c)
+			}
+		}
+	}
+	return end
+}
+
+func isO200kUpper(r rune) bool {
+	return unicode.In(r, unicode.Lu, unicode.Lt, unicode.Lm, unicode.Lo, unicode.M)
+}
+
+func isO200kLower(r rune) bool {
+	return unicode.In(r, unicode.Ll, unicode.Lm, unicode.Lo, unicode.M)
+}
+
+func isPunct(r rune) bool {
+	return !unicode.IsSpace(r) && !unicode.IsLetter(r) && !unicode.IsNumber(r)
+}
+
+func spanRunes(s string, f func(rune) bool) int {
+	n := 0
+	for n < len(s) {
+		r, size := utf8.DecodeRuneInString(s[n:])
+		if !f(r) {
+			break
+		}
+		n += size
+	}
+	return n
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Token Counting
+// ═══════════════════════════════════════════════════════════════════════════
+
+// Calibration factors relative to o200k_base for tokenizers that aren't public.
+// Claude's tokenizer yields noticeably more tokens than o200k on English and code;
+// Gemini's SentencePiece vocabulary is close to o200k.
+var tokenCalibration = map[ProviderType]float64{
+	ProviderOpenAI:    1.0,
+	ProviderAnthropic: 1.2,
+	ProviderGoogle:    1.05,
+}
+
+// Per-message framing tokens (role markers) and reply priming.
+const (
+	tokensPerMessage = 3
+	tokensPerReply   = 3
+)
+
+// CountTokens counts the tokens in text for model. OpenAI models are counted
+// exactly with the bundled o200k_base/cl100k_base encodings; other vendors are
+// calibrated from o200k_base.
+func CountTokens(model Model, text string) int {
+	return countText(vendorForModel(model), model, text)
+}
+
+// CountMessageTokens counts the prompt tokens of a message list for model,
+// including per-message overhead, tool calls and image inputs.
+func CountMessageTokens(model Model, msgs []Message) int {
+	return countMessages(vendorForModel(model), model, msgs)
+}
+
+// CountTokens returns the prompt token count of the request as it would be sent:
+// system prompt, context, messages, images and tool definitions.
+// Attached documents are counted only once extracted to text (see ExtractDocuments).
+func (b *Builder) CountTokens() int {
+	return b.countTokensFor(b.model, b.buildMessages())
+}
+
+func (b *Builder) countTokensFor(model Model, msgs []Message) int {
+	vendor := b.providerType()
+	if vendor != ProviderAnthropic && vendor != ProviderGoogle {
+		vendor = vendorForModel(model)
+	}
+	total := countMessages(vendor, model, msgs)
+	if len(b.tools) > 0 {
+		if data, err := json.Marshal(b.tools); err == nil {
+			total += countText(vendor, model, string(data))
+		}
+	}
+	return total
+}
+
+func countMessages(vendor ProviderType, model Model, msgs []Message) int {
+	total := tokensPerReply
+	for _, m := range msgs {
+		total += tokensPerMessage
+		switch c := m.Content.(type) {
+		case string:
+			total += countText(vendor, model, c)
+		case []ContentPart:
+			for _, part := range c {
+				switch {
+				case part.Type == "text":
+					total += countText(vendor, model, part.Text)
+				case part.ImageURL != nil:
+					w, h := dataURIDimensions(part.ImageURL.URL)
+					if w == 0 || h == 0 {
+						w, h = 1024, 1024 // remote image, size unknown
+					}
+					total += EstimateImageTokens(vendor, w, h, ImageDetail(part.ImageURL.Detail))
+				}
+			}
+		}
+		for _, tc := range m.ToolCalls {
+			total += countText(vendor, model, tc.Function.Name+tc.Function.Arguments)
+		}
+	}
+	return total
+}
+
+func countText(vendor ProviderType, model Model, text string) int {
+	if text == "" {
+		return 0
+	}
+	factor, ok := tokenCalibration[vendor]
+	if !ok {
+		factor = 1.0
+	}
+	if vendor == ProviderOpenAI || vendor == "" {
+		if enc := GetEncoding(openAIEncodingFor(model)); enc != nil {
+			return enc.Count(text)
+		}
+	}
+	if enc := GetEncoding("o200k_base"); enc != nil {
+		return int(math.Ceil(float64(enc.Count(text)) * factor))
+	}
+	return int(math.Ceil(approximateTokens(text) * factor))
+}
+
+// approximateTokens estimates an o200k-like count without a vocabulary.
+// Common words are a single token; longer pieces cost about one token per
+// 4 ASCII bytes, and non-ASCII characters roughly one token each.
+func approximateTokens(text string) float64 {
+	var total float64
+	for _, piece := range splitPieces(text) {
+		ascii, other := 0, 0
+		for _, r := range piece {
+			if r < utf8.RuneSelf {
+				ascii++
+			} else {
+				other++
+			}
+		}
+		cost := float64(other)
+		if ascii > 0 {
+			cost += 1 + math.Max(0, float64(ascii)-6)/4
+		}
+		total += cost
+	}
+	return total
+}
+
+// vendorForModel infers the tokenizer family from a model ID.
+func vendorForModel(model Model) ProviderType {
+	id := strings.ToLower(string(model))
+	switch {
+	case strings.HasPrefix(id, "anthropic/") || strings.Contains(id, "claude"):
+		return ProviderAnthropic
+	case strings.HasPrefix(id, "google/") || strings.Contains(id, "gemini"):
+		return ProviderGoogle
+	default:
+		return ProviderOpenAI
+	}
+}
+
+// openAIEncodingFor returns the tiktoken encoding name used by an OpenAI model.
+func openAIEncodingFor(model Model) string {
+	id := string(model)
+	if i := strings.LastIndex(id, "/"); i >= 0 {
+		id = id[i+1:]
+	}
+	if strings.HasPrefix(id, "gpt-3.5") || id == "gpt-4" || strings.HasPrefix(id, "gpt-4-") {
+		return "cl100k_base"
+	}
+	return "o200k_base"
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Context Window Preflight
+// ═══════════════════════════════════════════════════════════════════════════
+
+// ErrContextTooLong is returned (wrapped in *ContextTooLongError) when a request
+// will not fit in the model's context window.
+var ErrContextTooLong = errors.New("context too long")
+
+// ContextTooLongError reports a request rejected before it was sent.
+type ContextTooLongError struct {
+	Model     Model
+	Tokens    int // prompt tokens (counted offline)
+	MaxOutput int // tokens reserved for the response
+	Limit     int // model context window
+}
+
+// Error implements the error interface.
+func (e *ContextTooLongError) Error() string {
+	return fmt.Sprintf("%s: ~%d prompt tokens + %d max output exceeds the %d-token context window",
+		e.Model, e.Tokens, e.MaxOutput, e.Limit)
+}
+
+// Unwrap returns ErrContextTooLong so errors.Is works.
+func (e *ContextTooLongError) Unwrap() error {
+	return ErrContextTooLong
+}
+
+// checkContextWindow returns a *ContextTooLongError if msgs plus the reserved
+// output don't fit in model's window. Unknown models are not checked.
+func (b *Builder) checkContextWindow(model Model, msgs []Message) error {
+	limit := ContextWindow(model)
+	if limit == 0 {
+		return nil
+	}
+	maxOutput := b.maxTokens
+	if maxOutput == 0 && vendorForModel(model) == ProviderAnthropic && b.providerType() == ProviderAnthropic {
+		maxOutput = anthropicDefaultMaxTokens
+	}
+	tokens := b.countTokensFor(model, msgs)
+	if tokens+maxOutput > limit {
+		return &ContextTooLongError{Model: model, Tokens: tokens, MaxOutput: maxOutput, Limit: limit}
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package ai
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testEncoding(t *testing.T, name string) *Encoding {
+	t.Helper()
+	var sb strings.Builder
+	for rank, tok := range []string{"a", "b", "c", " ", "ab", " a"} {
+		fmt.Fprintf(&sb, "%s %d\n", base64.StdEncoding.EncodeToString([]byte(tok)), rank)
+	}
+	enc, err := LoadEncoding(name, strings.NewReader(sb.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return enc
+}
+
+func TestSplitPieces(t *testing.T) {
+	got := splitPieces("Hello world's  42345 !!\n\nok")
+	want := []string{"Hello", " world", "'s", " ", " ", "423", "45", " !!\n\n", "ok"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected pieces:\n got %q\nwant %q", got, want)
+	}
+}
+
+func TestEncodingBPE(t *testing.T) {
+	enc := testEncoding(t, "test")
+
+	tokens := enc.Encode("abc ab")
+	if !reflect.DeepEqual(tokens, []int{4, 2, 3, 4}) {
+		t.Fatalf("unexpected tokens: %v", tokens)
+	}
+	if enc.Decode(tokens) != "abc ab" {
+		t.Fatalf("round trip failed: %q", enc.Decode(tokens))
+	}
+}
+
+func TestBundledEncodingsAreExact(t *testing.T) {
+	// Token IDs from tiktoken
+	cases := []struct {
+		encoding, text string
+		want           []int
+	}{
+		{"o200k_base", "Hello world! CamelCase isn't HTTPServer.", []int{13225, 2375, 0, 112127, 6187, 12471, 21929, 6444, 13}},
+		{"o200k_base", "日本語 テキスト", []int{9048, 40909, 131230, 18368, 38236}},
+		{"cl100k_base", "Hello world! CamelCase isn't HTTPServer.", []int{9906, 1917, 0, 69254, 4301, 4536, 956, 10339, 5592, 13}},
+		{"cl100k_base", "日本語 テキスト", []int{9080, 22656, 45918, 252, 21370, 228, 62903, 71634}},
+	}
+	for _, c := range cases {
+		enc := GetEncoding(c.encoding)
+		if enc == nil {
+			t.Fatalf("%s is not bundled", c.encoding)
+		}
+		if got := enc.Encode(c.text); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s %q: got %v, want %v", c.encoding, c.text, got, c.want)
+		}
+	}
+	if n := CountTokens(ModelGPT4o, "Hello world! CamelCase isn't HTTPServer."); n != 9 {
+		t.Fatalf("expected exact count 9 by default, got %d", n)
+	}
+}
+
+func TestEncodingBPE_LongPieces(t *testing.T) {
+	// Quadratic merging took seconds for runs like these.
+	enc := GetEncoding("o200k_base")
+	for _, text := range []string{strings.Repeat("abcdefghij", 2_000), strings.Repeat(" ", 20_000)} {
+		tokens := enc.Encode(text)
+		if len(tokens) == 0 || len(tokens) >= len(text) || enc.Decode(tokens) != text {
+			t.Fatalf("unexpected encoding of a %d-byte run: %d tokens", len(text), len(tokens))
+		}
+	}
+}
+
+func TestSplitPieces_MultibyteSpace(t *testing.T) {
+	// A lone multi-byte space before a word is its own piece
+	for _, split := range []func(string) []string{splitPieces, splitO200k} {
+		if got := split("a\u3000b\u202fc"); !reflect.DeepEqual(got, []string{"a", "\u3000b", "\u202fc"}) {
+			t.Fatalf("unexpected pieces %q", got)
+		}
+	}
+}
+
+func TestCountTokensUsesRegisteredEncoding(t *testing.T) {
+	RegisterEncoding(testEncoding(t, "o200k_base"))
+	defer func() {
+		encodingsMu.Lock()
+		delete(encodings, "o200k_base")
+		encodingsMu.Unlock()
+	}()
+
+	if n := CountTokens(ModelGPT4o, "abc ab"); n != 4 {
+		t.Fatalf("expected exact o200k count 4, got %d", n)
+	}
+	if n := CountTokens(ModelClaudeSonnet, "abc ab"); n != 5 {
+		t.Fatalf("expected calibrated Claude count 5, got %d", n)
+	}
+}
+
+func TestBuilderCountTokensIncludesTools(t *testing.T) {
+	b := New(ModelGPT4o).System("You are terse.").User("What's the weather in Paris?")
+	plain := b.CountTokens()
+	b.Tool("get_weather", "Get the weather for a city", Params().String("city", "City name", true).Build())
+	if withTools := b.CountTokens(); withTools <= plain {
+		t.Fatalf("expected tool definitions to add tokens: %d <= %d", withTools, plain)
+	}
+}
+
+func TestSendWithMeta_PreflightContextTooLong(t *testing.T) {
+	defer withTestGlobals(t)()
+
+	sp := &stubProvider{name: "stub"}
+	client := &Client{provider: sp, providerType: ProviderOpenAI}
+
+	meta := client.New(ModelGPT4o).MaxTokens(130_000).User("hi").SendWithMeta()
+	if !errors.Is(meta.Error, ErrContextTooLong) {
+		t.Fatalf("expected ErrContextTooLong, got %v", meta.Error)
+	}
+	var ctxErr *ContextTooLongError
+	if !errors.As(meta.Error, &ctxErr) || ctxErr.Limit != 128_000 || ctxErr.MaxOutput != 130_000 {
+		t.Fatalf("unexpected error details: %+v", ctxErr)
+	}
+	if len(sp.Requests()) != 0 {
+		t.Fatal("expected no request to be sent")
+	}
+
+	// A fallback with a larger window is tried instead
+	meta = client.New(ModelGPT4o).Fallback(ModelGPT41).MaxTokens(130_000).User("hi").SendWithMeta()
+	if meta.Error != nil || meta.Model != ModelGPT41 {
+		t.Fatalf("expected fallback to succeed, got %v on %s", meta.Error, meta.Model)
+	}
+	if reqs := sp.Requests(); len(reqs) != 1 || reqs[0].MaxTokens != 130_000 {
+		t.Fatalf("expected one request with MaxTokens, got %+v", reqs)
+	}
+}
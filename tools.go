@@ -1,6 +1,7 @@
 package ai
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 )
@@ -235,26 +236,43 @@ func (r *ToolResponse) HasToolCalls() bool {
 // SendWithTools executes the request and returns a ToolResponse.
 // This is used for manual handling of tool calls. For automatic execution, use RunTools.
 func (b *Builder) SendWithTools() (*ToolResponse, error) {
-	msgs := b.buildMessages()
+	client := b.client
+	if client == nil {
+		client = getDefaultClient()
+	}
+	set := b.resolveSettings(client)
+	ctx := withSettings(b.getContext(), set)
+
+	provider := client.providerFor(b.model)
+	msgs, err := b.prepareDocuments(ctx, client, provider, b.model, b.buildMessages(), nil)
+	if err != nil {
+		return nil, err
+	}
 
-	content, resp, toolCalls, err := SendWithTools(b.model, msgs, b.tools, SendOptions{
+	req := &ProviderRequest{
+		Model:       string(b.model),
+		Messages:    msgs,
 		Temperature: b.temperature,
 		Thinking:    b.thinking,
+		Tools:       b.tools,
+	}
+	logRequest(ctx, provider.Name(), b.model, msgs)
+
+	resp, err := b.limitedSend(ctx, provider, b.model, msgs, func() (*ProviderResponse, error) {
+		return instrumentedSend(ctx, provider, req, 0, func(ctx context.Context) (*ProviderResponse, error) {
+			return provider.Send(ctx, req)
+		})
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	result := &ToolResponse{
-		Content:   content,
-		ToolCalls: toolCalls,
+	return &ToolResponse{
+		Content:   resp.Content,
+		ToolCalls: resp.ToolCalls,
 		Model:     b.model,
-	}
-	if resp != nil {
-		result.Tokens = resp.Usage.TotalTokens
-	}
-
-	return result, nil
+		Tokens:    resp.TotalTokens,
+	}, nil
 }
 
 // RunTools executes the request in an "agentic" loop.
@@ -273,37 +291,15 @@ func (b *Builder) RunTools(maxIterations int) (string, error) {
 
 		// No tool calls = we're done
 		if !resp.HasToolCalls() {
-			if Pretty {
-				printPrettyResponse(b.model, resp.Content)
-			}
+			logAnswer(withSettings(b.getContext(), b.currentSettings()), b.model, "", resp.Content)
 			return resp.Content, nil
 		}
 
 		// Execute each tool call
 		for _, tc := range resp.ToolCalls {
-			handler, ok := b.toolHandlers[tc.Function.Name]
-			if !ok {
-				return "", fmt.Errorf("no handler for tool: %s", tc.Function.Name)
-			}
-
-			// Parse arguments
-			var args map[string]any
-			if err := json.Unmarshal([]byte(tc.Function.Arguments), &args); err != nil {
-				return "", fmt.Errorf("invalid tool arguments: %w", err)
-			}
-
-			if Debug {
-				fmt.Printf("%s Calling tool: %s(%v)\n", colorYellow("🔧"), tc.Function.Name, args)
-			}
-
-			// Execute handler
-			result, err := handler(args)
+			result, err := b.executeToolCall(tc)
 			if err != nil {
-				result = fmt.Sprintf("Error: %v", err)
-			}
-
-			if Debug {
-				fmt.Printf("%s Tool result: %s\n", colorGreen("✓"), truncate(result, 100))
+				return "", err
 			}
 
 			// Add tool result to conversation
@@ -324,6 +320,33 @@ func (b *Builder) RunTools(maxIterations int) (string, error) {
 	return "", fmt.Errorf("max tool iterations (%d) reached", maxIterations)
 }
 
+// executeToolCall runs the registered handler for a tool call.
+// Handler errors are returned to the model as the result text; a missing
+// handler or malformed arguments are returned as errors.
+func (b *Builder) executeToolCall(tc ToolCall) (string, error) {
+	set := b.currentSettings()
+
+	handler, ok := b.toolHandlers[tc.Function.Name]
+	if !ok {
+		return "", fmt.Errorf("no handler for tool: %s", tc.Function.Name)
+	}
+
+	// Parse arguments
+	var args map[string]any
+	if err := json.Unmarshal([]byte(tc.Function.Arguments), &args); err != nil {
+		return "", fmt.Errorf("invalid tool arguments: %w", err)
+	}
+
+	// Execute handler
+	result, err := traceTool(withSettings(b.getContext(), set), tc, func() (string, error) {
+		return handler(args)
+	})
+	if err != nil {
+		result = fmt.Sprintf("Error: %v", err)
+	}
+	return result, nil
+}
+
 func truncate(s string, max int) string {
 	if len(s) <= max {
 		return s
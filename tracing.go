@@ -0,0 +1,315 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Tracing
+// ═══════════════════════════════════════════════════════════════════════════
+
+// Tracing optionally records spans for requests, retries, fallbacks, tool
+// calls and agent steps, with gen_ai.* attributes from the OpenTelemetry
+// GenAI semantic conventions. It is the default for clients without WithTracer.
+var Tracing Tracer
+
+// TraceContent adds prompts, completions and tool arguments/results to spans.
+// Off by default because they may contain sensitive data.
+var TraceContent = false
+
+// Tracer starts spans. It mirrors OpenTelemetry's trace.Tracer, so an adapter
+// is a few lines; the returned context must carry the new span.
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is an operation in progress.
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	RecordError(err error)
+	End()
+}
+
+// Attribute is a span attribute. Values are strings, bools, ints, float64s
+// or []string.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// Attr creates an Attribute.
+func Attr(key string, value any) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// WithTracer records this client's spans with t instead of Tracing.
+func WithTracer(t Tracer) ClientOption {
+	return func(c *ProviderConfig) { c.clientSettings().tracer = t }
+}
+
+// WithTraceContent turns prompt and completion capture on or off for this
+// client, overriding TraceContent.
+func WithTraceContent(on bool) ClientOption {
+	return func(c *ProviderConfig) { c.clientSettings().traceContent = &on }
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Span Helpers (called internally)
+// ═══════════════════════════════════════════════════════════════════════════
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...Attribute) {}
+func (noopSpan) RecordError(error)          {}
+func (noopSpan) End()                       {}
+
+// startSpan starts a span with the request's tracer. Without one it returns
+// ctx unchanged and a no-op span.
+func startSpan(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	tracer := settingsFrom(ctx).tracer
+	if tracer == nil {
+		return ctx, noopSpan{}
+	}
+	ctx, span := tracer.Start(ctx, name)
+	if len(attrs) > 0 {
+		span.SetAttributes(attrs...)
+	}
+	return ctx, span
+}
+
+// endSpan records err (if any) and ends span.
+func endSpan(span Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetAttributes(Attr("error.type", errorType(err)))
+	}
+	span.End()
+}
+
+// errorType is a low-cardinality error.type: the HTTP status, or the error kind.
+func errorType(err error) string {
+	var pe *ProviderError
+	switch {
+	case errors.As(err, &pe) && pe.StatusCode != 0:
+		return strconv.Itoa(pe.StatusCode)
+	case errors.As(err, &pe) && pe.kind() != nil:
+		return pe.kind().Error()
+	case errors.Is(err, ErrCircuitOpen):
+		return "circuit open"
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return err.Error()
+	}
+	return "_OTHER"
+}
+
+// instrumentedSend runs one provider call in a "chat {model}" span, records
+// it in the request metrics and logs its outcome.
+func instrumentedSend(ctx context.Context, provider Provider, req *ProviderRequest, attempt int, send func(ctx context.Context) (*ProviderResponse, error)) (*ProviderResponse, error) {
+	done := metrics.startCall(provider.Name(), req.Model, attempt)
+	start := time.Now()
+	ctx, span := startSpan(ctx, "chat "+req.Model, requestAttributes(provider, req)...)
+	if attempt > 0 {
+		span.SetAttributes(Attr("ai.attempt", attempt))
+	}
+	if settingsFrom(ctx).traceContent {
+		span.SetAttributes(Attr("gen_ai.input.messages", jsonString(req.Messages)))
+	}
+	resp, err := send(ctx)
+	if resp != nil {
+		span.SetAttributes(responseAttributes(req.Model, resp)...)
+		if settingsFrom(ctx).traceContent {
+			span.SetAttributes(Attr("gen_ai.output.messages", jsonString([]Message{{Role: "assistant", Content: resp.Content, ToolCalls: resp.ToolCalls}})))
+		}
+	}
+	endSpan(span, err)
+	done(resp, err)
+	logResult(ctx, provider.Name(), req.Model, resp, err, time.Since(start))
+	return resp, err
+}
+
+// endSendSpan records the outcome of SendWithMeta on its span.
+func endSendSpan(span Span, meta *ResponseMeta) {
+	span.SetAttributes(
+		Attr("gen_ai.response.model", string(meta.Model)),
+		Attr("gen_ai.usage.input_tokens", meta.PromptTokens),
+		Attr("gen_ai.usage.output_tokens", meta.CompletionTokens),
+		Attr("ai.retries", meta.Retries),
+	)
+	if meta.Cached {
+		span.SetAttributes(Attr("ai.cached", true))
+	}
+	endSpan(span, meta.Error)
+}
+
+// traceTool runs a tool handler in an "execute_tool {name}" span and logs
+// the call and its result.
+func traceTool(ctx context.Context, tc ToolCall, run func() (string, error)) (string, error) {
+	ctx, span := startSpan(ctx, "execute_tool "+tc.Function.Name,
+		Attr("gen_ai.operation.name", "execute_tool"),
+		Attr("gen_ai.tool.name", tc.Function.Name),
+		Attr("gen_ai.tool.call.id", tc.ID),
+	)
+	content := settingsFrom(ctx).traceContent
+	if content {
+		span.SetAttributes(Attr("gen_ai.tool.call.arguments", tc.Function.Arguments))
+	}
+	logToolCall(ctx, tc)
+	result, err := run()
+	logToolResult(ctx, tc, result, err)
+	if content {
+		span.SetAttributes(Attr("gen_ai.tool.call.result", result))
+	}
+	endSpan(span, err)
+	return result, err
+}
+
+func requestAttributes(provider Provider, req *ProviderRequest) []Attribute {
+	attrs := []Attribute{
+		Attr("gen_ai.operation.name", "chat"),
+		Attr("gen_ai.system", provider.Name()),
+		Attr("gen_ai.request.model", req.Model),
+	}
+	if req.Temperature != nil {
+		attrs = append(attrs, Attr("gen_ai.request.temperature", *req.Temperature))
+	}
+	if req.MaxTokens > 0 {
+		attrs = append(attrs, Attr("gen_ai.request.max_tokens", req.MaxTokens))
+	}
+	if req.Stream {
+		attrs = append(attrs, Attr("ai.stream", true))
+	}
+	return attrs
+}
+
+// responseAttributes describes a provider response. The response model is the
+// one the provider reports (e.g. a dated snapshot of an alias), or the
+// requested model when it reports none.
+func responseAttributes(model string, resp *ProviderResponse) []Attribute {
+	if resp.Model != "" {
+		model = resp.Model
+	}
+	attrs := []Attribute{
+		Attr("gen_ai.response.model", model),
+		Attr("gen_ai.usage.input_tokens", resp.PromptTokens),
+		Attr("gen_ai.usage.output_tokens", resp.CompletionTokens),
+	}
+	if resp.FinishReason != "" {
+		attrs = append(attrs, Attr("gen_ai.response.finish_reasons", []string{resp.FinishReason}))
+	}
+	return attrs
+}
+
+func jsonString(v any) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// In-Memory Tracer
+// ═══════════════════════════════════════════════════════════════════════════
+
+// MemoryTracer keeps spans in memory, for tests and debugging.
+type MemoryTracer struct {
+	mu    sync.Mutex
+	spans []*RecordedSpan
+}
+
+// NewMemoryTracer creates an empty MemoryTracer.
+func NewMemoryTracer() *MemoryTracer {
+	return &MemoryTracer{}
+}
+
+// RecordedSpan is a span kept by MemoryTracer.
+type RecordedSpan struct {
+	Name   string
+	Parent *RecordedSpan // nil for root spans
+	Start  time.Time
+	End    time.Time // zero until ended
+	Err    error
+
+	tracer *MemoryTracer
+	attrs  map[string]any
+}
+
+type memorySpanKey struct{}
+
+// Start starts a span, a child of the MemoryTracer span in ctx (if any).
+func (t *MemoryTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	parent, _ := ctx.Value(memorySpanKey{}).(*RecordedSpan)
+	s := &RecordedSpan{Name: name, Parent: parent, Start: time.Now(), tracer: t, attrs: map[string]any{}}
+	t.mu.Lock()
+	t.spans = append(t.spans, s)
+	t.mu.Unlock()
+	return context.WithValue(ctx, memorySpanKey{}, s), &memorySpan{s}
+}
+
+// Spans returns all spans in start order.
+func (t *MemoryTracer) Spans() []*RecordedSpan {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]*RecordedSpan(nil), t.spans...)
+}
+
+// Find returns the spans with the given name.
+func (t *MemoryTracer) Find(name string) []*RecordedSpan {
+	var out []*RecordedSpan
+	for _, s := range t.Spans() {
+		if s.Name == name {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// Reset drops all recorded spans.
+func (t *MemoryTracer) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.spans = nil
+}
+
+// Attr returns the value of an attribute, or nil.
+func (s *RecordedSpan) Attr(key string) any {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	return s.attrs[key]
+}
+
+// Attributes returns a copy of the span's attributes.
+func (s *RecordedSpan) Attributes() map[string]any {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	out := make(map[string]any, len(s.attrs))
+	for k, v := range s.attrs {
+		out[k] = v
+	}
+	return out
+}
+
+// memorySpan is the Span handed out by MemoryTracer.
+type memorySpan struct{ s *RecordedSpan }
+
+func (m *memorySpan) SetAttributes(attrs ...Attribute) {
+	m.s.tracer.mu.Lock()
+	defer m.s.tracer.mu.Unlock()
+	for _, a := range attrs {
+		m.s.attrs[a.Key] = a.Value
+	}
+}
+
+func (m *memorySpan) RecordError(err error) {
+	m.s.tracer.mu.Lock()
+	defer m.s.tracer.mu.Unlock()
+	m.s.Err = err
+}
+
+func (m *memorySpan) End() {
+	m.s.tracer.mu.Lock()
+	defer m.s.tracer.mu.Unlock()
+	m.s.End = time.Now()
+}
@@ -0,0 +1,133 @@
+package ai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTracing_SendRetriesAndFallbacks(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	p := &stubProvider{name: "openai", sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+		if req.Model == string(ModelGPT4o) {
+			return nil, &ProviderError{Provider: "openai", StatusCode: 500, Kind: ErrServerError, Message: "boom"}
+		}
+		return &ProviderResponse{Content: "ok", PromptTokens: 7, CompletionTokens: 3, TotalTokens: 10, FinishReason: "stop"}, nil
+	}}
+	tracer := NewMemoryTracer()
+	c := NewClientWithProvider(p, WithTracer(tracer))
+
+	meta := c.New(ModelGPT4o).Fallback(ModelGPT4oMini).RetryConfig(noSleepRetryConfig(1)).User("secret prompt").SendWithMeta()
+	if meta.Error != nil {
+		t.Fatal(meta.Error)
+	}
+
+	root := tracer.Find("ai.send")
+	hops := tracer.Find("ai.fallback")
+	failed := tracer.Find("chat " + string(ModelGPT4o))
+	served := tracer.Find("chat " + string(ModelGPT4oMini))
+	if len(root) != 1 || len(hops) != 2 || len(failed) != 2 || len(served) != 1 {
+		t.Fatalf("unexpected spans: %d root, %d hops, %d failed, %d served", len(root), len(hops), len(failed), len(served))
+	}
+	if hops[0].Parent != root[0] || failed[1].Parent != hops[0] || served[0].Parent != hops[1] {
+		t.Fatal("attempts should nest under their hop, hops under the request")
+	}
+	if failed[1].Attr("ai.attempt") != 2 || failed[1].Attr("error.type") != "500" || failed[1].Err == nil {
+		t.Fatalf("unexpected failed attempt %v", failed[1].Attributes())
+	}
+	attrs := served[0].Attributes()
+	if attrs["gen_ai.system"] != "openai" || attrs["gen_ai.usage.input_tokens"] != 7 ||
+		attrs["gen_ai.usage.output_tokens"] != 3 || attrs["gen_ai.response.finish_reasons"].([]string)[0] != "stop" {
+		t.Fatalf("unexpected attempt attributes %v", attrs)
+	}
+	if root[0].Attr("gen_ai.response.model") != string(ModelGPT4oMini) || root[0].Attr("ai.retries") != 1 || root[0].End.IsZero() {
+		t.Fatalf("unexpected root span %v", root[0].Attributes())
+	}
+	if _, ok := attrs["gen_ai.input.messages"]; ok {
+		t.Fatal("content must not be captured by default")
+	}
+
+	// Opt-in content capture.
+	tracer.Reset()
+	c = NewClientWithProvider(p, WithTracer(tracer), WithTraceContent(true))
+	c.New(ModelGPT4oMini).User("secret prompt").SendWithMeta()
+	span := tracer.Find("chat " + string(ModelGPT4oMini))[0]
+	if in, _ := span.Attr("gen_ai.input.messages").(string); !strings.Contains(in, "secret prompt") {
+		t.Fatalf("expected captured prompt, got %q", in)
+	}
+	if out, _ := span.Attr("gen_ai.output.messages").(string); !strings.Contains(out, "ok") {
+		t.Fatalf("expected captured completion, got %q", out)
+	}
+}
+
+func TestTracing_AgentStepsToolsAndStreams(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	calls := 0
+	p := &stubProvider{name: "openai", caps: ProviderCapabilities{Streaming: true}, sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+		calls++
+		if calls == 1 {
+			tc := ToolCall{ID: "call_1", Type: "function"}
+			tc.Function.Name = "lookup"
+			tc.Function.Arguments = `{"q":"go"}`
+			return &ProviderResponse{ToolCalls: []ToolCall{tc}}, nil
+		}
+		return &ProviderResponse{Content: "Final Answer: done"}, nil
+	}}
+	tracer := NewMemoryTracer()
+	c := NewClientWithProvider(p, WithTracer(tracer))
+
+	result := c.New(ModelGPT4o).Agent().
+		Tool("lookup", "Look something up", nil, func(args map[string]any) (string, error) { return "found", nil }).
+		Run("find go")
+	if result.Error != nil {
+		t.Fatal(result.Error)
+	}
+
+	agent := tracer.Find("invoke_agent")
+	steps := tracer.Find("ai.agent.step")
+	tools := tracer.Find("execute_tool lookup")
+	chats := tracer.Find("chat " + string(ModelGPT4o))
+	if len(agent) != 1 || len(steps) != 2 || len(tools) != 1 || len(chats) != 2 {
+		t.Fatalf("unexpected spans: %d agent, %d steps, %d tools, %d chats", len(agent), len(steps), len(tools), len(chats))
+	}
+	if steps[0].Parent != agent[0] || tools[0].Parent != steps[0] || chats[1].Parent != steps[1] {
+		t.Fatal("chat and tool spans should nest under their agent step")
+	}
+	if tools[0].Attr("gen_ai.tool.call.id") != "call_1" || steps[1].Attr("ai.agent.step") != 2 {
+		t.Fatalf("unexpected attributes %v %v", tools[0].Attributes(), steps[1].Attributes())
+	}
+
+	tracer.Reset()
+	if _, err := c.New(ModelGPT4o).User("hi").StreamResponse(func(string) {}); err != nil {
+		t.Fatal(err)
+	}
+	if spans := tracer.Find("chat " + string(ModelGPT4o)); len(spans) != 1 || spans[0].Attr("ai.stream") != true {
+		t.Fatalf("expected one stream span, got %d", len(spans))
+	}
+}
+
+func TestTracing_ResponseModelFromProvider(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"model":"gpt-4o-2024-08-06","choices":[{"message":{"content":"ok"},"finish_reason":"stop"}]}`))
+	}))
+	defer srv.Close()
+
+	tracer := NewMemoryTracer()
+	c := NewClient(ProviderOpenAI, WithAPIKey("k"), WithBaseURL(srv.URL), WithTracer(tracer))
+	if meta := c.New(ModelGPT4o).User("hi").SendWithMeta(); meta.Error != nil {
+		t.Fatal(meta.Error)
+	}
+	span := tracer.Find("chat " + string(ModelGPT4o))[0]
+	if got := span.Attr("gen_ai.response.model"); got != "gpt-4o-2024-08-06" {
+		t.Fatalf("expected the model reported by the provider, got %v", got)
+	}
+}
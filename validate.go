@@ -309,30 +309,32 @@ func (v *wordCountValidator) Validate(content string) error {
 // runValidators runs all validators on the content.
 // It also applies content filters (WithFilter) in-place, so filters can transform output.
 func (b *Builder) runValidators(content string) (string, error) {
+	debug := b.currentSettings().debug
+
 	for _, v := range b.validators {
 		// Content filters can transform the content
 		if fv, ok := v.(*filterValidator); ok {
 			newContent, err := fv.filter(content)
 			if err != nil {
-				if Debug {
+				if debug {
 					fmt.Printf("%s Validation failed [%s]: %v\n", colorRed("✗"), v.Name(), err)
 				}
 				return content, err
 			}
 			content = newContent
-			if Debug {
+			if debug {
 				fmt.Printf("%s Validation passed [%s]\n", colorGreen("✓"), v.Name())
 			}
 			continue
 		}
 
 		if err := v.Validate(content); err != nil {
-			if Debug {
+			if debug {
 				fmt.Printf("%s Validation failed [%s]: %v\n", colorRed("✗"), v.Name(), err)
 			}
 			return content, err
 		}
-		if Debug {
+		if debug {
 			fmt.Printf("%s Validation passed [%s]\n", colorGreen("✓"), v.Name())
 		}
 	}
@@ -0,0 +1,264 @@
+package ai
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Google Service Account Auth
+// ═══════════════════════════════════════════════════════════════════════════
+
+// GoogleCloudScope is the OAuth2 scope used for Vertex AI.
+const GoogleCloudScope = "https://www.googleapis.com/auth/cloud-platform"
+
+const googleTokenURL = "https://oauth2.googleapis.com/token"
+
+// serviceAccountKey is the subset of a service-account JSON key we need.
+type serviceAccountKey struct {
+	Type         string `json:"type"`
+	ProjectID    string `json:"project_id"`
+	PrivateKeyID string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	ClientEmail  string `json:"client_email"`
+	TokenURI     string `json:"token_uri"`
+}
+
+// ServiceAccountTokenSource mints OAuth2 access tokens from a service-account
+// JSON key using a signed JWT assertion (RFC 7523). Tokens are cached and
+// refreshed shortly before they expire. No scopes means GoogleCloudScope.
+func ServiceAccountTokenSource(keyJSON []byte, scopes ...string) (TokenSource, error) {
+	key, err := parseServiceAccountKey(keyJSON)
+	if err != nil {
+		return nil, err
+	}
+	signer, err := parseRSAPrivateKey(key.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("service account %s: %w", key.ClientEmail, err)
+	}
+	if len(scopes) == 0 {
+		scopes = []string{GoogleCloudScope}
+	}
+
+	return NewCachedTokenSource(func(ctx context.Context) (string, time.Time, error) {
+		assertion, err := signServiceAccountJWT(key, signer, strings.Join(scopes, " "), time.Now())
+		if err != nil {
+			return "", time.Time{}, err
+		}
+		return exchangeJWTAssertion(ctx, key.TokenURI, assertion)
+	}), nil
+}
+
+// ServiceAccountTokenSourceFromFile is ServiceAccountTokenSource for a key file.
+func ServiceAccountTokenSourceFromFile(path string, scopes ...string) (TokenSource, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ServiceAccountTokenSource(data, scopes...)
+}
+
+func parseServiceAccountKey(keyJSON []byte) (*serviceAccountKey, error) {
+	var key serviceAccountKey
+	if err := json.Unmarshal(keyJSON, &key); err != nil {
+		return nil, fmt.Errorf("invalid service account key: %w", err)
+	}
+	if key.Type != "" && key.Type != "service_account" {
+		return nil, fmt.Errorf("unsupported credentials type %q (want service_account)", key.Type)
+	}
+	if key.ClientEmail == "" || key.PrivateKey == "" {
+		return nil, errors.New("service account key is missing client_email or private_key")
+	}
+	if key.TokenURI == "" {
+		key.TokenURI = googleTokenURL
+	}
+	return &key, nil
+}
+
+// parseRSAPrivateKey decodes a PEM key in PKCS#8 or PKCS#1 form.
+func parseRSAPrivateKey(pemKey string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(pemKey))
+	if block == nil {
+		return nil, errors.New("private_key is not PEM encoded")
+	}
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		rsaKey, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return nil, errors.New("private_key is not an RSA key")
+		}
+		return rsaKey, nil
+	}
+	return x509.ParsePKCS1PrivateKey(block.Bytes)
+}
+
+// signServiceAccountJWT builds the RS256 assertion for the token exchange.
+func signServiceAccountJWT(key *serviceAccountKey, signer *rsa.PrivateKey, scope string, now time.Time) (string, error) {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": key.PrivateKeyID})
+	claims, _ := json.Marshal(map[string]any{
+		"iss":   key.ClientEmail,
+		"scope": scope,
+		"aud":   key.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+
+	enc := base64.RawURLEncoding
+	unsigned := enc.EncodeToString(header) + "." + enc.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, signer, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign JWT: %w", err)
+	}
+	return unsigned + "." + enc.EncodeToString(sig), nil
+}
+
+// exchangeJWTAssertion trades a signed assertion for an access token.
+func exchangeJWTAssertion(ctx context.Context, tokenURI, assertion string) (string, time.Time, error) {
+	return requestOAuthToken(ctx, tokenURI, url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	})
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Vertex AI Endpoints
+// ═══════════════════════════════════════════════════════════════════════════
+
+const (
+	vertexDefaultLocation  = "us-central1"
+	vertexAnthropicVersion = "vertex-2023-10-16"
+)
+
+// WithProject sets the cloud project ID. For Google this enables Vertex AI
+// mode; combine with WithRegion to pick the location.
+func WithProject(project string) ClientOption {
+	return func(c *ProviderConfig) {
+		c.Project = project
+	}
+}
+
+// vertexAI addresses publisher models in one project/location and
+// authorizes requests with OAuth2 bearer tokens.
+type vertexAI struct {
+	baseURL  string
+	project  string
+	location string
+	tokens   TokenSource
+	headers  map[string]string
+}
+
+// vertexProject returns the Vertex AI project for config, or "" when the
+// provider should use the Gemini API. GOOGLE_GENAI_USE_VERTEXAI=true enables
+// Vertex AI with GOOGLE_CLOUD_PROJECT.
+func vertexProject(config ProviderConfig) string {
+	if config.Project != "" {
+		return config.Project
+	}
+	if use := strings.ToLower(os.Getenv("GOOGLE_GENAI_USE_VERTEXAI")); use == "true" || use == "1" {
+		return os.Getenv("GOOGLE_CLOUD_PROJECT")
+	}
+	return ""
+}
+
+func newVertexAI(config ProviderConfig, project string) *vertexAI {
+	location := config.Region
+	if location == "" {
+		location = os.Getenv("GOOGLE_CLOUD_LOCATION")
+	}
+	if location == "" {
+		location = vertexDefaultLocation
+	}
+
+	baseURL := config.BaseURL
+	if baseURL == "" {
+		if location == "global" {
+			baseURL = "https://aiplatform.googleapis.com/v1"
+		} else {
+			baseURL = "https://" + location + "-aiplatform.googleapis.com/v1"
+		}
+	}
+
+	tokens := config.TokenSource
+	if tokens == nil {
+		tokens = defaultVertexTokenSource()
+	}
+	return &vertexAI{baseURL: baseURL, project: project, location: location, tokens: tokens, headers: config.Headers}
+}
+
+// defaultVertexTokenSource loads the key file named by GOOGLE_APPLICATION_CREDENTIALS
+// on first use, so construction never fails.
+func defaultVertexTokenSource() TokenSource {
+	var (
+		mu sync.Mutex
+		ts TokenSource
+	)
+	return TokenSourceFunc(func(ctx context.Context) (string, error) {
+		mu.Lock()
+		if ts == nil {
+			path := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
+			if path == "" {
+				mu.Unlock()
+				return "", errors.New("GOOGLE_APPLICATION_CREDENTIALS not set (or use WithTokenSource)")
+			}
+			loaded, err := ServiceAccountTokenSourceFromFile(path)
+			if err != nil {
+				mu.Unlock()
+				return "", err
+			}
+			ts = loaded
+		}
+		current := ts
+		mu.Unlock()
+		return current.Token(ctx)
+	})
+}
+
+// modelURL returns the endpoint for method (e.g. "generateContent") on a publisher model.
+func (v *vertexAI) modelURL(publisher, model, method string) string {
+	return fmt.Sprintf("%s/projects/%s/locations/%s/publishers/%s/models/%s:%s",
+		v.baseURL, v.project, v.location, publisher, model, method)
+}
+
+// authorize sets the JSON content type, bearer token and custom headers.
+func (v *vertexAI) authorize(ctx context.Context, req *http.Request) error {
+	token, err := v.tokens.Token(ctx)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	for k, v := range v.headers {
+		req.Header.Set(k, v)
+	}
+	return nil
+}
+
+// vertexClaudeModelID converts an Anthropic snapshot ID to Vertex AI form:
+// "claude-sonnet-4-5-20250929" becomes "claude-sonnet-4-5@20250929".
+func vertexClaudeModelID(id string) string {
+	i := strings.LastIndexByte(id, '-')
+	if i < 0 || len(id)-i-1 != 8 || strings.Contains(id, "@") {
+		return id
+	}
+	for _, c := range id[i+1:] {
+		if c < '0' || c > '9' {
+			return id
+		}
+	}
+	return id[:i] + "@" + id[i+1:]
+}
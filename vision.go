@@ -17,6 +17,9 @@ import (
 type ImageInput struct {
 	URL    string // URL or base64 data URI
 	Detail string // "auto", "low", "high"
+
+	Width  int `json:"-"` // pixel width, set by the preprocessing pipeline
+	Height int `json:"-"` // pixel height, set by the preprocessing pipeline
 }
 
 // ImageDetail controls how the model processes the image.
@@ -47,7 +50,7 @@ func (b *Builder) ImageWithDetail(path string, detail ImageDetail) *Builder {
 		return b
 	}
 
-	b.images = append(b.images, ImageInput{
+	b.addImage(ImageInput{
 		URL:    dataURI,
 		Detail: string(detail),
 	})
@@ -55,6 +58,7 @@ func (b *Builder) ImageWithDetail(path string, detail ImageDetail) *Builder {
 }
 
 // ImageURL adds a remote image URL to the request.
+// Providers without native URL support fetch and inline it (see MediaFetcher).
 // Uses "auto" detail level by default.
 func (b *Builder) ImageURL(url string) *Builder {
 	return b.ImageURLWithDetail(url, ImageDetailAuto)
@@ -73,7 +77,7 @@ func (b *Builder) ImageURLWithDetail(url string, detail ImageDetail) *Builder {
 // Example: b.ImageBase64("iVBORw0...", "image/png")
 func (b *Builder) ImageBase64(data, mimeType string) *Builder {
 	dataURI := fmt.Sprintf("data:%s;base64,%s", mimeType, data)
-	b.images = append(b.images, ImageInput{
+	b.addImage(ImageInput{
 		URL:    dataURI,
 		Detail: string(ImageDetailAuto),
 	})
@@ -92,6 +96,16 @@ func (b *Builder) Images(paths ...string) *Builder {
 // Internal Helpers
 // ═══════════════════════════════════════════════════════════════════════════
 
+// addImage attaches an image, running the preprocessing pipeline when enabled.
+func (b *Builder) addImage(img ImageInput) {
+	processed, err := b.preprocessImage(img)
+	if err != nil {
+		fmt.Printf("%s Error preprocessing image: %v\n", colorRed("✗"), err)
+		processed = img
+	}
+	b.images = append(b.images, processed)
+}
+
 // fileToDataURI reads a file and converts it to a base64 data URI.
 func fileToDataURI(path string) (string, error) {
 	data, err := os.ReadFile(path)